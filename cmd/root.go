@@ -19,7 +19,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/data"
 	"github.com/dlvhdr/gh-dash/v4/git"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui"
 	"github.com/dlvhdr/gh-dash/v4/ui/markdown"
 	"github.com/dlvhdr/gh-dash/v4/utils"
@@ -57,7 +59,8 @@ func createModel(repoPath string, configPath string, debug bool) (ui.Model, *os.
 		var fileErr error
 		newConfigFile, fileErr := os.OpenFile("debug.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 		if fileErr == nil {
-			log.SetOutput(newConfigFile)
+			// Tokens are redacted, so debug.log can be shared in bug reports
+			log.SetOutput(providers.NewRedactingWriter(newConfigFile))
 			log.SetTimeFormat(time.Kitchen)
 			log.SetReportCaller(true)
 			log.SetLevel(log.DebugLevel)
@@ -77,6 +80,37 @@ func createModel(repoPath string, configPath string, debug bool) (ui.Model, *os.
 	return ui.NewModel(repoPath, configPath), loggerFile
 }
 
+// setupRecording records or replays the API responses, as asked by the
+// --record and --replay flags
+func setupRecording() error {
+	recordDir, err := rootCmd.Flags().GetString("record")
+	if err != nil {
+		return err
+	}
+	replayDir, err := rootCmd.Flags().GetString("replay")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay can't be used together")
+	case (recordDir != "" || replayDir != "") && config.IsFeatureEnabled(config.FF_MOCK_DATA):
+		return fmt.Errorf("--record and --replay can't be used with %s", config.FF_MOCK_DATA)
+	case recordDir != "":
+		return providers.RecordResponses(recordDir)
+	case replayDir != "":
+		// The GraphQL client requires a token, which the recordings don't need
+		if os.Getenv("GH_TOKEN") == "" && os.Getenv("GITHUB_TOKEN") == "" {
+			os.Setenv("GH_TOKEN", "replay")
+		}
+		// Only the recorded responses should be shown, not the cached ones
+		data.DisableResponseCache()
+		return providers.ReplayResponses(replayDir)
+	}
+	return nil
+}
+
 func buildVersion(version, commit, date, builtBy string) string {
 	result := version
 	if commit != "" {
@@ -122,6 +156,18 @@ func init() {
 		"passing this flag will allow writing debug output to debug.log",
 	)
 
+	rootCmd.Flags().String(
+		"record",
+		"",
+		"save the API responses into this directory, with tokens redacted, to replay them later",
+	)
+
+	rootCmd.Flags().String(
+		"replay",
+		"",
+		"answer the API requests with the responses recorded into this directory, without the network",
+	)
+
 	rootCmd.Flags().BoolP(
 		"help",
 		"h",
@@ -158,6 +204,11 @@ func init() {
 			defer logger.Close()
 		}
 
+		// The API clients are created once the TUI starts, after this
+		if err := setupRecording(); err != nil {
+			log.Fatal("Cannot set up recorded responses", err)
+		}
+
 		utils.InitTemplateHandler()
 
 		p := tea.NewProgram(
@@ -35,25 +35,28 @@ const (
 )
 
 type SectionConfig struct {
-	Title   string
-	Filters string
-	Limit   *int `yaml:"limit,omitempty"`
-	Type    *ViewType
+	Title    string
+	Filters  string
+	Limit    *int `yaml:"limit,omitempty"`
+	Type     *ViewType
+	Provider string `yaml:"provider,omitempty"`
 }
 
 type PrsSectionConfig struct {
-	Title   string
-	Filters string
-	Limit   *int            `yaml:"limit,omitempty"`
-	Layout  PrsLayoutConfig `yaml:"layout,omitempty"`
-	Type    *ViewType
+	Title    string
+	Filters  string
+	Limit    *int            `yaml:"limit,omitempty"`
+	Layout   PrsLayoutConfig `yaml:"layout,omitempty"`
+	Type     *ViewType
+	Provider string `yaml:"provider,omitempty"`
 }
 
 type IssuesSectionConfig struct {
-	Title   string
-	Filters string
-	Limit   *int               `yaml:"limit,omitempty"`
-	Layout  IssuesLayoutConfig `yaml:"layout,omitempty"`
+	Title    string
+	Filters  string
+	Limit    *int               `yaml:"limit,omitempty"`
+	Layout   IssuesLayoutConfig `yaml:"layout,omitempty"`
+	Provider string             `yaml:"provider,omitempty"`
 }
 
 type PreviewConfig struct {
@@ -100,19 +103,24 @@ type LayoutConfig struct {
 }
 
 type Defaults struct {
-	Preview                PreviewConfig `yaml:"preview"`
-	PrsLimit               int           `yaml:"prsLimit"`
-	PrApproveComment       string        `yaml:"prApproveComment,omitempty"`
-	IssuesLimit            int           `yaml:"issuesLimit"`
-	View                   ViewType      `yaml:"view"`
-	Layout                 LayoutConfig  `yaml:"layout,omitempty"`
-	RefetchIntervalMinutes int           `yaml:"refetchIntervalMinutes,omitempty"`
-	DateFormat             string        `yaml:"dateFormat,omitempty"`
+	Preview                    PreviewConfig `yaml:"preview"`
+	PrsLimit                   int           `yaml:"prsLimit"`
+	PrApproveComment           string        `yaml:"prApproveComment,omitempty"`
+	IssuesLimit                int           `yaml:"issuesLimit"`
+	View                       ViewType      `yaml:"view"`
+	Layout                     LayoutConfig  `yaml:"layout,omitempty"`
+	RefetchIntervalMinutes     int           `yaml:"refetchIntervalMinutes,omitempty"`
+	FullRefetchIntervalMinutes int           `yaml:"fullRefetchIntervalMinutes,omitempty"`
+	RequestTimeoutSeconds      int           `yaml:"requestTimeoutSeconds,omitempty"`
+	DateFormat                 string        `yaml:"dateFormat,omitempty"`
 }
 
 type RepoConfig struct {
 	BranchesRefetchIntervalSeconds int `yaml:"branchesRefetchIntervalSeconds,omitempty"`
 	PrsRefetchIntervalSeconds      int `yaml:"prsRefetchIntervalSeconds,omitempty"`
+	// Remotes are the git remotes to prefer, in order, for detecting the provider
+	// and the current repo. Defaults to upstream, github, then origin
+	Remotes []string `yaml:"remotes,omitempty"`
 }
 
 type Keybinding struct {
@@ -221,11 +229,21 @@ type ThemeConfig struct {
 }
 
 type ProviderConfig struct {
-	Type         string `yaml:"type"`
-	Organization string `yaml:"organization,omitempty"`
-	Project      string `yaml:"project,omitempty"`
-	BaseURL      string `yaml:"baseUrl,omitempty"`
-	Token        string `yaml:"token,omitempty"`
+	Type          string `yaml:"type"`
+	Organization  string `yaml:"organization,omitempty"`
+	Project       string `yaml:"project,omitempty"`
+	Repository    string `yaml:"repository,omitempty"`
+	BaseURL       string `yaml:"baseUrl,omitempty"`
+	Token         string `yaml:"token,omitempty"`
+	MergeStrategy string `yaml:"mergeStrategy,omitempty"`
+	// TokenCommand prints the token, e.g. a password manager CLI
+	TokenCommand string `yaml:"tokenCommand,omitempty"`
+	// TokenFile contains the token, and must only be readable by its owner
+	TokenFile string `yaml:"tokenFile,omitempty"`
+	// GitCredential reads the token from the git credential helper
+	GitCredential bool `yaml:"gitCredential,omitempty"`
+	// Command launches the executable of a plugin provider
+	Command string `yaml:"command,omitempty"`
 }
 
 type Config struct {
@@ -241,6 +259,11 @@ type Config struct {
 	ShowAuthorIcons        bool                  `yaml:"showAuthorIcons"`
 	SmartFilteringAtLaunch bool                  `yaml:"smartFilteringAtLaunch" default:"true"`
 	Provider               *ProviderConfig       `yaml:"provider,omitempty"`
+	// Providers are named provider profiles, which sections select with their provider
+	Providers map[string]ProviderConfig `yaml:"providers,omitempty"`
+	// Hosts maps the hostnames of self-hosted forges to their provider type, for
+	// remotes that aren't detected from their hostname
+	Hosts map[string]string `yaml:"hosts,omitempty"`
 }
 
 type configError struct {
@@ -258,11 +281,13 @@ func (parser ConfigParser) getDefaultConfig() Config {
 				Open:  true,
 				Width: 50,
 			},
-			PrsLimit:               20,
-			PrApproveComment:       "LGTM",
-			IssuesLimit:            20,
-			View:                   PRsView,
-			RefetchIntervalMinutes: 30,
+			PrsLimit:                   20,
+			PrApproveComment:           "LGTM",
+			IssuesLimit:                20,
+			View:                       PRsView,
+			RefetchIntervalMinutes:     30,
+			FullRefetchIntervalMinutes: 60,
+			RequestTimeoutSeconds:      30,
 			Layout: LayoutConfig{
 				Prs: PrsLayoutConfig{
 					UpdatedAt: ColumnConfig{
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
 func (cfg Config) GetFullScreenDiffPagerEnv() []string {
@@ -28,20 +30,40 @@ func (cfg Config) GetFullScreenDiffPagerEnv() []string {
 	return env
 }
 
+// RequestContext returns a context for fetching from the provider, which times
+// out after RequestTimeoutSeconds unless it is 0
+func (d Defaults) RequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if d.RequestTimeoutSeconds <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, time.Duration(d.RequestTimeoutSeconds)*time.Second)
+}
+
+// FullRefetchInterval returns how long refetches only fetch what changed before
+// reloading the sections entirely. When it is 0 they always reload them
+func (d Defaults) FullRefetchInterval() time.Duration {
+	if d.FullRefetchIntervalMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(d.FullRefetchIntervalMinutes) * time.Minute
+}
+
 func (cfg PrsSectionConfig) ToSectionConfig() SectionConfig {
 	return SectionConfig{
-		Title:   cfg.Title,
-		Filters: cfg.Filters,
-		Limit:   cfg.Limit,
-		Type:    cfg.Type,
+		Title:    cfg.Title,
+		Filters:  cfg.Filters,
+		Limit:    cfg.Limit,
+		Type:     cfg.Type,
+		Provider: cfg.Provider,
 	}
 }
 
 func (cfg IssuesSectionConfig) ToSectionConfig() SectionConfig {
 	return SectionConfig{
-		Title:   cfg.Title,
-		Filters: cfg.Filters,
-		Limit:   cfg.Limit,
+		Title:    cfg.Title,
+		Filters:  cfg.Filters,
+		Limit:    cfg.Limit,
+		Provider: cfg.Provider,
 	}
 }
 
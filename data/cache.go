@@ -0,0 +1,222 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/cli/go-gh/v2/pkg/auth"
+
+	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// The first page of every configured section is cached on disk, so the rows of
+// the last run are shown at startup while the sections are fetched again. Each
+// section query of each account has its own file under $XDG_CACHE_HOME/gh-dash,
+// and files that weren't written for cacheMaxAge are removed
+
+const DEFAULT_XDG_CACHE_DIRNAME = ".cache"
+
+// cacheMaxAge is how long the responses of sections that aren't shown anymore are kept
+const cacheMaxAge = 30 * 24 * time.Hour
+
+// evictOldCacheFiles removes the old cache files once per run
+var evictOldCacheFiles = sync.OnceFunc(func() {
+	dir, err := cacheDir()
+	if err != nil {
+		return
+	}
+	for _, kind := range []string{"prs", "issues"} {
+		files, err := filepath.Glob(filepath.Join(dir, kind+"-*.json"))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil || time.Since(info.ModTime()) < cacheMaxAge {
+				continue
+			}
+			if err := os.Remove(file); err != nil {
+				log.Debug("Failed removing an old cached response", "file", file, "err", err)
+			}
+		}
+	}
+})
+
+var cacheDisabled bool
+
+// DisableResponseCache stops the responses from being read from and written to
+// the cache, for runs whose responses shouldn't mix with the usual ones
+func DisableResponseCache() {
+	cacheDisabled = true
+}
+
+// cachedResponse is a response as saved in a cache file
+type cachedResponse[T any] struct {
+	SavedAt  time.Time `json:"savedAt"`
+	Provider string    `json:"provider,omitempty"`
+	Account  string    `json:"account"`
+	Query    string    `json:"query"`
+	Limit    int       `json:"limit"`
+	Response T         `json:"response"`
+}
+
+// SaveCachedPullRequests caches the first page of pull requests of a query
+func SaveCachedPullRequests(provider string, query string, limit int, res PullRequestsResponse) {
+	res.Warnings = nil
+	saveCachedResponse("prs", provider, query, limit, res)
+}
+
+// LoadCachedPullRequests returns the cached first page of pull requests of a
+// query and when it was fetched
+func LoadCachedPullRequests(provider string, query string, limit int) (PullRequestsResponse, time.Time, bool) {
+	return loadCachedResponse[PullRequestsResponse]("prs", provider, query, limit)
+}
+
+// SaveCachedIssues caches the first page of issues of a query
+func SaveCachedIssues(provider string, query string, limit int, res IssuesResponse) {
+	res.Warnings = nil
+	saveCachedResponse("issues", provider, query, limit, res)
+}
+
+// LoadCachedIssues returns the cached first page of issues of a query and when
+// it was fetched
+func LoadCachedIssues(provider string, query string, limit int) (IssuesResponse, time.Time, bool) {
+	return loadCachedResponse[IssuesResponse]("issues", provider, query, limit)
+}
+
+func cacheDir() (string, error) {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		cacheDir = filepath.Join(homeDir, DEFAULT_XDG_CACHE_DIRNAME)
+	}
+	return filepath.Join(cacheDir, config.DashDir), nil
+}
+
+// cacheAccount identifies the account responses of the named provider are
+// cached under, see providers.ProviderManager.Account. Without the provider
+// system it is the one gh is signed in to
+func cacheAccount(provider string) (string, error) {
+	if globalProviderManager == nil {
+		if provider != "" {
+			return "", fmt.Errorf("provider %q isn't initialized", provider)
+		}
+		return ghCacheAccount()
+	}
+	return globalProviderManager.Account(provider)
+}
+
+var ghCacheAccount = sync.OnceValues(func() (string, error) {
+	login, err := CurrentLoginName()
+	if err != nil {
+		return "", err
+	}
+	if login == "" {
+		return "", fmt.Errorf("no user is signed in to gh")
+	}
+	host, _ := auth.DefaultHost()
+	return fmt.Sprintf("%s\n%s\n\n%s", providers.GitHub, host, login), nil
+})
+
+func cacheFilePath(kind string, account string, query string, limit int) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s\n%d", kind, account, query, limit)))
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", kind, hex.EncodeToString(hash[:])[:16])), nil
+}
+
+func saveCachedResponse[T any](kind string, provider string, query string, limit int, res T) {
+	if cacheDisabled || config.IsFeatureEnabled(config.FF_MOCK_DATA) {
+		return
+	}
+	evictOldCacheFiles()
+	account, err := cacheAccount(provider)
+	if err != nil {
+		log.Debug("Failed caching the response", "query", query, "err", err)
+		return
+	}
+	path, err := cacheFilePath(kind, account, query, limit)
+	if err != nil {
+		log.Debug("Failed caching the response", "query", query, "err", err)
+		return
+	}
+	data, err := json.Marshal(cachedResponse[T]{
+		SavedAt:  time.Now(),
+		Provider: provider,
+		Account:  account,
+		Query:    query,
+		Limit:    limit,
+		Response: res,
+	})
+	if err == nil {
+		err = writeFileAtomically(path, data)
+	}
+	if err != nil {
+		log.Debug("Failed caching the response", "query", query, "err", err)
+		return
+	}
+	log.Debug("Cached the response", "query", query, "file", path)
+}
+
+func loadCachedResponse[T any](kind string, provider string, query string, limit int) (T, time.Time, bool) {
+	var cached cachedResponse[T]
+	if cacheDisabled || config.IsFeatureEnabled(config.FF_MOCK_DATA) {
+		return cached.Response, time.Time{}, false
+	}
+	account, err := cacheAccount(provider)
+	if err != nil {
+		log.Debug("Not reading the cached response", "query", query, "err", err)
+		return cached.Response, time.Time{}, false
+	}
+	path, err := cacheFilePath(kind, account, query, limit)
+	if err != nil {
+		return cached.Response, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug("Failed reading the cached response", "file", path, "err", err)
+		}
+		return cached.Response, time.Time{}, false
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		log.Debug("Ignoring an invalid cached response", "file", path, "err", err)
+		var empty T
+		return empty, time.Time{}, false
+	}
+	return cached.Response, cached.SavedAt, true
+}
+
+// writeFileAtomically replaces a file with a temporary one, so that concurrent
+// runs never read a partly written file
+func writeFileAtomically(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
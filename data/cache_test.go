@@ -0,0 +1,100 @@
+package data_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/data"
+)
+
+// initGitLabProviders signs the current provider and the work profile in to a
+// fake GitLab, as the users their tokens belong to
+func initGitLabProviders(t *testing.T, server *httptest.Server, currentToken string) {
+	t.Helper()
+	require.NoError(t, data.InitProviders(&config.Config{
+		Provider: &config.ProviderConfig{Type: "gitlab", BaseURL: server.URL, Token: currentToken},
+		Providers: map[string]config.ProviderConfig{
+			"work": {Type: "gitlab", BaseURL: server.URL, Token: "bob-token"},
+		},
+	}, ""))
+}
+
+func TestCachedResponses(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logins := map[string]string{"alice-token": "alice", "bob-token": "bob"}
+		_ = json.NewEncoder(w).Encode(map[string]string{"username": logins[r.Header.Get("PRIVATE-TOKEN")]})
+	}))
+	t.Cleanup(server.Close)
+	initGitLabProviders(t, server, "alice-token")
+
+	// Files that weren't written for a month are removed
+	oldFile := filepath.Join(cacheHome, "gh-dash", "prs-0123456789abcdef.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(oldFile), 0o700))
+	require.NoError(t, os.WriteFile(oldFile, []byte("{}"), 0o600))
+	monthAgo := time.Now().Add(-31 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(oldFile, monthAgo, monthAgo))
+
+	_, _, ok := data.LoadCachedPullRequests("", "is:open author:@me", 20)
+	require.False(t, ok)
+
+	before := time.Now()
+	pr := data.PullRequestData{Number: 7, Title: "Add search", State: "OPEN", UpdatedAt: before.Add(-time.Hour).UTC()}
+	pr.Author.Login = "alice"
+	data.SaveCachedPullRequests("", "is:open author:@me", 20, data.PullRequestsResponse{
+		Prs:        []data.PullRequestData{pr},
+		TotalCount: 31,
+		PageInfo:   data.PageInfo{HasNextPage: true, EndCursor: "abc"},
+		Warnings:   []string{"a warning of that fetch"},
+	})
+	data.SaveCachedIssues("work", "is:open", 20, data.IssuesResponse{
+		Issues:     []data.IssueData{{Number: 12, Title: "Search is slow"}},
+		TotalCount: 1,
+	})
+
+	files, err := filepath.Glob(filepath.Join(cacheHome, "gh-dash", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.NotContains(t, files, oldFile)
+
+	res, savedAt, ok := data.LoadCachedPullRequests("", "is:open author:@me", 20)
+	require.True(t, ok)
+	require.False(t, savedAt.Before(before))
+	require.Equal(t, 31, res.TotalCount)
+	require.Equal(t, []data.PullRequestData{pr}, res.Prs)
+	require.Empty(t, res.Warnings)
+
+	// Every query, limit and account has its own cache
+	_, _, ok = data.LoadCachedPullRequests("", "is:open author:@me", 50)
+	require.False(t, ok)
+	_, _, ok = data.LoadCachedPullRequests("work", "is:open author:@me", 20)
+	require.False(t, ok)
+	_, _, ok = data.LoadCachedIssues("", "is:open", 20)
+	require.False(t, ok)
+	issues, _, ok := data.LoadCachedIssues("work", "is:open", 20)
+	require.True(t, ok)
+	require.Equal(t, "Search is slow", issues.Issues[0].Title)
+
+	// The cache follows the account rather than the profile name, which is empty
+	// for the provider detected in every clone
+	initGitLabProviders(t, server, "bob-token")
+	_, _, ok = data.LoadCachedPullRequests("", "is:open author:@me", 20)
+	require.False(t, ok)
+	_, _, ok = data.LoadCachedIssues("", "is:open", 20)
+	require.True(t, ok)
+
+	for _, file := range files {
+		require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+	}
+	_, _, ok = data.LoadCachedIssues("work", "is:open", 20)
+	require.False(t, ok)
+}
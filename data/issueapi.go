@@ -1,6 +1,8 @@
 package data
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	gh "github.com/cli/go-gh/v2/pkg/api"
 	graphql "github.com/cli/shurcooL-graphql"
 
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/theme"
 )
 
@@ -92,10 +95,10 @@ func makeIssuesQuery(query string) string {
 	return fmt.Sprintf("is:issue %s sort:updated", query)
 }
 
-func FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+func FetchIssues(ctx context.Context, provider string, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
 	// Try using the provider system first
-	if response, err := FetchIssuesWithProvider(query, limit, pageInfo); err == nil {
-		return response, nil
+	if response, err := FetchIssuesWithProvider(ctx, provider, query, limit, pageInfo); !errors.Is(err, errProviderFallback) {
+		return response, err
 	}
 
 	// Fallback to original GitHub implementation
@@ -113,6 +116,7 @@ func FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, e
 			IssueCount int
 			PageInfo   PageInfo
 		} `graphql:"search(type: ISSUE, first: $limit, after: $endCursor, query: $query)"`
+		RateLimit providers.RateLimit
 	}
 	var endCursor *string
 	if pageInfo != nil {
@@ -124,10 +128,11 @@ func FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, e
 		"endCursor": (*graphql.String)(endCursor),
 	}
 	log.Debug("Fetching issues", "query", query, "limit", limit, "endCursor", endCursor)
-	err = client.Query("SearchIssues", &queryResult, variables)
+	err = client.QueryWithContext(ctx, "SearchIssues", &queryResult, variables)
 	if err != nil {
 		return IssuesResponse{}, err
 	}
+	fallbackRateLimits.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched issues", "query", query, "count", queryResult.Search.IssueCount)
 
 	issues := make([]IssueData, 0, len(queryResult.Search.Nodes))
@@ -145,8 +150,64 @@ func FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, e
 	}, nil
 }
 
+// FetchUpdatedIssues fetches the issues matching updatedQuery, those updated
+// since the last fetch of a section, along with the number of issues matching
+// query, the one of the section. See FetchUpdatedPullRequests
+func FetchUpdatedIssues(ctx context.Context, provider string, query string, updatedQuery string, limit int) (IssuesResponse, int, error) {
+	if response, totalCount, err := FetchUpdatedIssuesWithProvider(ctx, provider, query, updatedQuery, limit); !errors.Is(err, errProviderFallback) {
+		return response, totalCount, err
+	}
+
+	client, err := gh.DefaultGraphQLClient()
+	if err != nil {
+		return IssuesResponse{}, 0, err
+	}
+
+	// The count comes from an alias of the search, in the same request
+	var queryResult struct {
+		Search struct {
+			Nodes []struct {
+				Issue IssueData `graphql:"... on Issue"`
+			}
+			IssueCount int
+			PageInfo   PageInfo
+		} `graphql:"search(type: ISSUE, first: $limit, query: $updatedQuery)"`
+		Matching struct {
+			IssueCount int
+		} `graphql:"matching: search(type: ISSUE, first: 1, query: $query)"`
+		RateLimit providers.RateLimit
+	}
+	variables := map[string]interface{}{
+		"query":        graphql.String(makeIssuesQuery(query)),
+		"updatedQuery": graphql.String(makeIssuesQuery(updatedQuery)),
+		"limit":        graphql.Int(limit),
+	}
+	log.Debug("Fetching updated issues", "query", updatedQuery, "limit", limit)
+	err = client.QueryWithContext(ctx, "SearchUpdatedIssues", &queryResult, variables)
+	if err != nil {
+		return IssuesResponse{}, 0, err
+	}
+	fallbackRateLimits.RecordRateLimit(queryResult.RateLimit)
+	log.Debug("Successfully fetched updated issues", "count", queryResult.Search.IssueCount, "matching", queryResult.Matching.IssueCount)
+
+	issues := make([]IssueData, 0, len(queryResult.Search.Nodes))
+	for _, node := range queryResult.Search.Nodes {
+		if node.Issue.Repository.IsArchived {
+			continue
+		}
+		issues = append(issues, node.Issue)
+	}
+
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: queryResult.Search.IssueCount,
+		PageInfo:   queryResult.Search.PageInfo,
+	}, queryResult.Matching.IssueCount, nil
+}
+
 type IssuesResponse struct {
 	Issues     []IssueData
 	TotalCount int
 	PageInfo   PageInfo
+	Warnings   []string
 }
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,6 +15,7 @@ import (
 	"github.com/shurcooL/githubv4"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/theme"
 )
 
@@ -34,6 +37,7 @@ type PullRequestData struct {
 	Deletions         int
 	HeadRefName       string
 	BaseRefName       string
+	IsCrossRepository bool
 	HeadRepository    struct {
 		Name string
 	}
@@ -78,27 +82,31 @@ type StatusContext struct {
 }
 
 type Commits struct {
-	Nodes []struct {
-		Commit struct {
-			Deployments struct {
-				Nodes []struct {
-					Task        graphql.String
-					Description graphql.String
-				}
-			} `graphql:"deployments(last: 10)"`
-			StatusCheckRollup struct {
-				Contexts struct {
-					TotalCount graphql.Int
-					Nodes      []struct {
-						Typename      graphql.String `graphql:"__typename"`
-						CheckRun      CheckRun       `graphql:"... on CheckRun"`
-						StatusContext StatusContext  `graphql:"... on StatusContext"`
-					}
-				} `graphql:"contexts(last: 20)"`
+	Nodes      []CommitNode
+	TotalCount int
+}
+
+type CommitNode struct {
+	Commit struct {
+		Deployments struct {
+			Nodes []struct {
+				Task        graphql.String
+				Description graphql.String
 			}
+		} `graphql:"deployments(last: 10)"`
+		StatusCheckRollup struct {
+			Contexts struct {
+				TotalCount graphql.Int
+				Nodes      []CheckContext
+			} `graphql:"contexts(last: 20)"`
 		}
 	}
-	TotalCount int
+}
+
+type CheckContext struct {
+	Typename      graphql.String `graphql:"__typename"`
+	CheckRun      CheckRun       `graphql:"... on CheckRun"`
+	StatusContext StatusContext  `graphql:"... on StatusContext"`
 }
 
 type Comment struct {
@@ -144,15 +152,17 @@ type Reviews struct {
 }
 
 type ReviewThreads struct {
-	Nodes []struct {
-		Id           string
-		IsOutdated   bool
-		OriginalLine int
-		StartLine    int
-		Line         int
-		Path         string
-		Comments     ReviewComments `graphql:"comments(first: 10)"`
-	}
+	Nodes []ReviewThread
+}
+
+type ReviewThread struct {
+	Id           string
+	IsOutdated   bool
+	OriginalLine int
+	StartLine    int
+	Line         int
+	Path         string
+	Comments     ReviewComments `graphql:"comments(first: 10)"`
 }
 
 type ChangedFile struct {
@@ -169,9 +179,11 @@ type ChangedFiles struct {
 
 type ReviewRequests struct {
 	TotalCount int
-	Nodes      []struct {
-		AsCodeOwner bool `graphql:"asCodeOwner"`
-	}
+	Nodes      []ReviewRequest
+}
+
+type ReviewRequest struct {
+	AsCodeOwner bool `graphql:"asCodeOwner"`
 }
 
 type PRLabel struct {
@@ -231,17 +243,14 @@ type PullRequestsResponse struct {
 	Prs        []PullRequestData
 	TotalCount int
 	PageInfo   PageInfo
+	Warnings   []string
 }
 
 var client *gh.GraphQLClient
 
-func FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
-	// Try using the provider system first
-	if response, err := FetchPullRequestsWithProvider(query, limit, pageInfo); err == nil {
-		return response, nil
-	}
-
-	// Fallback to original GitHub implementation
+// searchClient returns the client searching pull requests, which talks to the
+// mock server when FF_MOCK_DATA is enabled
+func searchClient() (*gh.GraphQLClient, error) {
 	var err error
 	if client == nil {
 		if config.IsFeatureEnabled(config.FF_MOCK_DATA) {
@@ -252,7 +261,21 @@ func FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequest
 			client, err = gh.DefaultGraphQLClient()
 		}
 	}
+	return client, err
+}
+
+// fallbackRateLimits tracks the budget of the GraphQL queries that don't go
+// through a provider
+var fallbackRateLimits providers.RateLimitTracker
+
+func FetchPullRequests(ctx context.Context, provider string, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	// Try using the provider system first
+	if response, err := FetchPullRequestsWithProvider(ctx, provider, query, limit, pageInfo); !errors.Is(err, errProviderFallback) {
+		return response, err
+	}
 
+	// Fallback to original GitHub implementation
+	client, err := searchClient()
 	if err != nil {
 		return PullRequestsResponse{}, err
 	}
@@ -265,6 +288,7 @@ func FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequest
 			IssueCount int
 			PageInfo   PageInfo
 		} `graphql:"search(type: ISSUE, first: $limit, after: $endCursor, query: $query)"`
+		RateLimit providers.RateLimit
 	}
 	var endCursor *string
 	if pageInfo != nil {
@@ -276,10 +300,11 @@ func FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequest
 		"endCursor": (*graphql.String)(endCursor),
 	}
 	log.Debug("Fetching PRs", "query", query, "limit", limit, "endCursor", endCursor)
-	err = client.Query("SearchPullRequests", &queryResult, variables)
+	err = client.QueryWithContext(ctx, "SearchPullRequests", &queryResult, variables)
 	if err != nil {
 		return PullRequestsResponse{}, err
 	}
+	fallbackRateLimits.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched PRs", "count", queryResult.Search.IssueCount)
 
 	prs := make([]PullRequestData, 0, len(queryResult.Search.Nodes))
@@ -297,10 +322,66 @@ func FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequest
 	}, nil
 }
 
-func FetchPullRequest(prUrl string) (PullRequestData, error) {
+// FetchUpdatedPullRequests fetches the pull requests matching updatedQuery, those
+// updated since the last fetch of a section, along with the number of pull
+// requests matching query, the one of the section. The count tells whether pull
+// requests stopped matching the section, see MergeUpdatedRows
+func FetchUpdatedPullRequests(ctx context.Context, provider string, query string, updatedQuery string, limit int) (PullRequestsResponse, int, error) {
+	if response, totalCount, err := FetchUpdatedPullRequestsWithProvider(ctx, provider, query, updatedQuery, limit); !errors.Is(err, errProviderFallback) {
+		return response, totalCount, err
+	}
+
+	client, err := searchClient()
+	if err != nil {
+		return PullRequestsResponse{}, 0, err
+	}
+
+	// The count comes from an alias of the search, in the same request
+	var queryResult struct {
+		Search struct {
+			Nodes []struct {
+				PullRequest PullRequestData `graphql:"... on PullRequest"`
+			}
+			IssueCount int
+			PageInfo   PageInfo
+		} `graphql:"search(type: ISSUE, first: $limit, query: $updatedQuery)"`
+		Matching struct {
+			IssueCount int
+		} `graphql:"matching: search(type: ISSUE, first: 1, query: $query)"`
+		RateLimit providers.RateLimit
+	}
+	variables := map[string]interface{}{
+		"query":        graphql.String(makePullRequestsQuery(query)),
+		"updatedQuery": graphql.String(makePullRequestsQuery(updatedQuery)),
+		"limit":        graphql.Int(limit),
+	}
+	log.Debug("Fetching updated PRs", "query", updatedQuery, "limit", limit)
+	err = client.QueryWithContext(ctx, "SearchUpdatedPullRequests", &queryResult, variables)
+	if err != nil {
+		return PullRequestsResponse{}, 0, err
+	}
+	fallbackRateLimits.RecordRateLimit(queryResult.RateLimit)
+	log.Debug("Successfully fetched updated PRs", "count", queryResult.Search.IssueCount, "matching", queryResult.Matching.IssueCount)
+
+	prs := make([]PullRequestData, 0, len(queryResult.Search.Nodes))
+	for _, node := range queryResult.Search.Nodes {
+		if node.PullRequest.Repository.IsArchived {
+			continue
+		}
+		prs = append(prs, node.PullRequest)
+	}
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: queryResult.Search.IssueCount,
+		PageInfo:   queryResult.Search.PageInfo,
+	}, queryResult.Matching.IssueCount, nil
+}
+
+func FetchPullRequest(ctx context.Context, provider string, prUrl string) (PullRequestData, error) {
 	// Try using the provider system first
-	if response, err := FetchPullRequestWithProvider(prUrl); err == nil {
-		return response, nil
+	if response, err := FetchPullRequestWithProvider(ctx, provider, prUrl); !errors.Is(err, errProviderFallback) {
+		return response, err
 	}
 
 	// Fallback to original GitHub implementation
@@ -315,6 +396,7 @@ func FetchPullRequest(prUrl string) (PullRequestData, error) {
 		Resource struct {
 			PullRequest PullRequestData `graphql:"... on PullRequest"`
 		} `graphql:"resource(url: $url)"`
+		RateLimit providers.RateLimit
 	}
 	parsedUrl, err := url.Parse(prUrl)
 	if err != nil {
@@ -324,10 +406,11 @@ func FetchPullRequest(prUrl string) (PullRequestData, error) {
 		"url": githubv4.URI{URL: parsedUrl},
 	}
 	log.Debug("Fetching PR", "url", prUrl)
-	err = client.Query("FetchPullRequest", &queryResult, variables)
+	err = client.QueryWithContext(ctx, "FetchPullRequest", &queryResult, variables)
 	if err != nil {
 		return PullRequestData{}, err
 	}
+	fallbackRateLimits.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched PR", "url", prUrl)
 
 	return queryResult.Resource.PullRequest, nil
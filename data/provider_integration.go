@@ -1,7 +1,12 @@
 package data
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/charmbracelet/log"
+	graphql "github.com/cli/shurcooL-graphql"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/providers"
@@ -25,6 +30,52 @@ func GetCurrentProvider() providers.GitProvider {
 	return globalProviderManager.GetCurrentProvider()
 }
 
+// GetProvider returns the provider of a named profile, or the current provider
+// when the name is empty. It is nil when the provider system isn't initialized
+func GetProvider(name string) (providers.GitProvider, error) {
+	if globalProviderManager == nil {
+		if name != "" {
+			return nil, fmt.Errorf("provider %q isn't initialized", name)
+		}
+		return nil, nil
+	}
+	return globalProviderManager.GetProvider(name)
+}
+
+// GetCapabilities returns the capabilities of the named provider. Without the
+// provider system everything goes through gh, which performs every action
+func GetCapabilities(providerName string) providers.Capabilities {
+	provider, err := GetProvider(providerName)
+	if err != nil || provider == nil {
+		return providers.GitHubCapabilities()
+	}
+	return provider.Capabilities()
+}
+
+// GetMergeStrategy returns the merge strategy configured for the named provider,
+// empty when it merges with the default of the repository
+func GetMergeStrategy(providerName string) string {
+	if globalProviderManager == nil {
+		return ""
+	}
+	return globalProviderManager.MergeStrategy(providerName)
+}
+
+// GetRateLimit returns the API budget the named provider last reported. Without
+// the provider system it is the one of the GraphQL queries
+func GetRateLimit(providerName string) (providers.RateLimit, bool) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return providers.RateLimit{}, false
+	}
+	if reporter, ok := provider.(providers.RateLimitReporter); ok {
+		if rateLimit, ok := reporter.RateLimit(); ok {
+			return rateLimit, true
+		}
+	}
+	return fallbackRateLimits.RateLimit()
+}
+
 // GetProviderInfo returns information about the current provider
 func GetProviderInfo() (providers.ProviderType, providers.AuthInfo, error) {
 	if globalProviderManager == nil {
@@ -51,6 +102,7 @@ func convertProviderPRToData(pr providers.PullRequestData) PullRequestData {
 		Deletions:         pr.Deletions,
 		HeadRefName:       pr.HeadRefName,
 		BaseRefName:       pr.BaseRefName,
+		IsCrossRepository: pr.IsCrossRepository,
 		HeadRepository:    pr.HeadRepository,
 		HeadRef:           pr.HeadRef,
 		Repository: Repository{
@@ -60,16 +112,116 @@ func convertProviderPRToData(pr providers.PullRequestData) PullRequestData {
 		},
 		IsDraft:          pr.IsDraft,
 		MergeStateStatus: MergeStateStatus(pr.MergeStateStatus),
-		// Initialize complex nested fields to avoid conversion issues
-		Assignees:      Assignees{},
-		Comments:       Comments{},
-		Reviews:        Reviews{},
-		ReviewThreads:  ReviewThreads{},
-		ReviewRequests: ReviewRequests{},
-		Files:          ChangedFiles{},
-		Commits:        Commits{},
-		Labels:         PRLabels{},
+		Assignees:        convertProviderAssignees(pr.Assignees),
+		Comments:         convertProviderComments(pr.Comments),
+		Reviews:          convertProviderReviews(pr.Reviews),
+		ReviewThreads:    convertProviderReviewThreads(pr.ReviewThreads),
+		ReviewRequests:   convertProviderReviewRequests(pr.ReviewRequests),
+		Files:            convertProviderFiles(pr.Files),
+		Commits:          convertProviderCommits(pr.Commits),
+		Labels:           PRLabels{Nodes: convertProviderLabels(pr.Labels.Nodes)},
+	}
+}
+
+func convertProviderAssignees(assignees providers.Assignees) Assignees {
+	res := Assignees{Nodes: make([]Assignee, 0, len(assignees.Nodes))}
+	for _, assignee := range assignees.Nodes {
+		res.Nodes = append(res.Nodes, Assignee{Login: assignee.Login})
+	}
+	return res
+}
+
+func convertProviderComments(comments providers.Comments) Comments {
+	res := Comments{TotalCount: comments.TotalCount, Nodes: make([]Comment, 0, len(comments.Nodes))}
+	for _, comment := range comments.Nodes {
+		res.Nodes = append(res.Nodes, Comment{
+			Author:    comment.Author,
+			Body:      comment.Body,
+			UpdatedAt: comment.UpdatedAt,
+		})
+	}
+	return res
+}
+
+func convertProviderReviews(reviews providers.Reviews) Reviews {
+	res := Reviews{TotalCount: reviews.TotalCount, Nodes: make([]Review, 0, len(reviews.Nodes))}
+	for _, review := range reviews.Nodes {
+		res.Nodes = append(res.Nodes, Review{
+			Author:    review.Author,
+			Body:      review.Body,
+			State:     review.State,
+			UpdatedAt: review.UpdatedAt,
+		})
 	}
+	return res
+}
+
+func convertProviderReviewThreads(threads providers.ReviewThreads) ReviewThreads {
+	res := ReviewThreads{Nodes: make([]ReviewThread, 0, len(threads.Nodes))}
+	for _, thread := range threads.Nodes {
+		comments := ReviewComments{TotalCount: thread.Comments.TotalCount}
+		for _, comment := range thread.Comments.Nodes {
+			comments.Nodes = append(comments.Nodes, ReviewComment(comment))
+		}
+		res.Nodes = append(res.Nodes, ReviewThread{
+			Id:           thread.Id,
+			IsOutdated:   thread.IsOutdated,
+			OriginalLine: thread.OriginalLine,
+			StartLine:    thread.StartLine,
+			Line:         thread.Line,
+			Path:         thread.Path,
+			Comments:     comments,
+		})
+	}
+	return res
+}
+
+func convertProviderReviewRequests(requests providers.ReviewRequests) ReviewRequests {
+	res := ReviewRequests{TotalCount: requests.TotalCount, Nodes: make([]ReviewRequest, 0, len(requests.Nodes))}
+	for _, request := range requests.Nodes {
+		res.Nodes = append(res.Nodes, ReviewRequest(request))
+	}
+	return res
+}
+
+func convertProviderFiles(files providers.ChangedFiles) ChangedFiles {
+	res := ChangedFiles{TotalCount: files.TotalCount, Nodes: make([]ChangedFile, 0, len(files.Nodes))}
+	for _, file := range files.Nodes {
+		res.Nodes = append(res.Nodes, ChangedFile(file))
+	}
+	return res
+}
+
+func convertProviderCommits(commits providers.Commits) Commits {
+	res := Commits{TotalCount: commits.TotalCount, Nodes: make([]CommitNode, 0, len(commits.Nodes))}
+	for _, commit := range commits.Nodes {
+		var node CommitNode
+		contexts := commit.Commit.StatusCheckRollup.Contexts
+		node.Commit.StatusCheckRollup.Contexts.TotalCount = graphql.Int(contexts.TotalCount)
+		for _, context := range contexts.Nodes {
+			var check CheckContext
+			check.Typename = graphql.String(context.Typename)
+			check.CheckRun.Name = graphql.String(context.CheckRun.Name)
+			check.CheckRun.Status = graphql.String(context.CheckRun.Status)
+			check.CheckRun.Conclusion = graphql.String(context.CheckRun.Conclusion)
+			check.CheckRun.CheckSuite.Creator.Login = graphql.String(context.CheckRun.CheckSuite.Creator.Login)
+			check.CheckRun.CheckSuite.WorkflowRun.Workflow.Name = graphql.String(context.CheckRun.CheckSuite.WorkflowRun.Workflow.Name)
+			check.StatusContext.Context = graphql.String(context.StatusContext.Context)
+			check.StatusContext.State = graphql.String(context.StatusContext.State)
+			check.StatusContext.Creator.Login = graphql.String(context.StatusContext.Creator.Login)
+			node.Commit.StatusCheckRollup.Contexts.Nodes = append(node.Commit.StatusCheckRollup.Contexts.Nodes, check)
+		}
+		res.Nodes = append(res.Nodes, node)
+	}
+	return res
+}
+
+func convertProviderLabels(labels []providers.Label) []Label {
+	res := make([]Label, 0, len(labels))
+	for _, label := range labels {
+		res = append(res, Label(label))
+	}
+	return res
 }
 
 func convertProviderIssueToData(issue providers.IssueData) IssueData {
@@ -88,86 +240,204 @@ func convertProviderIssueToData(issue providers.IssueData) IssueData {
 			NameWithOwner: issue.Repository.NameWithOwner,
 			IsArchived:    issue.Repository.IsArchived,
 		},
-		// Initialize complex nested fields to avoid conversion issues
-		Assignees: Assignees{},
-		Comments:  IssueComments{},
-		Reactions: IssueReactions{},
-		Labels:    IssueLabels{},
-	}
-}
-
-// Enhanced versions of existing functions that support multiple providers
-func FetchPullRequestsWithProvider(query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
-	provider := GetCurrentProvider()
-	if provider != nil {
-		log.Debug("Using provider", "type", provider.GetType(), "supportsPRs", provider.SupportsPullRequests())
-		if provider.SupportsPullRequests() {
-			providerResponse, err := provider.FetchPullRequests(query, limit, (*providers.PageInfo)(pageInfo))
-			if err != nil {
-				log.Debug("Provider fetch failed", "error", err)
-				return PullRequestsResponse{}, err
-			}
-			log.Debug("Provider fetch successful", "count", len(providerResponse.Prs))
-		
-		// Convert provider response to data response
-		prs := make([]PullRequestData, len(providerResponse.Prs))
-		for i, pr := range providerResponse.Prs {
-			prs[i] = convertProviderPRToData(pr)
-		}
-		
-		return PullRequestsResponse{
-			Prs:        prs,
-			TotalCount: providerResponse.TotalCount,
-			PageInfo:   PageInfo(providerResponse.PageInfo),
-		}, nil
-		} else {
-			log.Debug("Provider doesn't support pull requests")
-		}
-	} else {
+		Assignees: convertProviderAssignees(issue.Assignees),
+		Comments:  convertProviderIssueComments(issue.Comments),
+		Reactions: IssueReactions{TotalCount: issue.Reactions.TotalCount},
+		Labels:    IssueLabels{Nodes: convertProviderLabels(issue.Labels.Nodes)},
+	}
+}
+
+func convertProviderIssueComments(comments providers.IssueComments) IssueComments {
+	res := IssueComments{TotalCount: comments.TotalCount, Nodes: make([]IssueComment, 0, len(comments.Nodes))}
+	for _, comment := range comments.Nodes {
+		res.Nodes = append(res.Nodes, IssueComment(comment))
+	}
+	return res
+}
+
+// errProviderFallback tells the callers to use the original GitHub GraphQL implementation
+var errProviderFallback = errors.New("no provider available, falling back to GitHub")
+
+// Enhanced versions of existing functions that support multiple providers. They
+// fetch from the named provider profile, or the current provider for an empty name,
+// and return errProviderFallback when the GitHub GraphQL implementation should handle the request
+func FetchPullRequestsWithProvider(ctx context.Context, providerName string, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+	if provider == nil {
 		log.Debug("No provider available")
+		return PullRequestsResponse{}, errProviderFallback
+	}
+
+	log.Debug("Using provider", "type", provider.GetType(), "supportsPRs", provider.SupportsPullRequests())
+	if !provider.SupportsPullRequests() {
+		return PullRequestsResponse{}, unsupportedByProvider(provider, "pull requests")
 	}
-	
-	// Fallback to original GitHub implementation
-	log.Debug("Falling back to original GitHub implementation")
-	return FetchPullRequests(query, limit, pageInfo)
+
+	providerResponse, err := provider.FetchPullRequests(ctx, query, limit, (*providers.PageInfo)(pageInfo))
+	if err != nil {
+		log.Debug("Provider fetch failed", "error", err)
+		return PullRequestsResponse{}, providerError(provider, err)
+	}
+	log.Debug("Provider fetch successful", "count", len(providerResponse.Prs))
+	return convertProviderPRsResponse(providerResponse), nil
 }
 
-func FetchIssuesWithProvider(query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
-	provider := GetCurrentProvider()
-	if provider != nil && provider.SupportsIssues() {
-		providerResponse, err := provider.FetchIssues(query, limit, (*providers.PageInfo)(pageInfo))
+// FetchUpdatedPullRequestsWithProvider fetches the updated pull requests and the
+// count of a section in a single request when the provider is an UpdatedFetcher,
+// and searches twice otherwise
+func FetchUpdatedPullRequestsWithProvider(ctx context.Context, providerName string, query string, updatedQuery string, limit int) (PullRequestsResponse, int, error) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return PullRequestsResponse{}, 0, err
+	}
+	if provider == nil {
+		return PullRequestsResponse{}, 0, errProviderFallback
+	}
+	fetcher, ok := provider.(providers.UpdatedFetcher)
+	if !ok || !provider.SupportsPullRequests() {
+		updated, err := FetchPullRequestsWithProvider(ctx, providerName, updatedQuery, limit, nil)
 		if err != nil {
-			return IssuesResponse{}, err
+			return PullRequestsResponse{}, 0, err
 		}
-		
-		// Convert provider response to data response
-		issues := make([]IssueData, len(providerResponse.Issues))
-		for i, issue := range providerResponse.Issues {
-			issues[i] = convertProviderIssueToData(issue)
-		}
-		
-		return IssuesResponse{
-			Issues:     issues,
-			TotalCount: providerResponse.TotalCount,
-			PageInfo:   PageInfo(providerResponse.PageInfo),
-		}, nil
-	}
-	
-	// Fallback to original GitHub implementation
-	return FetchIssues(query, limit, pageInfo)
-}
-
-func FetchPullRequestWithProvider(url string) (PullRequestData, error) {
-	provider := GetCurrentProvider()
-	if provider != nil && provider.SupportsPullRequests() {
-		providerPR, err := provider.FetchPullRequest(url)
+		// Only the count of the section matters
+		current, err := FetchPullRequestsWithProvider(ctx, providerName, query, 1, nil)
+		return updated, current.TotalCount, err
+	}
+
+	providerResponse, totalCount, err := fetcher.FetchUpdatedPullRequests(ctx, query, updatedQuery, limit)
+	if err != nil {
+		return PullRequestsResponse{}, 0, providerError(provider, err)
+	}
+	return convertProviderPRsResponse(providerResponse), totalCount, nil
+}
+
+func convertProviderPRsResponse(providerResponse providers.PullRequestsResponse) PullRequestsResponse {
+	prs := make([]PullRequestData, len(providerResponse.Prs))
+	for i, pr := range providerResponse.Prs {
+		prs[i] = convertProviderPRToData(pr)
+	}
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: providerResponse.TotalCount,
+		PageInfo:   PageInfo(providerResponse.PageInfo),
+		Warnings:   providerResponse.Warnings,
+	}
+}
+
+func FetchIssuesWithProvider(ctx context.Context, providerName string, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return IssuesResponse{}, err
+	}
+	if provider == nil {
+		return IssuesResponse{}, errProviderFallback
+	}
+	if !provider.SupportsIssues() {
+		return IssuesResponse{}, unsupportedByProvider(provider, "issues")
+	}
+
+	providerResponse, err := provider.FetchIssues(ctx, query, limit, (*providers.PageInfo)(pageInfo))
+	if err != nil {
+		return IssuesResponse{}, providerError(provider, err)
+	}
+	return convertProviderIssuesResponse(providerResponse), nil
+}
+
+// FetchUpdatedIssuesWithProvider fetches the updated issues and the count of a
+// section, see FetchUpdatedPullRequestsWithProvider
+func FetchUpdatedIssuesWithProvider(ctx context.Context, providerName string, query string, updatedQuery string, limit int) (IssuesResponse, int, error) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return IssuesResponse{}, 0, err
+	}
+	if provider == nil {
+		return IssuesResponse{}, 0, errProviderFallback
+	}
+	fetcher, ok := provider.(providers.UpdatedFetcher)
+	if !ok || !provider.SupportsIssues() {
+		updated, err := FetchIssuesWithProvider(ctx, providerName, updatedQuery, limit, nil)
 		if err != nil {
-			return PullRequestData{}, err
+			return IssuesResponse{}, 0, err
 		}
-		
-		return convertProviderPRToData(providerPR), nil
+		// Only the count of the section matters
+		current, err := FetchIssuesWithProvider(ctx, providerName, query, 1, nil)
+		return updated, current.TotalCount, err
 	}
-	
-	// Fallback to original GitHub implementation
-	return FetchPullRequest(url)
-}
\ No newline at end of file
+
+	providerResponse, totalCount, err := fetcher.FetchUpdatedIssues(ctx, query, updatedQuery, limit)
+	if err != nil {
+		return IssuesResponse{}, 0, providerError(provider, err)
+	}
+	return convertProviderIssuesResponse(providerResponse), totalCount, nil
+}
+
+func convertProviderIssuesResponse(providerResponse providers.IssuesResponse) IssuesResponse {
+	issues := make([]IssueData, len(providerResponse.Issues))
+	for i, issue := range providerResponse.Issues {
+		issues[i] = convertProviderIssueToData(issue)
+	}
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: providerResponse.TotalCount,
+		PageInfo:   PageInfo(providerResponse.PageInfo),
+		Warnings:   providerResponse.Warnings,
+	}
+}
+
+func FetchPullRequestWithProvider(ctx context.Context, providerName string, url string) (PullRequestData, error) {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return PullRequestData{}, err
+	}
+	if provider == nil {
+		return PullRequestData{}, errProviderFallback
+	}
+	if !provider.SupportsPullRequests() {
+		return PullRequestData{}, unsupportedByProvider(provider, "pull requests")
+	}
+
+	providerPR, err := provider.FetchPullRequest(ctx, url)
+	if err != nil {
+		return PullRequestData{}, providerError(provider, err)
+	}
+
+	return convertProviderPRToData(providerPR), nil
+}
+
+// providerError keeps the GraphQL fallback for GitHub, whose provider doesn't
+// query everything the UI needs yet. The fallback only knows the default host,
+// so GitHub Enterprise Server profiles and other providers report their errors.
+// Fetches that were cancelled, timed out or rate limited aren't retried through the fallback
+func providerError(provider providers.GitProvider, err error) error {
+	var rateLimitErr *providers.RateLimitError
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &rateLimitErr) {
+		return err
+	}
+	if gitHub, ok := provider.(*providers.GitHubProvider); ok && gitHub.IsDefaultHost() {
+		return errProviderFallback
+	}
+	return err
+}
+
+// GitHubRepoArg returns a repository for the -R flag of gh, including the host
+// when the named provider is a GitHub Enterprise Server instance
+func GitHubRepoArg(providerName string, repoNameWithOwner string) string {
+	provider, err := GetProvider(providerName)
+	if err != nil {
+		return repoNameWithOwner
+	}
+	if gitHub, ok := provider.(*providers.GitHubProvider); ok {
+		return gitHub.RepoArg(repoNameWithOwner)
+	}
+	return repoNameWithOwner
+}
+
+func unsupportedByProvider(provider providers.GitProvider, feature string) error {
+	if provider.GetType() == providers.GitHub {
+		return errProviderFallback
+	}
+	return fmt.Errorf("%s doesn't support %s", provider.GetType(), feature)
+}
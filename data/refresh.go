@@ -0,0 +1,63 @@
+package data
+
+import (
+	"slices"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// MergeUpdatedRows merges the rows updated since the last fetch of a section
+// into the rows it shows, most recently updated first. totalCount is the number
+// of rows matching the section at the last fetch and newTotalCount the number
+// matching now.
+//
+// Rows that no longer match aren't among the updated rows, so they can't be
+// told apart from the rows that didn't change. They are only known to be
+// missing when the counts don't add up, in which case the rows can't be merged
+// and the section has to be reloaded
+func MergeUpdatedRows[T RowData](rows []T, updated []T, totalCount int, newTotalCount int) ([]T, bool) {
+	shown := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		shown[row.GetUrl()] = true
+	}
+	isUpdated := make(map[string]bool, len(updated))
+	added := 0
+	for _, row := range updated {
+		isUpdated[row.GetUrl()] = true
+		if !shown[row.GetUrl()] {
+			added++
+		}
+	}
+	// Rows that matched before without being shown, because they were on a page
+	// that wasn't fetched, don't add up either
+	if totalCount == providers.UnknownTotalCount || newTotalCount != totalCount+added {
+		return nil, false
+	}
+
+	merged := make([]T, 0, len(rows)+added)
+	merged = append(merged, updated...)
+	for _, row := range rows {
+		if !isUpdated[row.GetUrl()] {
+			merged = append(merged, row)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b T) int {
+		return b.GetUpdatedAt().Compare(a.GetUpdatedAt())
+	})
+	return merged, true
+}
+
+// AppendNewRows appends a page of rows, skipping the ones already shown. Pages
+// shift when rows are merged in after the first page was fetched
+func AppendNewRows[T RowData](rows []T, page []T) []T {
+	shown := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		shown[row.GetUrl()] = true
+	}
+	for _, row := range page {
+		if !shown[row.GetUrl()] {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
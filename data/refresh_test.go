@@ -0,0 +1,60 @@
+package data_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+var refreshEpoch = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func refreshIssue(number int, updatedHoursAgo int) data.IssueData {
+	return data.IssueData{
+		Number:    number,
+		Url:       fmt.Sprintf("https://github.com/acme/widgets/issues/%d", number),
+		UpdatedAt: refreshEpoch.Add(-time.Duration(updatedHoursAgo) * time.Hour),
+	}
+}
+
+func issueNumbers(issues []data.IssueData) []int {
+	var numbers []int
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Number)
+	}
+	return numbers
+}
+
+func TestMergeUpdatedRows(t *testing.T) {
+	rows := []data.IssueData{refreshIssue(4, 10), refreshIssue(3, 20), refreshIssue(2, 30)}
+
+	// #2 was updated and #5 was opened, so 4 issues match out of the 3 shown
+	merged, ok := data.MergeUpdatedRows(rows, []data.IssueData{refreshIssue(5, 1), refreshIssue(2, 2)}, 3, 4)
+	require.True(t, ok)
+	require.Equal(t, []int{5, 2, 4, 3}, issueNumbers(merged))
+	require.Equal(t, []int{4, 3, 2}, issueNumbers(rows))
+
+	merged, ok = data.MergeUpdatedRows(rows, nil, 3, 3)
+	require.True(t, ok)
+	require.Equal(t, []int{4, 3, 2}, issueNumbers(merged))
+
+	// #2 or #3 stopped matching, or #5 matched before without being shown
+	_, ok = data.MergeUpdatedRows(rows, []data.IssueData{refreshIssue(5, 1)}, 3, 3)
+	require.False(t, ok)
+	_, ok = data.MergeUpdatedRows(rows, nil, 3, 2)
+	require.False(t, ok)
+
+	// Counts the provider couldn't make don't add up
+	_, ok = data.MergeUpdatedRows(rows, nil, providers.UnknownTotalCount, providers.UnknownTotalCount)
+	require.False(t, ok)
+}
+
+func TestAppendNewRows(t *testing.T) {
+	rows := []data.IssueData{refreshIssue(5, 1), refreshIssue(4, 10), refreshIssue(3, 20)}
+	rows = data.AppendNewRows(rows, []data.IssueData{refreshIssue(3, 20), refreshIssue(2, 30)})
+	require.Equal(t, []int{5, 4, 3, 2}, issueNumbers(rows))
+}
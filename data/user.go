@@ -4,7 +4,17 @@ import (
 	gh "github.com/cli/go-gh/v2/pkg/api"
 )
 
+// CurrentLoginName returns the login of the authenticated user of the current provider
 func CurrentLoginName() (string, error) {
+	provider, err := GetProvider("")
+	if err != nil {
+		return "", err
+	}
+	if provider != nil {
+		authInfo, err := provider.GetAuthInfo()
+		return authInfo.Username, err
+	}
+
 	client, err := gh.DefaultGraphQLClient()
 	if err != nil {
 		return "", nil
@@ -0,0 +1,372 @@
+// fake-plugin is a reference gh-dash provider plugin, serving a few pull
+// requests and issues from memory. It is used by the tests of the plugin
+// provider, and shows what a plugin for another code review system needs:
+//
+//	provider:
+//	  type: plugin
+//	  command: go run ./examples/fake-plugin
+//
+// gh-dash writes one JSON request per line to stdin, and the plugin answers
+// each with one JSON response per line on stdout. Logs go to stderr
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type request struct {
+	Id     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Id     int         `json:"id"`
+	Result interface{} `json:"result,omitempty"`
+	Error  *rpcError   `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Message string `json:"message"`
+}
+
+type login struct {
+	Login string `json:"login"`
+}
+
+type comment struct {
+	Author    login     `json:"author"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type review struct {
+	Author    login     `json:"author"`
+	State     string    `json:"state"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type repository struct {
+	Name          string `json:"name"`
+	NameWithOwner string `json:"nameWithOwner"`
+}
+
+type assignees struct {
+	Nodes      []login `json:"nodes"`
+	TotalCount int     `json:"totalCount"`
+}
+
+type comments struct {
+	Nodes      []comment `json:"nodes"`
+	TotalCount int       `json:"totalCount"`
+}
+
+type reviews struct {
+	Nodes      []review `json:"nodes"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// item is a pull request or an issue, with the fields of gh-dash's
+// PullRequestData and IssueData that the fake plugin fills in
+type item struct {
+	Number         int        `json:"number"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	Author         login      `json:"author"`
+	State          string     `json:"state"`
+	IsDraft        bool       `json:"isDraft,omitempty"`
+	Url            string     `json:"url"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	Repository     repository `json:"repository"`
+	HeadRefName    string     `json:"headRefName,omitempty"`
+	BaseRefName    string     `json:"baseRefName,omitempty"`
+	ReviewDecision string     `json:"reviewDecision,omitempty"`
+	Assignees      assignees  `json:"assignees"`
+	Comments       comments   `json:"comments"`
+	Reviews        reviews    `json:"reviews"`
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	StartCursor string `json:"startCursor"`
+	EndCursor   string `json:"endCursor"`
+}
+
+const (
+	username = "fake-user"
+	baseURL  = "https://review.example.com"
+)
+
+var epoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newItem(number int, title string, author string, state string) *item {
+	repo := repository{Name: "widgets", NameWithOwner: "acme/widgets"}
+	return &item{
+		Number:     number,
+		Title:      title,
+		Author:     login{Login: author},
+		State:      state,
+		Url:        fmt.Sprintf("%s/%s/changes/%d", baseURL, repo.NameWithOwner, number),
+		CreatedAt:  epoch,
+		UpdatedAt:  epoch.Add(time.Duration(number) * time.Hour),
+		Repository: repo,
+	}
+}
+
+var pullRequests = []*item{
+	newItem(3, "Add the plugin protocol", username, "OPEN"),
+	newItem(2, "Fix the flaky login test", "alice", "OPEN"),
+	newItem(1, "Remove the legacy importer", "bob", "MERGED"),
+}
+
+var issues = []*item{
+	newItem(10, "Widgets render twice", "alice", "OPEN"),
+	newItem(9, "Document the API", username, "CLOSED"),
+}
+
+func init() {
+	pullRequests[0].HeadRefName, pullRequests[0].BaseRefName = "plugins", "main"
+	pullRequests[1].IsDraft = true
+	pullRequests[1].Assignees = assignees{Nodes: []login{{Login: username}}, TotalCount: 1}
+}
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	out := json.NewEncoder(os.Stdout)
+
+	// gh-dash closes stdin when it exits
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid request:", err)
+			continue
+		}
+		// cancel is a notification, the fake plugin answers immediately anyway
+		if req.Method == "cancel" {
+			continue
+		}
+
+		result, err := handle(req)
+		resp := response{Id: req.Id, Result: result}
+		if err != nil {
+			resp = response{Id: req.Id, Error: &rpcError{Message: err.Error()}}
+		}
+		if err := out.Encode(resp); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to write response:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func handle(req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion int `json:"protocolVersion"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.ProtocolVersion != 1 {
+			return nil, fmt.Errorf("unsupported protocol version %d", params.ProtocolVersion)
+		}
+		return map[string]interface{}{
+			"name":               "fake",
+			"username":           username,
+			"pullRequests":       true,
+			"issues":             true,
+			"expandsMeQualifier": true,
+			"capabilities": map[string]interface{}{
+				"mergeStrategies": []string{"merge"},
+				"close":           true,
+				"draftToggle":     true,
+				"reviews":         true,
+				"comments":        true,
+				"assignees":       true,
+				"issueClose":      true,
+			},
+			"commands": map[string][]string{
+				"diff": {"echo", "diff", "{repo}", "{number}"},
+			},
+		}, nil
+
+	case "listPullRequests", "listIssues":
+		var params struct {
+			Query  string `json:"query"`
+			Limit  int    `json:"limit"`
+			Cursor string `json:"cursor"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+		items := pullRequests
+		key := "prs"
+		if req.Method == "listIssues" {
+			items, key = issues, "issues"
+		}
+		page, info, total := list(items, params.Query, params.Limit, params.Cursor)
+		return map[string]interface{}{key: page, "totalCount": total, "pageInfo": info}, nil
+
+	case "getPullRequest":
+		var params struct {
+			Url string `json:"url"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+		for _, pr := range pullRequests {
+			if pr.Url == params.Url {
+				return pr, nil
+			}
+		}
+		return nil, fmt.Errorf("no pull request at %s", params.Url)
+
+	case "action":
+		var params struct {
+			Action string   `json:"action"`
+			Number int      `json:"number"`
+			Repo   string   `json:"repo"`
+			Body   string   `json:"body"`
+			Logins []string `json:"logins"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return struct{}{}, act(params.Action, params.Number, params.Body, params.Logins)
+	}
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
+
+// list filters the items on is: and author: qualifiers, and pages through them
+// with the offset of the next item as the cursor
+func list(items []*item, query string, limit int, cursor string) ([]*item, pageInfo, int) {
+	var matching []*item
+	for _, it := range items {
+		if matches(it, query) {
+			matching = append(matching, it)
+		}
+	}
+
+	start, _ := strconv.Atoi(cursor)
+	start = min(start, len(matching))
+	end := len(matching)
+	if limit > 0 {
+		end = min(start+limit, len(matching))
+	}
+	info := pageInfo{
+		HasNextPage: end < len(matching),
+		StartCursor: strconv.Itoa(start),
+		EndCursor:   strconv.Itoa(end),
+	}
+	return matching[start:end], info, len(matching)
+}
+
+func matches(it *item, query string) bool {
+	for _, token := range strings.Fields(query) {
+		qualifier, value, ok := strings.Cut(token, ":")
+		if !ok {
+			if !strings.Contains(strings.ToLower(it.Title), strings.ToLower(token)) {
+				return false
+			}
+			continue
+		}
+		switch qualifier {
+		case "is":
+			switch value {
+			case "open", "closed", "merged":
+				if !strings.EqualFold(it.State, value) {
+					return false
+				}
+			case "draft":
+				if !it.IsDraft {
+					return false
+				}
+			}
+		case "author":
+			if it.Author.Login != value {
+				return false
+			}
+		case "assignee":
+			if !hasLogin(it.Assignees.Nodes, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func act(action string, number int, body string, logins []string) error {
+	var pr *item
+	for _, it := range append(pullRequests, issues...) {
+		if it.Number == number {
+			pr = it
+		}
+	}
+	if pr == nil {
+		return fmt.Errorf("no pull request or issue #%d", number)
+	}
+
+	switch action {
+	case "approve":
+		pr.Reviews.Nodes = append(pr.Reviews.Nodes, review{Author: login{Login: username}, State: "APPROVED", Body: body, UpdatedAt: time.Now()})
+		pr.Reviews.TotalCount++
+		pr.ReviewDecision = "APPROVED"
+	case "merge":
+		pr.State = "MERGED"
+	case "close", "closeIssue":
+		pr.State = "CLOSED"
+	case "reopen", "reopenIssue":
+		pr.State = "OPEN"
+	case "ready":
+		pr.IsDraft = false
+	case "comment":
+		pr.Comments.Nodes = append(pr.Comments.Nodes, comment{Author: login{Login: username}, Body: body, UpdatedAt: time.Now()})
+		pr.Comments.TotalCount++
+	case "assign":
+		for _, l := range logins {
+			if !hasLogin(pr.Assignees.Nodes, l) {
+				pr.Assignees.Nodes = append(pr.Assignees.Nodes, login{Login: l})
+			}
+		}
+		pr.Assignees.TotalCount = len(pr.Assignees.Nodes)
+	case "unassign":
+		var kept []login
+		for _, assignee := range pr.Assignees.Nodes {
+			if !contains(logins, assignee.Login) {
+				kept = append(kept, assignee)
+			}
+		}
+		pr.Assignees = assignees{Nodes: kept, TotalCount: len(kept)}
+	default:
+		return fmt.Errorf("the fake plugin can't %s", action)
+	}
+	pr.UpdatedAt = time.Now()
+	return nil
+}
+
+func hasLogin(logins []login, name string) bool {
+	for _, l := range logins {
+		if l.Login == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
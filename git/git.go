@@ -17,6 +17,8 @@ import (
 // Extends git.Repository
 type Repo struct {
 	gitm.Repository
+	// Remote is the preferred remote, see GetPreferredRemote, and Origin its URL
+	Remote         string
 	Origin         string
 	Remotes        []string
 	Branches       []Branch
@@ -35,7 +37,13 @@ type Branch struct {
 	Remotes       []string
 }
 
-func GetOriginUrl(dir string) (string, error) {
+// DefaultRemotes are preferred when no remotes are configured, in the order gh
+// uses. In a fork workflow upstream is the repository pull requests are opened against
+var DefaultRemotes = []string{"upstream", "github", "origin"}
+
+// GetPreferredRemote returns the first of the preferred remotes the repo has,
+// using DefaultRemotes when preferred is empty, or else its first remote
+func GetPreferredRemote(dir string, preferred []string) (string, error) {
 	repo, err := gitm.Open(dir)
 	if err != nil {
 		return "", err
@@ -44,23 +52,40 @@ func GetOriginUrl(dir string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if len(remotes) == 0 {
+		return "", errors.New("no git remote found")
+	}
 
-	for _, remote := range remotes {
-		if remote != "origin" {
-			continue
-		}
-
-		urls, err := gitm.RemoteGetURL(dir, remote)
-		if err != nil || len(urls) == 0 {
-			return "", err
+	if len(preferred) == 0 {
+		preferred = DefaultRemotes
+	}
+	for _, name := range preferred {
+		for _, remote := range remotes {
+			if remote == name {
+				return remote, nil
+			}
 		}
-		return urls[0], nil
 	}
+	return remotes[0], nil
+}
 
-	return "", errors.New("no origin remote found")
+// GetRemoteUrl returns the URL of the preferred remote, see GetPreferredRemote
+func GetRemoteUrl(dir string, preferred []string) (string, error) {
+	remote, err := GetPreferredRemote(dir, preferred)
+	if err != nil {
+		return "", err
+	}
+	urls, err := gitm.RemoteGetURL(dir, remote)
+	if err != nil {
+		return "", err
+	}
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote %s has no URL", remote)
+	}
+	return urls[0], nil
 }
 
-func GetRepo(dir string) (*Repo, error) {
+func GetRepo(dir string, preferredRemotes []string) (*Repo, error) {
 	repo, err := gitm.Open(dir)
 	if err != nil {
 		return nil, err
@@ -77,6 +102,10 @@ func GetRepo(dir string) (*Repo, error) {
 	if err != nil {
 		return nil, err
 	}
+	remote, err := GetPreferredRemote(dir, preferredRemotes)
+	if err != nil {
+		return nil, err
+	}
 	status, err := getUnstagedStatus(repo)
 	if err != nil {
 		return nil, err
@@ -92,14 +121,7 @@ func GetRepo(dir string) (*Repo, error) {
 			updatedAt = &commits[0].Committer.When
 			lastCommitMsg = utils.StringPtr(commits[0].Summary())
 		}
-		commitsAhead, err := repo.RevListCount([]string{fmt.Sprintf("origin/%s..%s", b, b)})
-		if err != nil {
-			commitsAhead = 0
-		}
-		commitsBehind, err := repo.RevListCount([]string{fmt.Sprintf("%s..origin/%s", b, b)})
-		if err != nil {
-			commitsBehind = 0
-		}
+		commitsAhead, commitsBehind := countAheadBehind(repo, b, remote)
 		remotes, _ := repo.RemoteGetURL(b)
 		branches[i] = Branch{
 			Name:          b,
@@ -129,12 +151,30 @@ func GetRepo(dir string) (*Repo, error) {
 	if err != nil {
 		return nil, err
 	}
-	origin, err := gitm.RemoteGetURL(dir, "origin", gitm.RemoteGetURLOptions{All: true})
+	origin, err := gitm.RemoteGetURL(dir, remote, gitm.RemoteGetURLOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Repo{Repository: *repo, Origin: origin[0], Remotes: remotes, HeadBranchName: headBranch, Branches: branches, Status: status}, nil
+	return &Repo{Repository: *repo, Remote: remote, Origin: origin[0], Remotes: remotes, HeadBranchName: headBranch, Branches: branches, Status: status}, nil
+}
+
+// countAheadBehind compares a branch with the branch of the same name on the
+// preferred remote, or on origin when the preferred remote doesn't have it,
+// e.g. for branches pushed to a fork
+func countAheadBehind(repo *gitm.Repository, branch string, remote string) (int64, int64) {
+	for _, r := range []string{remote, "origin"} {
+		commitsAhead, err := repo.RevListCount([]string{fmt.Sprintf("%s/%s..%s", r, branch, branch)})
+		if err != nil {
+			continue
+		}
+		commitsBehind, err := repo.RevListCount([]string{fmt.Sprintf("%s..%s/%s", branch, r, branch)})
+		if err != nil {
+			continue
+		}
+		return commitsAhead, commitsBehind
+	}
+	return 0, 0
 }
 
 func GetStatus(dir string) (gitm.NameStatus, error) {
@@ -172,7 +212,7 @@ func getUnstagedStatus(repo *gitm.Repository) (gitm.NameStatus, error) {
 	return status, err
 }
 
-func FetchRepo(dir string) (*Repo, error) {
+func FetchRepo(dir string, preferredRemotes []string) (*Repo, error) {
 	repo, err := gitm.Open(dir)
 	if err != nil {
 		return nil, err
@@ -181,7 +221,49 @@ func FetchRepo(dir string) (*Repo, error) {
 	if err != nil {
 		return nil, err
 	}
-	return GetRepo(dir)
+	return GetRepo(dir, preferredRemotes)
+}
+
+// CheckoutPullRequest checks out a pull request of the preferred remote, see
+// GetPreferredRemote, and returns the checked out branch. The source branch is
+// checked out as a local branch tracking it. Pull requests from forks, whose source
+// branch isn't on the remote, and those whose source branch can't be fetched are
+// checked out as a pr/<number> branch at prRef
+func CheckoutPullRequest(dir string, preferredRemotes []string, prNumber int, sourceBranch string, isFork bool, prRef string) (string, error) {
+	repo, err := gitm.Open(dir)
+	if err != nil {
+		return "", err
+	}
+	remote, err := GetPreferredRemote(dir, preferredRemotes)
+	if err != nil {
+		return "", err
+	}
+
+	if sourceBranch != "" && !isFork {
+		remoteRef := fmt.Sprintf("refs/remotes/%s/%s", remote, sourceBranch)
+		_, err := gitm.NewCommand("fetch", remote, fmt.Sprintf("+%s%s:%s", gitm.RefsHeads, sourceBranch, remoteRef)).RunInDir(dir)
+		if err == nil {
+			if repo.HasBranch(sourceBranch) {
+				if err := repo.Checkout(sourceBranch); err != nil {
+					return "", err
+				}
+				_, err = gitm.NewCommand("merge", "--ff-only", remoteRef).RunInDir(dir)
+				return sourceBranch, err
+			}
+			_, err = gitm.NewCommand("checkout", "-b", sourceBranch, "--track", remote+"/"+sourceBranch).RunInDir(dir)
+			return sourceBranch, err
+		}
+	}
+
+	if prRef == "" {
+		return "", fmt.Errorf("no branch to check out for pull request #%d", prNumber)
+	}
+	branch := fmt.Sprintf("pr/%d", prNumber)
+	if _, err := gitm.NewCommand("fetch", remote, prRef).RunInDir(dir); err != nil {
+		return "", err
+	}
+	_, err = gitm.NewCommand("checkout", "-B", branch, "FETCH_HEAD").RunInDir(dir)
+	return branch, err
 }
 
 func GetRepoInPwd() (*gitm.Repository, error) {
@@ -0,0 +1,126 @@
+package git_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/git"
+)
+
+func newRepoWithRemotes(t *testing.T, remotes ...string) string {
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	for _, remote := range remotes {
+		url := "https://github.com/" + remote + "/gh-dash.git"
+		require.NoError(t, exec.Command("git", "-C", dir, "remote", "add", remote, url).Run())
+	}
+	return dir
+}
+
+func TestGetPreferredRemote(t *testing.T) {
+	testCases := map[string]struct {
+		remotes   []string
+		preferred []string
+		want      string
+	}{
+		"upstream of a fork": {
+			remotes: []string{"origin", "upstream"},
+			want:    "upstream",
+		},
+		"origin only": {
+			remotes: []string{"origin"},
+			want:    "origin",
+		},
+		"configured remote": {
+			remotes:   []string{"origin", "upstream", "company"},
+			preferred: []string{"company", "origin"},
+			want:      "company",
+		},
+		"missing preferred remotes": {
+			remotes:   []string{"fork"},
+			preferred: []string{"upstream"},
+			want:      "fork",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := newRepoWithRemotes(t, tc.remotes...)
+			got, err := git.GetPreferredRemote(dir, tc.preferred)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+
+			url, err := git.GetRemoteUrl(dir, tc.preferred)
+			require.NoError(t, err)
+			require.Equal(t, "https://github.com/"+tc.want+"/gh-dash.git", url)
+		})
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=gh-dash", "-c", "user.email=gh-dash@example.com"}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// newPullRequestRemote creates a repo to fetch pull requests from, with a feature
+// branch and the refs/pull/5/merge ref of a pull request from a fork
+func newPullRequestRemote(t *testing.T) string {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "main")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "feature")
+	runGit(t, dir, "checkout", "-q", "--detach", "main")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "fork")
+	runGit(t, dir, "update-ref", "refs/pull/5/merge", "HEAD")
+	runGit(t, dir, "checkout", "-q", "main")
+	return dir
+}
+
+func TestCheckoutPullRequest(t *testing.T) {
+	testCases := map[string]struct {
+		sourceBranch string
+		isFork       bool
+		wantBranch   string
+		wantCommit   string
+	}{
+		"branch of the repo": {
+			sourceBranch: "feature",
+			wantBranch:   "feature",
+			wantCommit:   "feature",
+		},
+		"fork with a branch of the same name": {
+			sourceBranch: "feature",
+			isFork:       true,
+			wantBranch:   "pr/5",
+			wantCommit:   "fork",
+		},
+		"deleted source branch": {
+			sourceBranch: "deleted",
+			wantBranch:   "pr/5",
+			wantCommit:   "fork",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// Pull requests are fetched from upstream, origin being an empty fork
+			dir := t.TempDir()
+			runGit(t, dir, "init", "-q")
+			runGit(t, dir, "remote", "add", "origin", t.TempDir())
+			runGit(t, dir, "remote", "add", "upstream", newPullRequestRemote(t))
+
+			branch, err := git.CheckoutPullRequest(dir, nil, 5, tc.sourceBranch, tc.isFork, "refs/pull/5/merge")
+			require.NoError(t, err)
+			require.Equal(t, tc.wantBranch, branch)
+			require.Equal(t, tc.wantBranch, runGit(t, dir, "branch", "--show-current"))
+			require.Equal(t, tc.wantCommit, runGit(t, dir, "log", "-1", "--format=%s"))
+		})
+	}
+}
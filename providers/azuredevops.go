@@ -1,12 +1,17 @@
 package providers
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -15,12 +20,19 @@ import (
 )
 
 type AzureDevOpsProvider struct {
+	*RateLimitTracker
+
 	client       *http.Client
 	config       ProviderConfig
 	organization string
 	project      string
 	baseURL      string
 	token        string
+
+	// connectionDataMu guards connectionData, which the fetches of every
+	// section fill
+	connectionDataMu sync.Mutex
+	connectionData   *AzureConnectionData
 }
 
 type AzurePullRequest struct {
@@ -32,32 +44,62 @@ type AzurePullRequest struct {
 		DisplayName string `json:"displayName"`
 		UniqueName  string `json:"uniqueName"`
 	} `json:"createdBy"`
-	CreationDate   time.Time `json:"creationDate"`
-	SourceRefName  string    `json:"sourceRefName"`
-	TargetRefName  string    `json:"targetRefName"`
-	Repository     struct {
+	CreationDate  time.Time       `json:"creationDate"`
+	ClosedDate    time.Time       `json:"closedDate"`
+	SourceRefName string          `json:"sourceRefName"`
+	TargetRefName string          `json:"targetRefName"`
+	IsDraft       bool            `json:"isDraft"`
+	MergeStatus   string          `json:"mergeStatus"`
+	Reviewers     []AzureReviewer `json:"reviewers"`
+	Repository    struct {
+		Id      string `json:"id"`
 		Name    string `json:"name"`
 		WebURL  string `json:"webUrl"`
 		Project struct {
+			Id   string `json:"id"`
 			Name string `json:"name"`
 		} `json:"project"`
 	} `json:"repository"`
+	LastMergeSourceCommit struct {
+		CommitId string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	// ForkSource is the ref of the fork for pull requests from forks
+	ForkSource *struct {
+		Name string `json:"name"`
+	} `json:"forkSource"`
 	Url string `json:"url"`
 }
 
+type AzureIdentityRef struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
 type AzureWorkItem struct {
 	Id     int `json:"id"`
 	Fields struct {
-		Title       string    `json:"System.Title"`
-		State       string    `json:"System.State"`
-		CreatedBy   string    `json:"System.CreatedBy"`
-		CreatedDate time.Time `json:"System.CreatedDate"`
-		ChangedDate time.Time `json:"System.ChangedDate"`
-		Description string    `json:"System.Description"`
+		Title        string            `json:"System.Title"`
+		State        string            `json:"System.State"`
+		WorkItemType string            `json:"System.WorkItemType"`
+		TeamProject  string            `json:"System.TeamProject"`
+		CreatedBy    *AzureIdentityRef `json:"System.CreatedBy"`
+		AssignedTo   *AzureIdentityRef `json:"System.AssignedTo"`
+		Tags         string            `json:"System.Tags"`
+		CommentCount int               `json:"System.CommentCount"`
+		CreatedDate  time.Time         `json:"System.CreatedDate"`
+		ChangedDate  time.Time         `json:"System.ChangedDate"`
+		Description  string            `json:"System.Description"`
 	} `json:"fields"`
 	Url string `json:"url"`
 }
 
+type AzureWiqlResponse struct {
+	WorkItems []struct {
+		Id int `json:"id"`
+	} `json:"workItems"`
+}
+
 type AzurePullRequestsResponse struct {
 	Value []AzurePullRequest `json:"value"`
 	Count int                `json:"count"`
@@ -76,13 +118,15 @@ func NewAzureDevOpsProvider(config ProviderConfig) (GitProvider, error) {
 
 	log.Debug("Creating Azure DevOps provider", "org", config.Organization, "project", config.Project, "baseURL", baseURL, "hasToken", config.Token != "")
 
+	rateLimits := &RateLimitTracker{}
 	return &AzureDevOpsProvider{
-		client:       &http.Client{Timeout: 30 * time.Second},
-		config:       config,
-		organization: config.Organization,
-		project:      config.Project,
-		baseURL:      baseURL,
-		token:        config.Token,
+		RateLimitTracker: rateLimits,
+		client:           newRateLimitedClient(rateLimits),
+		config:           config,
+		organization:     config.Organization,
+		project:          config.Project,
+		baseURL:          baseURL,
+		token:            config.Token,
 	}, nil
 }
 
@@ -98,15 +142,43 @@ func (p *AzureDevOpsProvider) SupportsIssues() bool {
 	return true // Azure DevOps work items map to issues
 }
 
+// Capabilities are the actions of the REST API. Commenting and assigning aren't
+// supported on Azure DevOps, and checks are watched in the browser
+func (p *AzureDevOpsProvider) Capabilities() Capabilities {
+	return Capabilities{
+		MergeStrategies: []string{"merge", "squash", "rebase", "rebaseMerge"},
+		Close:           true,
+		DraftToggle:     true,
+		Reviews:         true,
+		Checkout:        true,
+	}
+}
+
+// GetAuthInfo resolves the authenticated user through the connection data. The
+// username is the display name, which is how users appear in pull requests and work items
 func (p *AzureDevOpsProvider) GetAuthInfo() (AuthInfo, error) {
+	tokenSource := "Personal Access Token"
+	if p.config.TokenSource != "" {
+		tokenSource = fmt.Sprintf("Personal Access Token (%s)", p.config.TokenSource)
+	}
+	if p.token == "" {
+		return AuthInfo{IsLoggedIn: false, TokenSource: tokenSource}, nil
+	}
+
+	ctx, cancel := actionContext()
+	defer cancel()
+	connectionData, err := p.fetchConnectionData(ctx)
+	if err != nil {
+		return AuthInfo{IsLoggedIn: false, TokenSource: tokenSource}, err
+	}
 	return AuthInfo{
-		Username:    "azure-user",
-		IsLoggedIn:  p.token != "",
-		TokenSource: "Personal Access Token",
+		Username:    connectionData.AuthenticatedUser.ProviderDisplayName,
+		IsLoggedIn:  true,
+		TokenSource: tokenSource,
 	}, nil
 }
 
-func (p *AzureDevOpsProvider) FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+func (p *AzureDevOpsProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
 	log.Debug("Azure DevOps FetchPullRequests called", "query", query, "limit", limit, "org", p.organization, "project", p.project)
 
 	// Check if we have required configuration
@@ -119,106 +191,207 @@ func (p *AzureDevOpsProvider) FetchPullRequests(query string, limit int, pageInf
 		return PullRequestsResponse{}, fmt.Errorf("Azure DevOps Personal Access Token is required. Set AZURE_DEVOPS_TOKEN, ADO_PAT, or AZURE_PAT environment variable")
 	}
 
-	apiURL := fmt.Sprintf("%s/%s/%s/_apis/git/pullrequests?api-version=7.1&$top=%d", 
-		p.baseURL, p.organization, p.project, limit)
-
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return PullRequestsResponse{}, err
-	}
-
-	p.setAuthHeader(req)
-	req.Header.Set("Content-Type", "application/json")
-
-	log.Debug("Fetching Azure DevOps PRs", "url", apiURL, "limit", limit)
-	
-	resp, err := p.client.Do(req)
+	search, err := p.buildPullRequestSearch(ctx, query)
 	if err != nil {
 		return PullRequestsResponse{}, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return PullRequestsResponse{}, err
+	// The pull requests API has no total count or next link, so page with $skip
+	// and ask for one extra pull request to know whether there is a next page
+	skip := 0
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		if parsed, err := strconv.Atoi(pageInfo.EndCursor); err == nil {
+			skip = parsed
+		}
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return PullRequestsResponse{}, fmt.Errorf("Azure DevOps API error: %s", string(body))
+	search.params.Set("api-version", "7.1")
+	search.params.Set("$skip", strconv.Itoa(skip))
+	search.params.Set("$top", strconv.Itoa(limit+1))
+
+	apiURL := fmt.Sprintf("%s/%s/%s/_apis/git/pullrequests?%s",
+		p.baseURL, p.organization, url.PathEscape(search.project), search.params.Encode())
+	if search.repository != "" {
+		apiURL = fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/pullrequests?%s",
+			p.baseURL, p.organization, url.PathEscape(search.project), url.PathEscape(search.repository), search.params.Encode())
 	}
 
+	log.Debug("Fetching Azure DevOps PRs", "url", apiURL, "limit", limit)
 	var azureResp AzurePullRequestsResponse
-	if err := json.Unmarshal(body, &azureResp); err != nil {
+	if _, err := p.do(ctx, "GET", apiURL, nil, &azureResp); err != nil {
 		return PullRequestsResponse{}, err
 	}
 
 	log.Debug("Successfully fetched Azure DevOps PRs", "count", azureResp.Count)
 
-	prs := make([]PullRequestData, 0, len(azureResp.Value))
-	for _, azurePR := range azureResp.Value {
-		// Convert Azure DevOps PR to common format
-		pr := p.convertAzurePRToData(azurePR)
-		prs = append(prs, pr)
+	hasNextPage := len(azureResp.Value) > limit
+	if hasNextPage {
+		azureResp.Value = azureResp.Value[:limit]
+	}
+
+	prs := p.fetchPullRequestsDetails(ctx, azureResp.Value)
+
+	end := skip + len(prs)
+	// Only the last page tells how many pull requests match
+	totalCount := end
+	if hasNextPage {
+		totalCount = UnknownTotalCount
 	}
 
 	return PullRequestsResponse{
 		Prs:        prs,
-		TotalCount: azureResp.Count,
-		PageInfo:   PageInfo{}, // TODO: Implement pagination
+		TotalCount: totalCount,
+		PageInfo: PageInfo{
+			HasNextPage: hasNextPage,
+			StartCursor: strconv.Itoa(skip),
+			EndCursor:   strconv.Itoa(end),
+		},
+		Warnings: unsupportedFiltersWarning("Azure DevOps", search.unsupported),
 	}, nil
 }
 
-func (p *AzureDevOpsProvider) FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+// azureWorkItemFields are the fields requested from the workitemsbatch API
+var azureWorkItemFields = []string{
+	"System.Id",
+	"System.Title",
+	"System.State",
+	"System.WorkItemType",
+	"System.TeamProject",
+	"System.CreatedBy",
+	"System.AssignedTo",
+	"System.Tags",
+	"System.CommentCount",
+	"System.CreatedDate",
+	"System.ChangedDate",
+	"System.Description",
+}
+
+// azureWorkItemsBatchSize is the maximum number of ids the workitemsbatch API accepts
+const azureWorkItemsBatchSize = 200
+
+func (p *AzureDevOpsProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	if p.organization == "" || p.project == "" {
+		return IssuesResponse{}, fmt.Errorf("Azure DevOps organization and project are required")
+	}
+	if p.token == "" {
+		return IssuesResponse{}, fmt.Errorf("Azure DevOps Personal Access Token is required. Set AZURE_DEVOPS_TOKEN, ADO_PAT, or AZURE_PAT environment variable")
+	}
+
 	// Use Work Items API for issues
-	wiql := fmt.Sprintf("SELECT [System.Id], [System.Title], [System.State], [System.CreatedBy], [System.CreatedDate], [System.ChangedDate] FROM workitems WHERE [System.TeamProject] = '%s'", p.project)
-	
-	apiURL := fmt.Sprintf("%s/%s/%s/_apis/wit/wiql?api-version=7.1", 
-		p.baseURL, p.organization, p.project)
+	wiql, unsupported := p.buildWorkItemQuery(query)
 
-	wiqlQuery := map[string]string{"query": wiql}
-	jsonData, err := json.Marshal(wiqlQuery)
-	if err != nil {
+	apiURL := fmt.Sprintf("%s/%s/%s/_apis/wit/wiql?api-version=7.1",
+		p.baseURL, p.organization, url.PathEscape(p.project))
+
+	log.Debug("Fetching Azure DevOps work items", "url", apiURL, "limit", limit, "wiql", wiql)
+	var wiqlResp AzureWiqlResponse
+	if _, err := p.do(ctx, "POST", apiURL, map[string]string{"query": wiql}, &wiqlResp); err != nil {
 		return IssuesResponse{}, err
 	}
 
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(string(jsonData)))
+	// WIQL only returns the ids of all matching work items, so page through them here
+	offset := 0
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		if parsed, err := strconv.Atoi(pageInfo.EndCursor); err == nil {
+			offset = parsed
+		}
+	}
+	offset = min(offset, len(wiqlResp.WorkItems))
+	end := min(offset+limit, len(wiqlResp.WorkItems))
+	ids := make([]int, 0, end-offset)
+	for _, workItem := range wiqlResp.WorkItems[offset:end] {
+		ids = append(ids, workItem.Id)
+	}
+
+	workItems, err := p.fetchWorkItems(ctx, ids)
 	if err != nil {
 		return IssuesResponse{}, err
 	}
+	log.Debug("Successfully fetched Azure DevOps work items", "count", len(workItems), "total", len(wiqlResp.WorkItems))
+
+	issues := make([]IssueData, 0, len(workItems))
+	for _, workItem := range workItems {
+		issues = append(issues, p.convertWorkItemToData(workItem))
+	}
+
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: len(wiqlResp.WorkItems),
+		PageInfo: PageInfo{
+			HasNextPage: end < len(wiqlResp.WorkItems),
+			StartCursor: strconv.Itoa(offset),
+			EndCursor:   strconv.Itoa(end),
+		},
+		Warnings: unsupportedFiltersWarning("Azure DevOps", unsupported),
+	}, nil
+}
+
+// fetchWorkItems fetches the details of the given work items, keeping their order
+func (p *AzureDevOpsProvider) fetchWorkItems(ctx context.Context, ids []int) ([]AzureWorkItem, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s/_apis/wit/workitemsbatch?api-version=7.1",
+		p.baseURL, p.organization, url.PathEscape(p.project))
+
+	workItems := make([]AzureWorkItem, 0, len(ids))
+	for start := 0; start < len(ids); start += azureWorkItemsBatchSize {
+		batch := ids[start:min(start+azureWorkItemsBatchSize, len(ids))]
+		payload := map[string]interface{}{
+			"ids":         batch,
+			"fields":      azureWorkItemFields,
+			"errorPolicy": "omit",
+		}
+		var batchResp AzureWorkItemsResponse
+		if _, err := p.do(ctx, "POST", apiURL, payload, &batchResp); err != nil {
+			return nil, err
+		}
+		for _, workItem := range batchResp.Value {
+			// Deleted or inaccessible work items come back as null with the omit error policy
+			if workItem.Id != 0 {
+				workItems = append(workItems, workItem)
+			}
+		}
+	}
+	return workItems, nil
+}
+
+// do sends a request to the Azure DevOps REST API, encoding payload as the JSON
+// body when set and decoding the response into result when set
+func (p *AzureDevOpsProvider) do(ctx context.Context, method string, apiURL string, payload interface{}, result interface{}) (http.Header, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
 
 	p.setAuthHeader(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	log.Debug("Fetching Azure DevOps work items", "url", apiURL, "limit", limit)
-	
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return IssuesResponse{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return IssuesResponse{}, err
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return IssuesResponse{}, fmt.Errorf("Azure DevOps API error: %s", string(body))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Azure DevOps API error: %s", string(body))
 	}
 
-	// TODO: Parse WIQL response and fetch actual work item details
-	// This is a simplified implementation
-	return IssuesResponse{
-		Issues:     []IssueData{},
-		TotalCount: 0,
-		PageInfo:   PageInfo{},
-	}, nil
-}
-
-func (p *AzureDevOpsProvider) FetchPullRequest(prUrl string) (PullRequestData, error) {
-	// Extract PR ID from URL and fetch individual PR
-	// This is a simplified implementation
-	return PullRequestData{}, fmt.Errorf("FetchPullRequest not implemented for Azure DevOps")
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Header, nil
 }
 
 func (p *AzureDevOpsProvider) setAuthHeader(req *http.Request) {
@@ -233,13 +406,23 @@ func (p *AzureDevOpsProvider) convertAzurePRToData(azurePR AzurePullRequest) Pul
 	var state string
 	switch azurePR.Status {
 	case "completed":
-		state = "merged"
+		state = "MERGED"
 	case "abandoned":
-		state = "closed"
+		state = "CLOSED"
 	default:
-		state = "open"
+		state = "OPEN"
+	}
+
+	mergeable := "UNKNOWN"
+	switch azurePR.MergeStatus {
+	case "succeeded":
+		mergeable = "MERGEABLE"
+	case "conflicts":
+		mergeable = "CONFLICTING"
 	}
 
+	// Pull requests have no update date, fetchPullRequestDetails moves UpdatedAt
+	// to their last activity
 	return PullRequestData{
 		Number: azurePR.PullRequestId,
 		Title:  azurePR.Title,
@@ -248,16 +431,17 @@ func (p *AzureDevOpsProvider) convertAzurePRToData(azurePR AzurePullRequest) Pul
 			Login string
 		}{Login: azurePR.CreatedBy.DisplayName},
 		AuthorAssociation: "MEMBER", // Default assumption
-		UpdatedAt:         azurePR.CreationDate, // Azure doesn't separate created/updated easily
+		UpdatedAt:         latestTime(azurePR.CreationDate, azurePR.ClosedDate),
 		CreatedAt:         azurePR.CreationDate,
-		Url:               azurePR.Url,
+		Url:               p.pullRequestWebURL(azurePR),
 		State:             state,
-		Mergeable:         "UNKNOWN",
+		Mergeable:         mergeable,
 		ReviewDecision:    "",
 		Additions:         0, // Would need additional API call
 		Deletions:         0, // Would need additional API call
 		HeadRefName:       strings.TrimPrefix(azurePR.SourceRefName, "refs/heads/"),
 		BaseRefName:       strings.TrimPrefix(azurePR.TargetRefName, "refs/heads/"),
+		IsCrossRepository: azurePR.ForkSource != nil,
 		HeadRepository: struct {
 			Name string
 		}{Name: azurePR.Repository.Name},
@@ -276,13 +460,94 @@ func (p *AzureDevOpsProvider) convertAzurePRToData(azurePR AzurePullRequest) Pul
 		ReviewThreads:    ReviewThreads{},
 		ReviewRequests:   ReviewRequests{},
 		Files:            ChangedFiles{},
-		IsDraft:          false,
+		IsDraft:          azurePR.IsDraft,
 		Commits:          Commits{},
 		Labels:           PRLabels{},
 		MergeStateStatus: "",
 	}
 }
 
+// pullRequestWebURL returns the web page of a pull request, azurePR.Url being its REST API URL
+func (p *AzureDevOpsProvider) pullRequestWebURL(azurePR AzurePullRequest) string {
+	if azurePR.Repository.WebURL != "" {
+		return fmt.Sprintf("%s/pullrequest/%d", azurePR.Repository.WebURL, azurePR.PullRequestId)
+	}
+	return p.pullRequestPageURL(azurePR.PullRequestId, azurePR.Repository.Project.Name+"/"+azurePR.Repository.Name)
+}
+
+// pullRequestPageURL returns the web page of a pull request in a repository
+// named project/repo, or repo in the configured project
+func (p *AzureDevOpsProvider) pullRequestPageURL(prNumber int, repoNameWithOwner string) string {
+	project := p.project
+	parts := strings.Split(repoNameWithOwner, "/")
+	repo := parts[len(parts)-1]
+	if len(parts) >= 2 && parts[len(parts)-2] != "" {
+		project = parts[len(parts)-2]
+	}
+	return fmt.Sprintf("%s/%s/%s/_git/%s/pullrequest/%d", p.baseURL, p.organization,
+		url.PathEscape(project), url.PathEscape(repo), prNumber)
+}
+
+// latestTime returns the latest of the given times
+func latestTime(times ...time.Time) time.Time {
+	var latest time.Time
+	for _, t := range times {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// azureTagColor is used for work item tags, which have no color in Azure DevOps
+const azureTagColor = "8b949e"
+
+func (p *AzureDevOpsProvider) convertWorkItemToData(workItem AzureWorkItem) IssueData {
+	// Work item states are process specific, map the common closed states
+	state := "OPEN"
+	switch workItem.Fields.State {
+	case "Closed", "Done", "Removed", "Resolved", "Completed", "Cut":
+		state = "CLOSED"
+	}
+
+	project := workItem.Fields.TeamProject
+	if project == "" {
+		project = p.project
+	}
+
+	issue := IssueData{
+		Number:            workItem.Id,
+		Title:             workItem.Fields.Title,
+		Body:              workItem.Fields.Description,
+		State:             state,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         workItem.Fields.ChangedDate,
+		CreatedAt:         workItem.Fields.CreatedDate,
+		Url:               fmt.Sprintf("%s/%s/%s/_workitems/edit/%d", p.baseURL, p.organization, url.PathEscape(project), workItem.Id),
+		Repository: Repository{
+			Name:          project,
+			NameWithOwner: fmt.Sprintf("%s/%s", p.organization, project),
+		},
+		Comments: IssueComments{TotalCount: workItem.Fields.CommentCount},
+	}
+	if workItem.Fields.CreatedBy != nil {
+		issue.Author.Login = workItem.Fields.CreatedBy.DisplayName
+	}
+	if workItem.Fields.AssignedTo != nil {
+		issue.Assignees = Assignees{
+			Nodes:      []Assignee{{Login: workItem.Fields.AssignedTo.DisplayName}},
+			TotalCount: 1,
+		}
+	}
+	for _, tag := range strings.Split(workItem.Fields.Tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			issue.Labels.Nodes = append(issue.Labels.Nodes, Label{Name: tag, Color: azureTagColor})
+		}
+	}
+
+	return issue
+}
+
 // Helper type to implement ItemData interface for Azure DevOps items
 type AzureDevOpsItemData struct {
 	title         string
@@ -332,55 +597,52 @@ func (d AzureDevOpsItemData) GetCreatedAt() time.Time {
 func (p *AzureDevOpsProvider) GetDiffCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// For Azure DevOps, we use the web URL approach since there's no native CLI diff command
 	// We'll open the PR diff page in the browser
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d?_a=files", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner) + "?_a=files"}, nil
 }
 
 func (p *AzureDevOpsProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
-	// Azure DevOps doesn't have a direct checkout command like gh
-	// We'll need to implement this using git commands
-	// For now, return an error indicating this isn't supported yet
-	return nil, fmt.Errorf("checkout command not yet implemented for Azure DevOps - use git fetch and git checkout manually")
+	// Azure DevOps doesn't have a direct checkout command like gh,
+	// pull requests are checked out with git through PullRequestRef instead
+	return nil, fmt.Errorf("Azure DevOps pull requests are checked out with git")
+}
+
+// PullRequestRef returns the merge ref Azure DevOps keeps for every pull request
+func (p *AzureDevOpsProvider) PullRequestRef(prNumber int) string {
+	return fmt.Sprintf("refs/pull/%d/merge", prNumber)
 }
 
 func (p *AzureDevOpsProvider) GetMergeCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI merge command
 	// Open the PR page where user can manually merge
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
 
 func (p *AzureDevOpsProvider) GetCloseCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI close command
 	// Open the PR page where user can manually close
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
 
 func (p *AzureDevOpsProvider) GetReopenCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI reopen command
 	// Open the PR page where user can manually reopen
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
 
 func (p *AzureDevOpsProvider) GetReadyCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI ready command
 	// Open the PR page where user can manually mark as ready
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
 
 func (p *AzureDevOpsProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI update command
 	// Open the PR page where user can manually update
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
 
 func (p *AzureDevOpsProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
 	// Azure DevOps doesn't have a CLI watch checks command
 	// Open the PR page where user can monitor builds/checks
-	return []string{"open", fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", 
-		p.baseURL, p.organization, p.project, prNumber)}, nil
+	return []string{"open", p.pullRequestPageURL(prNumber, repoNameWithOwner)}, nil
 }
\ No newline at end of file
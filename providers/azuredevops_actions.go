@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/charmbracelet/log"
+)
+
+// azureApproveVote is the reviewer vote for "Approved"
+const azureApproveVote = 10
+
+// azureMergeStrategies maps the configured merge strategy onto the completion
+// options of Azure DevOps, accepting both the GitHub and the Azure DevOps names
+var azureMergeStrategies = map[string]string{
+	"":              "noFastForward",
+	"merge":         "noFastForward",
+	"noFastForward": "noFastForward",
+	"squash":        "squash",
+	"rebase":        "rebase",
+	"rebaseMerge":   "rebaseMerge",
+}
+
+func (p *AzureDevOpsProvider) ApprovePullRequest(prNumber int, repoNameWithOwner string, comment string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+	connectionData, err := p.fetchConnectionData(ctx)
+	if err != nil {
+		return err
+	}
+
+	reviewerURL := fmt.Sprintf("%s/reviewers/%s?api-version=7.1",
+		p.pullRequestAPIURL(pr), url.PathEscape(connectionData.AuthenticatedUser.Id))
+	if _, err := p.do(ctx, "PUT", reviewerURL, map[string]int{"vote": azureApproveVote}, nil); err != nil {
+		return err
+	}
+
+	if comment == "" {
+		return nil
+	}
+	threadsURL := fmt.Sprintf("%s/threads?api-version=7.1", p.pullRequestAPIURL(pr))
+	thread := map[string]interface{}{
+		"comments": []map[string]interface{}{{"content": comment, "commentType": "text"}},
+		"status":   "active",
+	}
+	_, err = p.do(ctx, "POST", threadsURL, thread, nil)
+	return err
+}
+
+func (p *AzureDevOpsProvider) MergePullRequest(prNumber int, repoNameWithOwner string) error {
+	strategy, ok := azureMergeStrategies[p.config.MergeStrategy]
+	if !ok {
+		return fmt.Errorf("unknown Azure DevOps merge strategy %q, use merge, squash, rebase or rebaseMerge", p.config.MergeStrategy)
+	}
+
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+
+	// Completing requires the last merge source commit of the pull request
+	return p.updatePullRequest(ctx, pr, map[string]interface{}{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pr.LastMergeSourceCommit.CommitId},
+		"completionOptions":     map[string]string{"mergeStrategy": strategy},
+	})
+}
+
+func (p *AzureDevOpsProvider) ClosePullRequest(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+	return p.updatePullRequest(ctx, pr, map[string]string{"status": "abandoned"})
+}
+
+func (p *AzureDevOpsProvider) ReopenPullRequest(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+	return p.updatePullRequest(ctx, pr, map[string]string{"status": "active"})
+}
+
+func (p *AzureDevOpsProvider) MarkPullRequestReady(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return err
+	}
+	return p.updatePullRequest(ctx, pr, map[string]bool{"isDraft": false})
+}
+
+// fetchAzurePullRequest fetches a pull request by its id, which is unique
+// within the organization, to find its project and repository
+func (p *AzureDevOpsProvider) fetchAzurePullRequest(ctx context.Context, prNumber int) (AzurePullRequest, error) {
+	apiURL := fmt.Sprintf("%s/%s/_apis/git/pullrequests/%d?api-version=7.1", p.baseURL, p.organization, prNumber)
+
+	var pr AzurePullRequest
+	if _, err := p.do(ctx, "GET", apiURL, nil, &pr); err != nil {
+		return AzurePullRequest{}, err
+	}
+	return pr, nil
+}
+
+func (p *AzureDevOpsProvider) updatePullRequest(ctx context.Context, pr AzurePullRequest, payload interface{}) error {
+	log.Debug("Updating Azure DevOps pull request", "id", pr.PullRequestId, "payload", payload)
+	_, err := p.do(ctx, "PATCH", p.pullRequestAPIURL(pr)+"?api-version=7.1", payload, nil)
+	return err
+}
+
+func (p *AzureDevOpsProvider) pullRequestAPIURL(pr AzurePullRequest) string {
+	return fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/pullrequests/%d",
+		p.baseURL, p.organization, url.PathEscape(pr.Repository.Project.Name), pr.Repository.Id, pr.PullRequestId)
+}
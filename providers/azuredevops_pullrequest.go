@@ -0,0 +1,555 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// azurePullRequestURLRegexp matches the web and the REST API URL of a pull request
+var azurePullRequestURLRegexp = regexp.MustCompile(`(?i)/pullrequests?/(\d+)`)
+
+type AzureReviewer struct {
+	AzureIdentityRef
+	Vote        int  `json:"vote"`
+	IsRequired  bool `json:"isRequired"`
+	IsContainer bool `json:"isContainer"`
+}
+
+type AzureCommentThread struct {
+	Id            int  `json:"id"`
+	IsDeleted     bool `json:"isDeleted"`
+	ThreadContext *struct {
+		FilePath       string             `json:"filePath"`
+		RightFileStart *AzureFilePosition `json:"rightFileStart"`
+		RightFileEnd   *AzureFilePosition `json:"rightFileEnd"`
+	} `json:"threadContext"`
+	Comments        []AzureComment                `json:"comments"`
+	Properties      map[string]AzurePropertyValue `json:"properties"`
+	LastUpdatedDate time.Time                     `json:"lastUpdatedDate"`
+}
+
+type AzureFilePosition struct {
+	Line int `json:"line"`
+}
+
+type AzurePropertyValue struct {
+	Value interface{} `json:"$value"`
+}
+
+type AzureComment struct {
+	Author          AzureIdentityRef `json:"author"`
+	Content         string           `json:"content"`
+	CommentType     string           `json:"commentType"`
+	IsDeleted       bool             `json:"isDeleted"`
+	LastUpdatedDate time.Time        `json:"lastUpdatedDate"`
+}
+
+type AzurePolicyEvaluation struct {
+	Status string `json:"status"`
+	// Context is set for build validation policies
+	Context *struct {
+		BuildId int `json:"buildId"`
+	} `json:"context"`
+	Configuration struct {
+		IsBlocking bool `json:"isBlocking"`
+		Type       struct {
+			DisplayName string `json:"displayName"`
+		} `json:"type"`
+		Settings struct {
+			DisplayName string `json:"displayName"`
+		} `json:"settings"`
+	} `json:"configuration"`
+}
+
+type AzureBuild struct {
+	Id         int    `json:"id"`
+	Status     string `json:"status"`
+	Result     string `json:"result"`
+	Definition struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"definition"`
+}
+
+type AzureIteration struct {
+	Id              int `json:"id"`
+	SourceRefCommit struct {
+		CommitId string `json:"commitId"`
+	} `json:"sourceRefCommit"`
+	CommonRefCommit struct {
+		CommitId string `json:"commitId"`
+	} `json:"commonRefCommit"`
+}
+
+type AzureIterationChange struct {
+	ChangeType   string `json:"changeType"`
+	OriginalPath string `json:"originalPath"`
+	Item         struct {
+		Path     string `json:"path"`
+		IsFolder bool   `json:"isFolder"`
+	} `json:"item"`
+}
+
+type AzureFileDiff struct {
+	Path           string `json:"path"`
+	LineDiffBlocks []struct {
+		ChangeType         azureLineDiffChangeType `json:"changeType"`
+		OriginalLinesCount int                     `json:"originalLinesCount"`
+		ModifiedLinesCount int                     `json:"modifiedLinesCount"`
+	} `json:"lineDiffBlocks"`
+}
+
+// azureLineDiffChangeType is serialized either by name or by its enum value
+type azureLineDiffChangeType string
+
+func (t *azureLineDiffChangeType) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*t = azureLineDiffChangeType(name)
+		return nil
+	}
+	var value int
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+	*t = azureLineDiffChangeType([]string{"none", "add", "delete", "edit"}[min(max(value, 0), 3)])
+	return nil
+}
+
+func (p *AzureDevOpsProvider) FetchPullRequest(ctx context.Context, prUrl string) (PullRequestData, error) {
+	matches := azurePullRequestURLRegexp.FindStringSubmatch(prUrl)
+	if len(matches) != 2 {
+		return PullRequestData{}, fmt.Errorf("invalid Azure DevOps pull request URL: %s", prUrl)
+	}
+	prNumber, _ := strconv.Atoi(matches[1])
+
+	log.Debug("Fetching Azure DevOps pull request", "url", prUrl)
+	azurePR, err := p.fetchAzurePullRequest(ctx, prNumber)
+	if err != nil {
+		return PullRequestData{}, err
+	}
+	pr := p.fetchPullRequestDetails(ctx, azurePR, true)
+	log.Debug("Successfully fetched Azure DevOps pull request", "url", prUrl)
+
+	return pr, nil
+}
+
+// fetchPullRequestsDetails fetches the details of the listed pull requests concurrently
+func (p *AzureDevOpsProvider) fetchPullRequestsDetails(ctx context.Context, azurePRs []AzurePullRequest) []PullRequestData {
+	prs := make([]PullRequestData, len(azurePRs))
+	forEachConcurrently(len(azurePRs), func(i int) {
+		prs[i] = p.fetchPullRequestDetails(ctx, azurePRs[i], false)
+	})
+	return prs
+}
+
+// fetchPullRequestDetails converts a pull request and adds its comment threads, builds
+// and policy evaluations. withFiles also fetches the changed files shown in the sidebar.
+// Details that fail to load are left empty rather than failing the pull request
+func (p *AzureDevOpsProvider) fetchPullRequestDetails(ctx context.Context, azurePR AzurePullRequest, withFiles bool) PullRequestData {
+	pr := p.convertAzurePRToData(azurePR)
+
+	var threads struct {
+		Value []AzureCommentThread `json:"value"`
+	}
+	if _, err := p.do(ctx, "GET", p.pullRequestAPIURL(azurePR)+"/threads?api-version=7.1", nil, &threads); err != nil {
+		log.Debug("Failed fetching Azure DevOps comment threads", "pr", pr.Url, "err", err)
+	}
+	pr.ReviewThreads, pr.Comments = azureCommentThreads(threads.Value)
+	// Pushes, votes and status changes are recorded as system threads too
+	for _, thread := range threads.Value {
+		pr.UpdatedAt = latestTime(pr.UpdatedAt, thread.LastUpdatedDate)
+	}
+	pr.Reviews, pr.ReviewRequests, pr.ReviewDecision = azureReviews(azurePR, threads.Value)
+
+	evaluations, err := p.fetchPolicyEvaluations(ctx, azurePR)
+	if err != nil {
+		log.Debug("Failed fetching Azure DevOps policy evaluations", "pr", pr.Url, "err", err)
+	}
+	builds, err := p.fetchPullRequestBuilds(ctx, azurePR)
+	if err != nil {
+		log.Debug("Failed fetching Azure DevOps builds", "pr", pr.Url, "err", err)
+	}
+	pr.Commits = azureChecks(builds, evaluations)
+	pr.MergeStateStatus = azureMergeStateStatus(azurePR, evaluations)
+
+	if withFiles {
+		files, err := p.fetchChangedFiles(ctx, azurePR)
+		if err != nil {
+			log.Debug("Failed fetching Azure DevOps changed files", "pr", pr.Url, "err", err)
+		}
+		pr.Files = files
+		for _, file := range files.Nodes {
+			pr.Additions += file.Additions
+			pr.Deletions += file.Deletions
+		}
+	}
+
+	return pr
+}
+
+// azureReviews maps reviewer votes onto reviews, and reviewers that haven't voted
+// onto review requests. Votes have no date, so it is taken from their vote update thread
+func azureReviews(azurePR AzurePullRequest, threads []AzureCommentThread) (Reviews, ReviewRequests, string) {
+	votedAt := map[string]time.Time{}
+	for _, thread := range threads {
+		if thread.Properties["CodeReviewThreadType"].Value != "VoteUpdate" || len(thread.Comments) == 0 {
+			continue
+		}
+		comment := thread.Comments[0]
+		if comment.LastUpdatedDate.After(votedAt[comment.Author.Id]) {
+			votedAt[comment.Author.Id] = comment.LastUpdatedDate
+		}
+	}
+
+	var reviews Reviews
+	var requests ReviewRequests
+	approved, changesRequested, requiredPending := 0, 0, 0
+	for _, reviewer := range azurePR.Reviewers {
+		if reviewer.Vote == 0 {
+			requests.Nodes = append(requests.Nodes, ReviewRequest{})
+			if reviewer.IsRequired {
+				requiredPending++
+			}
+			continue
+		}
+
+		var review Review
+		review.Author.Login = reviewer.DisplayName
+		review.UpdatedAt = votedAt[reviewer.Id]
+		switch {
+		case reviewer.Vote >= 10:
+			review.State = "APPROVED"
+			approved++
+		case reviewer.Vote > 0:
+			review.State = "APPROVED"
+			review.Body = "Approved with suggestions"
+			approved++
+		case reviewer.Vote <= -10:
+			review.State = "CHANGES_REQUESTED"
+			review.Body = "Rejected"
+			changesRequested++
+		default:
+			review.State = "CHANGES_REQUESTED"
+			review.Body = "Waiting for author"
+			changesRequested++
+		}
+		reviews.Nodes = append(reviews.Nodes, review)
+	}
+	reviews.TotalCount = len(reviews.Nodes)
+	requests.TotalCount = len(requests.Nodes)
+
+	// Like on GitHub, there is no decision while no review is required
+	decision := ""
+	switch {
+	case changesRequested > 0:
+		decision = "CHANGES_REQUESTED"
+	case requiredPending > 0:
+		decision = "REVIEW_REQUIRED"
+	case approved > 0:
+		decision = "APPROVED"
+	}
+	return reviews, requests, decision
+}
+
+// azureCommentThreads maps threads on a file onto review threads and the others
+// onto comments. System comments, like vote updates, are left out
+func azureCommentThreads(threads []AzureCommentThread) (ReviewThreads, Comments) {
+	var reviewThreads ReviewThreads
+	var comments Comments
+	for _, thread := range threads {
+		if thread.IsDeleted {
+			continue
+		}
+
+		if thread.ThreadContext != nil && thread.ThreadContext.FilePath != "" {
+			reviewThread := ReviewThread{
+				Id:   strconv.Itoa(thread.Id),
+				Path: strings.TrimPrefix(thread.ThreadContext.FilePath, "/"),
+			}
+			if start := thread.ThreadContext.RightFileStart; start != nil {
+				reviewThread.StartLine = start.Line
+				reviewThread.OriginalLine = start.Line
+			}
+			if end := thread.ThreadContext.RightFileEnd; end != nil {
+				reviewThread.Line = end.Line
+			}
+			for _, azureComment := range thread.Comments {
+				if azureComment.IsDeleted || azureComment.CommentType == "system" {
+					continue
+				}
+				var comment ReviewComment
+				comment.Author.Login = azureComment.Author.DisplayName
+				comment.Body = azureComment.Content
+				comment.UpdatedAt = azureComment.LastUpdatedDate
+				comment.StartLine = reviewThread.StartLine
+				comment.Line = reviewThread.Line
+				reviewThread.Comments.Nodes = append(reviewThread.Comments.Nodes, comment)
+			}
+			reviewThread.Comments.TotalCount = len(reviewThread.Comments.Nodes)
+			if reviewThread.Comments.TotalCount > 0 {
+				reviewThreads.Nodes = append(reviewThreads.Nodes, reviewThread)
+			}
+			continue
+		}
+
+		for _, azureComment := range thread.Comments {
+			if azureComment.IsDeleted || azureComment.CommentType == "system" {
+				continue
+			}
+			var comment Comment
+			comment.Author.Login = azureComment.Author.DisplayName
+			comment.Body = azureComment.Content
+			comment.UpdatedAt = azureComment.LastUpdatedDate
+			comments.Nodes = append(comments.Nodes, comment)
+		}
+	}
+	comments.TotalCount = len(comments.Nodes)
+	return reviewThreads, comments
+}
+
+// fetchPolicyEvaluations fetches the branch policy evaluations of a pull request
+func (p *AzureDevOpsProvider) fetchPolicyEvaluations(ctx context.Context, azurePR AzurePullRequest) ([]AzurePolicyEvaluation, error) {
+	params := url.Values{}
+	params.Set("artifactId", fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d",
+		azurePR.Repository.Project.Id, azurePR.PullRequestId))
+	params.Set("api-version", "7.1-preview.1")
+	apiURL := fmt.Sprintf("%s/%s/%s/_apis/policy/evaluations?%s",
+		p.baseURL, p.organization, url.PathEscape(azurePR.Repository.Project.Name), params.Encode())
+
+	var evaluations struct {
+		Value []AzurePolicyEvaluation `json:"value"`
+	}
+	if _, err := p.do(ctx, "GET", apiURL, nil, &evaluations); err != nil {
+		return nil, err
+	}
+	return evaluations.Value, nil
+}
+
+// fetchPullRequestBuilds fetches the latest pipeline build of each definition
+// that ran for a pull request
+func (p *AzureDevOpsProvider) fetchPullRequestBuilds(ctx context.Context, azurePR AzurePullRequest) ([]AzureBuild, error) {
+	params := url.Values{}
+	params.Set("branchName", fmt.Sprintf("refs/pull/%d/merge", azurePR.PullRequestId))
+	params.Set("repositoryId", azurePR.Repository.Id)
+	params.Set("repositoryType", "TfsGit")
+	params.Set("queryOrder", "queueTimeDescending")
+	params.Set("$top", "50")
+	params.Set("api-version", "7.1")
+	apiURL := fmt.Sprintf("%s/%s/%s/_apis/build/builds?%s",
+		p.baseURL, p.organization, url.PathEscape(azurePR.Repository.Project.Name), params.Encode())
+
+	var builds struct {
+		Value []AzureBuild `json:"value"`
+	}
+	if _, err := p.do(ctx, "GET", apiURL, nil, &builds); err != nil {
+		return nil, err
+	}
+
+	latest := make([]AzureBuild, 0, len(builds.Value))
+	seen := map[int]bool{}
+	for _, build := range builds.Value {
+		if seen[build.Definition.Id] {
+			continue
+		}
+		seen[build.Definition.Id] = true
+		latest = append(latest, build)
+	}
+	return latest, nil
+}
+
+// azureChecks maps pipeline builds and policy evaluations onto GitHub style check runs.
+// Build validation policies are left out when their build is already listed
+func azureChecks(builds []AzureBuild, evaluations []AzurePolicyEvaluation) Commits {
+	var node CommitNode
+	contexts := &node.Commit.StatusCheckRollup.Contexts
+	buildIds := map[int]bool{}
+
+	for _, build := range builds {
+		buildIds[build.Id] = true
+		checkRun := CheckRun{Name: build.Definition.Name, Status: "COMPLETED"}
+		checkRun.CheckSuite.Creator.Login = "azure-pipelines"
+
+		switch build.Status {
+		case "notStarted", "postponed":
+			checkRun.Status = "QUEUED"
+		case "inProgress", "cancelling":
+			checkRun.Status = "IN_PROGRESS"
+		default:
+			checkRun.Conclusion = azureBuildConclusion(build.Result)
+		}
+		contexts.Nodes = append(contexts.Nodes, CheckContext{Typename: "CheckRun", CheckRun: checkRun})
+	}
+
+	for _, evaluation := range evaluations {
+		if evaluation.Status == "notApplicable" {
+			continue
+		}
+		if evaluation.Context != nil && buildIds[evaluation.Context.BuildId] {
+			continue
+		}
+
+		// Policies like required reviewers or work item linking have no name of their own
+		checkRun := CheckRun{Name: evaluation.Configuration.Type.DisplayName, Status: "COMPLETED"}
+		if name := evaluation.Configuration.Settings.DisplayName; name != "" {
+			checkRun.Name = name
+			checkRun.CheckSuite.WorkflowRun.Workflow.Name = evaluation.Configuration.Type.DisplayName
+		}
+		checkRun.CheckSuite.Creator.Login = "policy"
+
+		switch evaluation.Status {
+		case "approved":
+			checkRun.Conclusion = "SUCCESS"
+		case "rejected", "broken":
+			checkRun.Conclusion = "FAILURE"
+			if !evaluation.Configuration.IsBlocking {
+				checkRun.Conclusion = "NEUTRAL"
+			}
+		case "running":
+			checkRun.Status = "IN_PROGRESS"
+		case "queued":
+			checkRun.Status = "QUEUED"
+		}
+		contexts.Nodes = append(contexts.Nodes, CheckContext{Typename: "CheckRun", CheckRun: checkRun})
+	}
+
+	if len(contexts.Nodes) == 0 {
+		return Commits{}
+	}
+	contexts.TotalCount = len(contexts.Nodes)
+	return Commits{Nodes: []CommitNode{node}, TotalCount: 1}
+}
+
+func azureBuildConclusion(result string) string {
+	switch result {
+	case "succeeded":
+		return "SUCCESS"
+	case "failed":
+		return "FAILURE"
+	case "canceled":
+		return "CANCELLED"
+	}
+	// partiallySucceeded and none
+	return "NEUTRAL"
+}
+
+// azureMergeStateStatus derives the GitHub merge state of a pull request from its
+// merge status and whether its blocking policies are approved
+func azureMergeStateStatus(azurePR AzurePullRequest, evaluations []AzurePolicyEvaluation) string {
+	switch azurePR.MergeStatus {
+	case "conflicts":
+		return "DIRTY"
+	case "succeeded":
+		for _, evaluation := range evaluations {
+			if evaluation.Configuration.IsBlocking && evaluation.Status != "approved" && evaluation.Status != "notApplicable" {
+				return "BLOCKED"
+			}
+		}
+		return "CLEAN"
+	}
+	return "UNKNOWN"
+}
+
+// fetchChangedFiles fetches the files changed by the latest iteration of a pull
+// request, with their line counts from the file diffs API
+func (p *AzureDevOpsProvider) fetchChangedFiles(ctx context.Context, azurePR AzurePullRequest) (ChangedFiles, error) {
+	prURL := p.pullRequestAPIURL(azurePR)
+
+	var iterations struct {
+		Value []AzureIteration `json:"value"`
+	}
+	if _, err := p.do(ctx, "GET", prURL+"/iterations?api-version=7.1", nil, &iterations); err != nil {
+		return ChangedFiles{}, err
+	}
+	if len(iterations.Value) == 0 {
+		return ChangedFiles{}, nil
+	}
+	iteration := iterations.Value[len(iterations.Value)-1]
+
+	var changes struct {
+		ChangeEntries []AzureIterationChange `json:"changeEntries"`
+	}
+	changesURL := fmt.Sprintf("%s/iterations/%d/changes?$compareTo=0&$top=2000&api-version=7.1", prURL, iteration.Id)
+	if _, err := p.do(ctx, "GET", changesURL, nil, &changes); err != nil {
+		return ChangedFiles{}, err
+	}
+
+	files := ChangedFiles{}
+	fileDiffParams := make([]map[string]string, 0, len(changes.ChangeEntries))
+	for _, change := range changes.ChangeEntries {
+		if change.Item.IsFolder {
+			continue
+		}
+		files.Nodes = append(files.Nodes, ChangedFile{
+			Path:       strings.TrimPrefix(change.Item.Path, "/"),
+			ChangeType: azureChangeType(change.ChangeType),
+		})
+		fileDiffParams = append(fileDiffParams, map[string]string{
+			"path":         change.Item.Path,
+			"originalPath": change.OriginalPath,
+		})
+	}
+	files.TotalCount = len(files.Nodes)
+	if len(fileDiffParams) == 0 {
+		return files, nil
+	}
+
+	var diffs []AzureFileDiff
+	diffsURL := fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/FileDiffs?api-version=7.1-preview.1",
+		p.baseURL, p.organization, url.PathEscape(azurePR.Repository.Project.Name), azurePR.Repository.Id)
+	payload := map[string]interface{}{
+		"baseVersionCommit":   iteration.CommonRefCommit.CommitId,
+		"targetVersionCommit": iteration.SourceRefCommit.CommitId,
+		"fileDiffParams":      fileDiffParams,
+	}
+	if _, err := p.do(ctx, "POST", diffsURL, payload, &diffs); err != nil {
+		// The files are still worth showing without line counts
+		log.Debug("Failed fetching Azure DevOps file diffs", "pr", azurePR.PullRequestId, "err", err)
+		return files, nil
+	}
+
+	lineCounts := map[string][2]int{}
+	for _, diff := range diffs {
+		var counts [2]int
+		for _, block := range diff.LineDiffBlocks {
+			switch block.ChangeType {
+			case "add":
+				counts[0] += block.ModifiedLinesCount
+			case "delete":
+				counts[1] += block.OriginalLinesCount
+			case "edit":
+				counts[0] += block.ModifiedLinesCount
+				counts[1] += block.OriginalLinesCount
+			}
+		}
+		lineCounts[strings.TrimPrefix(diff.Path, "/")] = counts
+	}
+	for i, file := range files.Nodes {
+		counts := lineCounts[file.Path]
+		files.Nodes[i].Additions = counts[0]
+		files.Nodes[i].Deletions = counts[1]
+	}
+
+	return files, nil
+}
+
+// azureChangeType maps a version control change type, e.g. "edit, rename", onto a GitHub one
+func azureChangeType(changeType string) string {
+	switch {
+	case strings.Contains(changeType, "rename"):
+		return "RENAMED"
+	case strings.Contains(changeType, "add"):
+		return "ADDED"
+	case strings.Contains(changeType, "delete"):
+		return "DELETED"
+	}
+	return "MODIFIED"
+}
@@ -0,0 +1,255 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// azureIgnoredQualifiers are GitHub qualifiers that have no meaning for Azure DevOps
+// but are commonly part of section filters, so they are dropped without a warning
+var azureIgnoredQualifiers = map[string]bool{
+	"sort":     true,
+	"archived": true,
+}
+
+// azureClosedWorkItemStates are the closed states of the default process templates
+var azureClosedWorkItemStates = []string{"Closed", "Done", "Removed", "Resolved", "Completed", "Cut"}
+
+// azurePullRequestSearch is a section filter translated into the pull requests API
+type azurePullRequestSearch struct {
+	project    string
+	repository string
+	params     url.Values
+	// unsupported lists the qualifiers that couldn't be translated, as written by the user
+	unsupported []string
+}
+
+// buildPullRequestSearch translates a GitHub style section filter into the
+// searchCriteria parameters of the Azure DevOps pull requests API
+func (p *AzureDevOpsProvider) buildPullRequestSearch(ctx context.Context, query string) (azurePullRequestSearch, error) {
+	search := azurePullRequestSearch{project: p.project, params: url.Values{}}
+	search.params.Set("searchCriteria.status", "active")
+
+	parsed := ParseSearchQuery(query)
+	for _, filter := range parsed.Filters {
+		if azureIgnoredQualifiers[filter.Key] || (filter.Key == "is" && (filter.Value == "pr" || filter.Value == "issue")) {
+			continue
+		}
+		if filter.Negated {
+			search.unsupported = append(search.unsupported, formatSearchFilter(filter))
+			continue
+		}
+
+		switch filter.Key {
+		case "is", "state", "status":
+			status, ok := azurePullRequestStatus(filter.Value)
+			if !ok {
+				search.unsupported = append(search.unsupported, formatSearchFilter(filter))
+				continue
+			}
+			search.params.Set("searchCriteria.status", status)
+		case "author", "createdby", "creator":
+			id, err := p.resolveIdentityId(ctx, filter.Value)
+			if err != nil {
+				return azurePullRequestSearch{}, err
+			}
+			search.params.Set("searchCriteria.creatorId", id)
+		case "review-requested", "reviewed-by", "reviewer":
+			id, err := p.resolveIdentityId(ctx, filter.Value)
+			if err != nil {
+				return azurePullRequestSearch{}, err
+			}
+			search.params.Set("searchCriteria.reviewerId", id)
+		case "repo":
+			// Accepts repo, project/repo and org/project/repo as written by git.GetRepoShortName
+			parts := strings.Split(filter.Value, "/")
+			search.repository = parts[len(parts)-1]
+			if len(parts) >= 2 {
+				search.project = parts[len(parts)-2]
+			}
+		case "base", "target":
+			search.params.Set("searchCriteria.targetRefName", azureRefName(filter.Value))
+		case "head", "source":
+			search.params.Set("searchCriteria.sourceRefName", azureRefName(filter.Value))
+		default:
+			search.unsupported = append(search.unsupported, formatSearchFilter(filter))
+		}
+	}
+	if text := parsed.Text(); text != "" {
+		search.unsupported = append(search.unsupported, fmt.Sprintf("%q (free text search)", text))
+	}
+
+	if len(search.unsupported) > 0 {
+		log.Debug("Unsupported Azure DevOps pull request filters", "filters", search.unsupported)
+	}
+	return search, nil
+}
+
+func azurePullRequestStatus(value string) (string, bool) {
+	switch strings.ToLower(value) {
+	case "open", "active":
+		return "active", true
+	case "closed", "abandoned":
+		return "abandoned", true
+	case "merged", "completed":
+		return "completed", true
+	case "all":
+		return "all", true
+	}
+	return "", false
+}
+
+// buildWorkItemQuery translates a GitHub style section filter into a WIQL query
+func (p *AzureDevOpsProvider) buildWorkItemQuery(query string) (string, []string) {
+	clauses := []string{fmt.Sprintf("[System.TeamProject] = %s", wiqlString(p.project))}
+	var unsupported []string
+
+	parsed := ParseSearchQuery(query)
+	for _, filter := range parsed.Filters {
+		if azureIgnoredQualifiers[filter.Key] || (filter.Key == "is" && (filter.Value == "pr" || filter.Value == "issue")) {
+			continue
+		}
+
+		var clause string
+		switch filter.Key {
+		case "is":
+			switch strings.ToLower(filter.Value) {
+			case "open":
+				clause = fmt.Sprintf("[System.State] NOT IN (%s)", wiqlList(azureClosedWorkItemStates))
+			case "closed":
+				clause = fmt.Sprintf("[System.State] IN (%s)", wiqlList(azureClosedWorkItemStates))
+			}
+		case "state", "status":
+			clause = fmt.Sprintf("[System.State] = %s", wiqlString(filter.Value))
+		case "type", "workitemtype":
+			clause = fmt.Sprintf("[System.WorkItemType] = %s", wiqlString(filter.Value))
+		case "assignee", "assignedto":
+			clause = fmt.Sprintf("[System.AssignedTo] = %s", wiqlIdentity(filter.Value))
+		case "author", "createdby", "creator":
+			clause = fmt.Sprintf("[System.CreatedBy] = %s", wiqlIdentity(filter.Value))
+		case "label", "tag":
+			clause = fmt.Sprintf("[System.Tags] CONTAINS %s", wiqlString(filter.Value))
+		case "area", "areapath":
+			clause = fmt.Sprintf("[System.AreaPath] UNDER %s", wiqlString(filter.Value))
+		case "iteration", "iterationpath":
+			clause = fmt.Sprintf("[System.IterationPath] UNDER %s", wiqlString(filter.Value))
+		}
+
+		if clause == "" {
+			unsupported = append(unsupported, formatSearchFilter(filter))
+			continue
+		}
+		if filter.Negated {
+			clause = "NOT " + clause
+		}
+		clauses = append(clauses, clause)
+	}
+	for _, term := range parsed.Terms {
+		clauses = append(clauses, fmt.Sprintf("[System.Title] CONTAINS %s", wiqlString(term)))
+	}
+
+	if len(unsupported) > 0 {
+		log.Debug("Unsupported Azure DevOps work item filters", "filters", unsupported)
+	}
+	wiql := fmt.Sprintf("SELECT [System.Id] FROM workitems WHERE %s ORDER BY [System.ChangedDate] DESC",
+		strings.Join(clauses, " AND "))
+	return wiql, unsupported
+}
+
+// resolveIdentityId returns the id of a user for searchCriteria parameters,
+// which only accept ids. @me is resolved to the authenticated user
+func (p *AzureDevOpsProvider) resolveIdentityId(ctx context.Context, user string) (string, error) {
+	if user == "@me" {
+		connectionData, err := p.fetchConnectionData(ctx)
+		if err != nil {
+			return "", err
+		}
+		return connectionData.AuthenticatedUser.Id, nil
+	}
+
+	params := url.Values{}
+	params.Set("searchFilter", "General")
+	params.Set("filterValue", user)
+	params.Set("api-version", "7.1")
+	apiURL := fmt.Sprintf("%s/_apis/identities?%s", p.identityBaseURL(), params.Encode())
+
+	var identities struct {
+		Value []struct {
+			Id string `json:"id"`
+		} `json:"value"`
+	}
+	if _, err := p.do(ctx, "GET", apiURL, nil, &identities); err != nil {
+		return "", err
+	}
+	if len(identities.Value) == 0 {
+		return "", fmt.Errorf("no Azure DevOps user found for %q", user)
+	}
+	return identities.Value[0].Id, nil
+}
+
+type AzureConnectionData struct {
+	AuthenticatedUser struct {
+		Id                  string `json:"id"`
+		ProviderDisplayName string `json:"providerDisplayName"`
+		Properties          struct {
+			Account struct {
+				Value string `json:"$value"`
+			} `json:"Account"`
+		} `json:"properties"`
+	} `json:"authenticatedUser"`
+}
+
+func (p *AzureDevOpsProvider) fetchConnectionData(ctx context.Context) (AzureConnectionData, error) {
+	p.connectionDataMu.Lock()
+	defer p.connectionDataMu.Unlock()
+	if p.connectionData != nil {
+		return *p.connectionData, nil
+	}
+	var connectionData AzureConnectionData
+	apiURL := fmt.Sprintf("%s/%s/_apis/connectionData", p.baseURL, p.organization)
+	if _, err := p.do(ctx, "GET", apiURL, nil, &connectionData); err != nil {
+		return AzureConnectionData{}, err
+	}
+	p.connectionData = &connectionData
+	return connectionData, nil
+}
+
+// identityBaseURL returns the URL of the identity service, which lives on its
+// own host for Azure DevOps Services and on the collection for Azure DevOps Server
+func (p *AzureDevOpsProvider) identityBaseURL() string {
+	if p.baseURL == "https://dev.azure.com" {
+		return fmt.Sprintf("https://vssps.dev.azure.com/%s", p.organization)
+	}
+	return fmt.Sprintf("%s/%s", p.baseURL, p.organization)
+}
+
+func azureRefName(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
+
+func wiqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func wiqlList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = wiqlString(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// wiqlIdentity maps @me onto the WIQL @Me macro
+func wiqlIdentity(value string) string {
+	if value == "@me" {
+		return "@Me"
+	}
+	return wiqlString(value)
+}
@@ -0,0 +1,506 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func newAzureDevOpsServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+
+	server.handle("/org/_apis/connectionData", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"authenticatedUser": map[string]string{"id": "me-id", "providerDisplayName": "Me"},
+		})
+	})
+	server.handle("/org/_apis/identities", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "alice@example.com", r.URL.Query().Get("filterValue"))
+		server.writeJSON(w, map[string]interface{}{"value": []map[string]string{{"id": "alice-id"}}})
+	})
+	server.handle("/org/other/_apis/git/repositories/web/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "completed", q.Get("searchCriteria.status"))
+		assert.Equal(t, "me-id", q.Get("searchCriteria.reviewerId"))
+		assert.Equal(t, "alice-id", q.Get("searchCriteria.creatorId"))
+		assert.Equal(t, "refs/heads/main", q.Get("searchCriteria.targetRefName"))
+		server.writeJSON(w, map[string]interface{}{"count": 0, "value": []interface{}{}})
+	})
+	server.handle("/org/proj/_apis/git/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "active", q.Get("searchCriteria.status"))
+		skip, err := strconv.Atoi(q.Get("$skip"))
+		assert.NoError(t, err)
+		top, err := strconv.Atoi(q.Get("$top"))
+		assert.NoError(t, err)
+
+		var values []map[string]interface{}
+		for id := 21 + skip; id <= 23 && len(values) < top; id++ {
+			values = append(values, map[string]interface{}{
+				"pullRequestId": id,
+				"title":         "Pull request",
+				"status":        "active",
+				"createdBy":     map[string]string{"displayName": "Alice"},
+				"repository":    map[string]interface{}{"name": "web", "project": map[string]string{"name": "proj"}},
+			})
+		}
+		server.writeJSON(w, map[string]interface{}{"count": len(values), "value": values})
+	})
+	server.handle("/org/_apis/git/pullrequests/7", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"pullRequestId": 7,
+			"title":         "Add search",
+			"status":        "active",
+			"mergeStatus":   "conflicts",
+			"createdBy":     map[string]string{"displayName": "Alice"},
+			"reviewers": []map[string]interface{}{
+				{"id": "bob-id", "displayName": "Bob", "vote": 10},
+				{"id": "carol-id", "displayName": "Carol", "vote": 0, "isRequired": true},
+			},
+			"repository": map[string]interface{}{
+				"id":      "repo-id",
+				"name":    "web",
+				"webUrl":  "https://dev.azure.com/org/proj/_git/web",
+				"project": map[string]string{"id": "proj-id", "name": "proj"},
+			},
+			"lastMergeSourceCommit": map[string]string{"commitId": "abc"},
+		})
+	})
+	server.handle("/org/proj/_apis/policy/evaluations", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("artifactId") != "vstfs:///CodeReview/CodeReviewId/proj-id/7" {
+			server.writeJSON(w, map[string]interface{}{"value": []interface{}{}})
+			return
+		}
+		server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+			{"status": "approved", "context": map[string]int{"buildId": 90}, "configuration": map[string]interface{}{"isBlocking": true, "type": map[string]string{"displayName": "Build"}, "settings": map[string]string{"displayName": "CI"}}},
+			{"status": "rejected", "configuration": map[string]interface{}{"isBlocking": true, "type": map[string]string{"displayName": "Work item linking"}}},
+			{"status": "queued", "configuration": map[string]interface{}{"isBlocking": true, "type": map[string]string{"displayName": "Minimum number of reviewers"}}},
+			{"status": "notApplicable", "configuration": map[string]interface{}{"type": map[string]string{"displayName": "Comment requirements"}}},
+		}})
+	})
+	server.handle("/org/proj/_apis/build/builds", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("branchName") != "refs/pull/7/merge" {
+			server.writeJSON(w, map[string]interface{}{"value": []interface{}{}})
+			return
+		}
+		server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+			{"id": 90, "status": "completed", "result": "succeeded", "definition": map[string]interface{}{"id": 1, "name": "CI"}},
+			{"id": 91, "status": "inProgress", "definition": map[string]interface{}{"id": 2, "name": "E2E"}},
+			{"id": 80, "status": "completed", "result": "failed", "definition": map[string]interface{}{"id": 1, "name": "CI"}},
+		}})
+	})
+	server.handle("/org/proj/_apis/git/repositories/repo-id/FileDiffs", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			BaseVersionCommit   string `json:"baseVersionCommit"`
+			TargetVersionCommit string `json:"targetVersionCommit"`
+		}
+		server.readJSON(r, &payload)
+		assert.Equal(t, "base", payload.BaseVersionCommit)
+		assert.Equal(t, "head", payload.TargetVersionCommit)
+		server.writeJSON(w, []map[string]interface{}{
+			{"path": "/src/search.go", "lineDiffBlocks": []map[string]interface{}{{"changeType": "add", "modifiedLinesCount": 40}}},
+			{"path": "/src/main.go", "lineDiffBlocks": []map[string]interface{}{
+				{"changeType": 3, "originalLinesCount": 2, "modifiedLinesCount": 3},
+				{"changeType": "none", "originalLinesCount": 10, "modifiedLinesCount": 10},
+			}},
+		})
+	})
+	server.handle("/org/proj/_apis/git/repositories/repo-id/pullrequests/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+	})
+	server.handle("/org/proj/_apis/git/repositories/repo-id/pullrequests/7/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			return
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/org/proj/_apis/git/repositories/repo-id/pullrequests/7/") {
+		case "threads":
+			server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+				{
+					"id":         1,
+					"properties": map[string]interface{}{"CodeReviewThreadType": map[string]string{"$value": "VoteUpdate"}},
+					"comments": []map[string]interface{}{{
+						"author": map[string]string{"id": "bob-id", "displayName": "Bob"}, "content": "Bob voted 10",
+						"commentType": "system", "lastUpdatedDate": "2024-01-03T10:00:00Z",
+					}},
+				},
+				{
+					"id": 2,
+					"comments": []map[string]interface{}{{
+						"author": map[string]string{"displayName": "Carol"}, "content": "Looks good overall",
+						"commentType": "text", "lastUpdatedDate": "2024-01-02T10:00:00Z",
+					}},
+				},
+				{
+					"id": 3,
+					"threadContext": map[string]interface{}{
+						"filePath":       "/src/search.go",
+						"rightFileStart": map[string]int{"line": 4},
+						"rightFileEnd":   map[string]int{"line": 6},
+					},
+					"comments": []map[string]interface{}{{
+						"author": map[string]string{"displayName": "Bob"}, "content": "Nit: rename this",
+						"commentType": "text", "lastUpdatedDate": "2024-01-02T11:00:00Z",
+					}},
+				},
+			}})
+		case "iterations":
+			server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+				{"id": 1, "sourceRefCommit": map[string]string{"commitId": "old"}, "commonRefCommit": map[string]string{"commitId": "base"}},
+				{"id": 2, "sourceRefCommit": map[string]string{"commitId": "head"}, "commonRefCommit": map[string]string{"commitId": "base"}},
+			}})
+		case "iterations/2/changes":
+			server.writeJSON(w, map[string]interface{}{"changeEntries": []map[string]interface{}{
+				{"changeType": "add", "item": map[string]interface{}{"path": "/src/search.go"}},
+				{"changeType": "edit", "item": map[string]interface{}{"path": "/src/main.go"}},
+				{"changeType": "add", "item": map[string]interface{}{"path": "/src", "isFolder": true}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server.handle("/org/proj/_apis/wit/wiql", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		server.writeJSON(w, map[string]interface{}{
+			"workItems": []map[string]int{{"id": 11}, {"id": 12}, {"id": 13}},
+		})
+	})
+	server.handle("/org/proj/_apis/wit/workitemsbatch", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Ids []int `json:"ids"`
+		}
+		server.readJSON(r, &payload)
+
+		values := make([]map[string]interface{}, 0, len(payload.Ids))
+		for _, id := range payload.Ids {
+			values = append(values, map[string]interface{}{
+				"id": id,
+				"fields": map[string]interface{}{
+					"System.Title":        "Work item",
+					"System.State":        "Active",
+					"System.TeamProject":  "proj",
+					"System.CreatedBy":    map[string]string{"displayName": "Alice"},
+					"System.AssignedTo":   map[string]string{"displayName": "Bob"},
+					"System.Tags":         "backend; urgent",
+					"System.CommentCount": 4,
+					"System.CreatedDate":  "2024-01-01T10:00:00Z",
+					"System.ChangedDate":  "2024-01-05T10:00:00Z",
+				},
+			})
+		}
+		server.writeJSON(w, map[string]interface{}{"count": len(values), "value": values})
+	})
+
+	return server
+}
+
+func newTestAzureDevOpsProvider(server *fakeServer) providers.GitProvider {
+	return server.newProvider(providers.ProviderConfig{
+		Type:         providers.AzureDevOps,
+		Organization: "org",
+		Project:      "proj",
+	})
+}
+
+// wiqlQueries returns the WIQL queries the server received
+func wiqlQueries(t *testing.T, server *fakeServer) []string {
+	var queries []string
+	for _, req := range server.requests("POST", "/org/proj/_apis/wit/wiql") {
+		var payload struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &payload))
+		queries = append(queries, payload.Query)
+	}
+	return queries
+}
+
+// pullRequestUpdates returns the changes of pull request 7 the server received
+func pullRequestUpdates(t *testing.T, server *fakeServer) []map[string]interface{} {
+	var updates []map[string]interface{}
+	for _, req := range server.requests("PATCH", "/org/proj/_apis/git/repositories/repo-id/pullrequests/7") {
+		var update map[string]interface{}
+		require.NoError(t, json.Unmarshal(req.Body, &update))
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// pullRequestActionRequests returns the other requests that changed pull request 7
+func pullRequestActionRequests(server *fakeServer) []string {
+	var requests []string
+	for _, req := range server.requests("", "/org/proj/_apis/git/repositories/repo-id/pullrequests/7/") {
+		if req.Method != "GET" {
+			requests = append(requests, req.Method+" "+req.Path)
+		}
+	}
+	return requests
+}
+
+func TestAzureDevOpsGetAuthInfo(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := server.newProvider(providers.ProviderConfig{
+		Type:         providers.AzureDevOps,
+		Organization: "org",
+		Project:      "proj",
+		TokenSource:  "ADO_PAT",
+	})
+
+	authInfo, err := provider.GetAuthInfo()
+	require.NoError(t, err)
+	require.Equal(t, providers.AuthInfo{
+		Username:    "Me",
+		IsLoggedIn:  true,
+		TokenSource: "Personal Access Token (ADO_PAT)",
+	}, authInfo)
+}
+
+func TestAzureDevOpsFetchPullRequestsPaging(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := newTestAzureDevOpsProvider(server)
+
+	res, err := provider.FetchPullRequests(context.Background(), "", 2, nil)
+	require.NoError(t, err)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Equal(t, "2", res.PageInfo.EndCursor)
+	require.Equal(t, providers.UnknownTotalCount, res.TotalCount)
+	require.Len(t, res.Prs, 2)
+	require.Equal(t, 21, res.Prs[0].Number)
+
+	next, err := provider.FetchPullRequests(context.Background(), "", 2, &res.PageInfo)
+	require.NoError(t, err)
+	require.False(t, next.PageInfo.HasNextPage)
+	require.Equal(t, 3, next.TotalCount)
+	require.Len(t, next.Prs, 1)
+	require.Equal(t, 23, next.Prs[0].Number)
+}
+
+func TestAzureDevOpsFetchPullRequest(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := newTestAzureDevOpsProvider(server)
+
+	pr, err := provider.FetchPullRequest(context.Background(), "https://dev.azure.com/org/proj/_git/web/pullrequest/7")
+	require.NoError(t, err)
+	require.Equal(t, 7, pr.Number)
+	require.Equal(t, "https://dev.azure.com/org/proj/_git/web/pullrequest/7", pr.Url)
+	require.Equal(t, "CONFLICTING", pr.Mergeable)
+
+	require.Equal(t, "REVIEW_REQUIRED", pr.ReviewDecision)
+	require.Len(t, pr.Reviews.Nodes, 1)
+	require.Equal(t, "Bob", pr.Reviews.Nodes[0].Author.Login)
+	require.Equal(t, "APPROVED", pr.Reviews.Nodes[0].State)
+	require.Equal(t, 2024, pr.Reviews.Nodes[0].UpdatedAt.Year())
+	require.Equal(t, 1, pr.ReviewRequests.TotalCount)
+
+	require.Len(t, pr.Comments.Nodes, 1)
+	require.Equal(t, "Looks good overall", pr.Comments.Nodes[0].Body)
+	require.Len(t, pr.ReviewThreads.Nodes, 1)
+	require.Equal(t, "src/search.go", pr.ReviewThreads.Nodes[0].Path)
+	require.Equal(t, 6, pr.ReviewThreads.Nodes[0].Line)
+
+	require.Equal(t, []providers.ChangedFile{
+		{Path: "src/search.go", Additions: 40, ChangeType: "ADDED"},
+		{Path: "src/main.go", Additions: 3, Deletions: 2, ChangeType: "MODIFIED"},
+	}, pr.Files.Nodes)
+	require.Equal(t, 43, pr.Additions)
+	require.Equal(t, 2, pr.Deletions)
+
+	// The latest build of each definition, then the policies other than the CI build validation
+	checks := pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes
+	require.Len(t, checks, 4)
+	require.Equal(t, "CI", checks[0].CheckRun.Name)
+	require.Equal(t, "SUCCESS", checks[0].CheckRun.Conclusion)
+	require.Equal(t, "E2E", checks[1].CheckRun.Name)
+	require.Equal(t, "IN_PROGRESS", checks[1].CheckRun.Status)
+	require.Equal(t, "Work item linking", checks[2].CheckRun.Name)
+	require.Equal(t, "FAILURE", checks[2].CheckRun.Conclusion)
+	require.Equal(t, "Minimum number of reviewers", checks[3].CheckRun.Name)
+	require.Equal(t, "QUEUED", checks[3].CheckRun.Status)
+	require.Equal(t, "DIRTY", pr.MergeStateStatus)
+}
+
+func TestAzureDevOpsReviewDecision(t *testing.T) {
+	testCases := map[string]struct {
+		reviewers []map[string]interface{}
+		want      string
+	}{
+		"no reviewers": {
+			want: "",
+		},
+		"optional reviewer pending": {
+			reviewers: []map[string]interface{}{{"id": "bob-id", "vote": 0}},
+			want:      "",
+		},
+		"required reviewer pending": {
+			reviewers: []map[string]interface{}{{"id": "bob-id", "vote": 10}, {"id": "carol-id", "vote": 0, "isRequired": true}},
+			want:      "REVIEW_REQUIRED",
+		},
+		"approved": {
+			reviewers: []map[string]interface{}{{"id": "bob-id", "vote": 5}, {"id": "carol-id", "vote": 0}},
+			want:      "APPROVED",
+		},
+		"rejected": {
+			reviewers: []map[string]interface{}{{"id": "bob-id", "vote": 10}, {"id": "carol-id", "vote": -10, "isRequired": true}},
+			want:      "CHANGES_REQUESTED",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.handle("/org/", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/org/_apis/git/pullrequests/9" {
+					server.writeJSON(w, map[string]interface{}{"value": []interface{}{}})
+					return
+				}
+				server.writeJSON(w, map[string]interface{}{
+					"pullRequestId": 9,
+					"status":        "active",
+					"reviewers":     tc.reviewers,
+					"repository":    map[string]interface{}{"id": "repo-id", "name": "web", "project": map[string]string{"name": "proj"}},
+				})
+			})
+			provider := newTestAzureDevOpsProvider(server)
+
+			pr, err := provider.FetchPullRequest(context.Background(), "https://dev.azure.com/org/proj/_git/web/pullrequest/9")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, pr.ReviewDecision)
+		})
+	}
+}
+
+func TestAzureDevOpsFetchIssues(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := newTestAzureDevOpsProvider(server)
+
+	res, err := provider.FetchIssues(context.Background(), "", 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Issues, 2)
+
+	issue := res.Issues[0]
+	require.Equal(t, 11, issue.Number)
+	require.Equal(t, "OPEN", issue.State)
+	require.Equal(t, "Alice", issue.Author.Login)
+	require.Equal(t, []providers.Assignee{{Login: "Bob"}}, issue.Assignees.Nodes)
+	require.Equal(t, 4, issue.Comments.TotalCount)
+	require.Equal(t, []string{"backend", "urgent"}, []string{issue.Labels.Nodes[0].Name, issue.Labels.Nodes[1].Name})
+	require.Equal(t, server.URL+"/org/proj/_workitems/edit/11", issue.Url)
+	require.True(t, issue.UpdatedAt.After(issue.CreatedAt))
+
+	next, err := provider.FetchIssues(context.Background(), "", 2, &res.PageInfo)
+	require.NoError(t, err)
+	require.False(t, next.PageInfo.HasNextPage)
+	require.Len(t, next.Issues, 1)
+	require.Equal(t, 13, next.Issues[0].Number)
+}
+
+func TestAzureDevOpsEscapesProjects(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("/org/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/org/Q&A%20%232/_apis/git/pullrequests":
+			server.writeJSON(w, map[string]interface{}{"count": 0, "value": []interface{}{}})
+		case "/org/Q&A%20%232/_apis/wit/wiql":
+			server.writeJSON(w, map[string]interface{}{"workItems": []map[string]int{{"id": 11}}})
+		case "/org/Q&A%20%232/_apis/wit/workitemsbatch":
+			server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{{
+				"id":     11,
+				"fields": map[string]string{"System.Title": "Escape projects", "System.TeamProject": "Q&A #2"},
+			}}})
+		default:
+			t.Errorf("unexpected request %s", r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	})
+	provider := server.newProvider(providers.ProviderConfig{
+		Type:         providers.AzureDevOps,
+		Organization: "org",
+		Project:      "Q&A #2",
+	})
+
+	_, err := provider.FetchPullRequests(context.Background(), "", 20, nil)
+	require.NoError(t, err)
+	res, err := provider.FetchIssues(context.Background(), "", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Issues, 1)
+	require.Equal(t, server.URL+"/org/Q&A%20%232/_workitems/edit/11", res.Issues[0].Url)
+}
+
+func TestAzureDevOpsPullRequestFilters(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := newTestAzureDevOpsProvider(server)
+
+	res, err := provider.FetchPullRequests(
+		context.Background(),
+		"is:merged review-requested:@me author:alice@example.com repo:org/other/web base:main involves:@me -author:@me fix",
+		20,
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`Azure DevOps ignored unsupported filters: involves:@me, -author:@me, "fix" (free text search)`,
+	}, res.Warnings)
+}
+
+func TestAzureDevOpsWorkItemFilters(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := newTestAzureDevOpsProvider(server)
+
+	res, err := provider.FetchIssues(context.Background(), `is:open assignee:@me label:"tech debt" -label:wontfix type:Bug milestone:v1 crash`, 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Azure DevOps ignored unsupported filters: milestone:v1"}, res.Warnings)
+	require.Equal(t, []string{
+		"SELECT [System.Id] FROM workitems WHERE [System.TeamProject] = 'proj' " +
+			"AND [System.State] NOT IN ('Closed', 'Done', 'Removed', 'Resolved', 'Completed', 'Cut') " +
+			"AND [System.AssignedTo] = @Me " +
+			"AND [System.Tags] CONTAINS 'tech debt' " +
+			"AND NOT [System.Tags] CONTAINS 'wontfix' " +
+			"AND [System.WorkItemType] = 'Bug' " +
+			"AND [System.Title] CONTAINS 'crash' " +
+			"ORDER BY [System.ChangedDate] DESC",
+	}, wiqlQueries(t, server))
+}
+
+func TestAzureDevOpsPullRequestActions(t *testing.T) {
+	server := newAzureDevOpsServer(t)
+	provider := server.newProvider(providers.ProviderConfig{
+		Type:          providers.AzureDevOps,
+		Organization:  "org",
+		Project:       "proj",
+		MergeStrategy: "squash",
+	})
+	actions, ok := provider.(providers.PullRequestActions)
+	require.True(t, ok)
+
+	require.NoError(t, actions.ApprovePullRequest(7, "proj/web", "LGTM"))
+	require.Equal(t, []string{
+		"PUT /org/proj/_apis/git/repositories/repo-id/pullrequests/7/reviewers/me-id",
+		"POST /org/proj/_apis/git/repositories/repo-id/pullrequests/7/threads",
+	}, pullRequestActionRequests(server))
+
+	require.NoError(t, actions.MergePullRequest(7, "proj/web"))
+	require.NoError(t, actions.ClosePullRequest(7, "proj/web"))
+	require.NoError(t, actions.ReopenPullRequest(7, "proj/web"))
+	require.NoError(t, actions.MarkPullRequestReady(7, "proj/web"))
+	require.Equal(t, []map[string]interface{}{
+		{
+			"status":                "completed",
+			"lastMergeSourceCommit": map[string]interface{}{"commitId": "abc"},
+			"completionOptions":     map[string]interface{}{"mergeStrategy": "squash"},
+		},
+		{"status": "abandoned"},
+		{"status": "active"},
+		{"isDraft": false},
+	}, pullRequestUpdates(t, server))
+}
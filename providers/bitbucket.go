@@ -0,0 +1,1044 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	defaultBitbucketBaseURL   = "https://bitbucket.org"
+	defaultBitbucketAPIURL    = "https://api.bitbucket.org/2.0"
+	bitbucketServerAPIVersion = "/rest/api/1.0"
+)
+
+// BitbucketProvider talks to both Bitbucket Cloud (REST API 2.0) and
+// Bitbucket Server / Data Center (REST API 1.0), whose APIs differ a lot
+type BitbucketProvider struct {
+	*RateLimitTracker
+
+	client     *http.Client
+	config     ProviderConfig
+	server     bool
+	baseURL    string
+	apiURL     string
+	token      string
+	repository string
+
+	// userMu guards username and userUUID, which the fetches of every section
+	// fill
+	userMu   sync.Mutex
+	username string
+	userUUID string
+}
+
+// Bitbucket Cloud types
+
+type BitbucketUser struct {
+	UUID        string `json:"uuid"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+type BitbucketParticipant struct {
+	User     BitbucketUser `json:"user"`
+	Role     string        `json:"role"`
+	Approved bool          `json:"approved"`
+	State    string        `json:"state"`
+}
+
+type BitbucketBranchRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type BitbucketPullRequest struct {
+	Id           int                    `json:"id"`
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	State        string                 `json:"state"`
+	Draft        bool                   `json:"draft"`
+	Author       BitbucketUser          `json:"author"`
+	Source       BitbucketBranchRef     `json:"source"`
+	Destination  BitbucketBranchRef     `json:"destination"`
+	Participants []BitbucketParticipant `json:"participants"`
+	Reviewers    []BitbucketUser        `json:"reviewers"`
+	CommentCount int                    `json:"comment_count"`
+	CreatedOn    time.Time              `json:"created_on"`
+	UpdatedOn    time.Time              `json:"updated_on"`
+	Links        struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+type BitbucketPage[T any] struct {
+	Size   int    `json:"size"`
+	Page   int    `json:"page"`
+	Next   string `json:"next"`
+	Values []T    `json:"values"`
+}
+
+type BitbucketDiffStat struct {
+	Status       string `json:"status"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Old          *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+type BitbucketComment struct {
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User      BitbucketUser `json:"user"`
+	UpdatedOn time.Time     `json:"updated_on"`
+	Deleted   bool          `json:"deleted"`
+}
+
+// BitbucketBuildStatus is shared by Cloud and Server, both report SUCCESSFUL,
+// FAILED, INPROGRESS and (Cloud only) STOPPED
+type BitbucketBuildStatus struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	URL   string `json:"url"`
+}
+
+// Bitbucket Server types
+
+type BitbucketServerUser struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	DisplayName string `json:"displayName"`
+}
+
+type BitbucketServerParticipant struct {
+	User     BitbucketServerUser `json:"user"`
+	Role     string              `json:"role"`
+	Approved bool                `json:"approved"`
+	Status   string              `json:"status"`
+}
+
+type BitbucketServerRef struct {
+	DisplayId    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+type BitbucketServerPullRequest struct {
+	Id          int                          `json:"id"`
+	Title       string                       `json:"title"`
+	Description string                       `json:"description"`
+	State       string                       `json:"state"`
+	Draft       bool                         `json:"draft"`
+	Author      BitbucketServerParticipant   `json:"author"`
+	Reviewers   []BitbucketServerParticipant `json:"reviewers"`
+	FromRef     BitbucketServerRef           `json:"fromRef"`
+	ToRef       BitbucketServerRef           `json:"toRef"`
+	CreatedDate int64                        `json:"createdDate"`
+	UpdatedDate int64                        `json:"updatedDate"`
+	Properties  struct {
+		CommentCount int `json:"commentCount"`
+		MergeResult  struct {
+			Outcome string `json:"outcome"`
+		} `json:"mergeResult"`
+	} `json:"properties"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+type BitbucketServerPage[T any] struct {
+	Size          int  `json:"size"`
+	IsLastPage    bool `json:"isLastPage"`
+	Start         int  `json:"start"`
+	NextPageStart int  `json:"nextPageStart"`
+	Values        []T  `json:"values"`
+}
+
+type BitbucketServerChange struct {
+	Type string `json:"type"`
+	Path struct {
+		ToString string `json:"toString"`
+	} `json:"path"`
+}
+
+type BitbucketServerActivity struct {
+	Action  string `json:"action"`
+	Comment *struct {
+		Text        string              `json:"text"`
+		Author      BitbucketServerUser `json:"author"`
+		UpdatedDate int64               `json:"updatedDate"`
+	} `json:"comment"`
+}
+
+func NewBitbucketProvider(config ProviderConfig) (GitProvider, error) {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	server := config.Type == BitbucketServer
+
+	var apiURL string
+	switch {
+	case server && baseURL == "":
+		return nil, fmt.Errorf("Bitbucket Server base URL is required. Set provider.baseUrl in the config")
+	case server:
+		apiURL = baseURL + bitbucketServerAPIVersion
+	case baseURL == "" || baseURL == defaultBitbucketBaseURL:
+		baseURL = defaultBitbucketBaseURL
+		apiURL = defaultBitbucketAPIURL
+	default:
+		apiURL = baseURL + "/2.0"
+	}
+
+	var repository string
+	if config.Organization != "" && config.Repository != "" {
+		repository = config.Organization + "/" + config.Repository
+	}
+
+	log.Debug("Creating Bitbucket provider", "server", server, "baseURL", baseURL, "hasToken", config.Token != "")
+
+	rateLimits := &RateLimitTracker{}
+	return &BitbucketProvider{
+		RateLimitTracker: rateLimits,
+		client:           newRateLimitedClient(rateLimits),
+		config:           config,
+		server:           server,
+		baseURL:          baseURL,
+		apiURL:           apiURL,
+		token:            config.Token,
+		repository:       repository,
+	}, nil
+}
+
+func (p *BitbucketProvider) GetType() ProviderType {
+	if p.server {
+		return BitbucketServer
+	}
+	return Bitbucket
+}
+
+func (p *BitbucketProvider) SupportsPullRequests() bool {
+	return true
+}
+
+func (p *BitbucketProvider) SupportsIssues() bool {
+	// Bitbucket Cloud issue trackers are mostly replaced by Jira and Server has none
+	return false
+}
+
+// Capabilities are empty, every action opens the pull request in the browser
+func (p *BitbucketProvider) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func (p *BitbucketProvider) GetAuthInfo() (AuthInfo, error) {
+	tokenSource := "Access Token"
+	if strings.Contains(p.token, ":") {
+		tokenSource = "App Password"
+	}
+	ctx, cancel := actionContext()
+	defer cancel()
+	username, _, err := p.currentUser(ctx)
+	if err != nil {
+		return AuthInfo{IsLoggedIn: false, TokenSource: tokenSource}, err
+	}
+	return AuthInfo{
+		Username:    username,
+		IsLoggedIn:  true,
+		TokenSource: tokenSource,
+	}, nil
+}
+
+// currentUser returns the username and, on Bitbucket Cloud, the UUID of the
+// signed in user
+func (p *BitbucketProvider) currentUser(ctx context.Context) (string, string, error) {
+	p.userMu.Lock()
+	defer p.userMu.Unlock()
+	if p.username != "" {
+		return p.username, p.userUUID, nil
+	}
+
+	if p.server {
+		// Bitbucket Server has no endpoint for the current user, but reports it on every response
+		var properties map[string]interface{}
+		header, err := p.get(ctx, p.apiURL+"/application-properties", nil, &properties)
+		if err != nil {
+			return "", "", err
+		}
+		username := header.Get("X-AUSERNAME")
+		if username == "" {
+			return "", "", fmt.Errorf("Bitbucket Server didn't report the current user, check the access token")
+		}
+		p.username = username
+		return p.username, "", nil
+	}
+
+	var user BitbucketUser
+	if _, err := p.get(ctx, p.apiURL+"/user", nil, &user); err != nil {
+		return "", "", err
+	}
+	p.username = user.Nickname
+	p.userUUID = user.UUID
+	return p.username, p.userUUID, nil
+}
+
+func (p *BitbucketProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	if p.token == "" {
+		return PullRequestsResponse{}, fmt.Errorf("Bitbucket access token is required. Set BITBUCKET_TOKEN environment variable")
+	}
+	if p.server {
+		return p.fetchServerPullRequests(ctx, query, limit, pageInfo)
+	}
+	return p.fetchCloudPullRequests(ctx, query, limit, pageInfo)
+}
+
+func (p *BitbucketProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	return IssuesResponse{}, fmt.Errorf("issues are not supported for Bitbucket")
+}
+
+var (
+	bitbucketCloudPullRequestURLRegexp  = regexp.MustCompile(`^https?://[^/]+/([^/]+/[^/]+)/pull-requests/(\d+)`)
+	bitbucketServerPullRequestURLRegexp = regexp.MustCompile(`^https?://.+?/(?:projects|users)/([^/]+)/repos/([^/]+)/pull-requests/(\d+)`)
+)
+
+func (p *BitbucketProvider) FetchPullRequest(ctx context.Context, prUrl string) (PullRequestData, error) {
+	log.Debug("Fetching Bitbucket pull request", "url", prUrl)
+
+	if p.server {
+		matches := bitbucketServerPullRequestURLRegexp.FindStringSubmatch(prUrl)
+		if len(matches) != 4 {
+			return PullRequestData{}, fmt.Errorf("invalid Bitbucket Server pull request URL: %s", prUrl)
+		}
+		project := matches[1]
+		if strings.Contains(prUrl, "/users/") {
+			project = "~" + project
+		}
+		id, _ := strconv.Atoi(matches[3])
+		return p.fetchServerPullRequest(ctx, project+"/"+matches[2], id)
+	}
+
+	matches := bitbucketCloudPullRequestURLRegexp.FindStringSubmatch(prUrl)
+	if len(matches) != 3 {
+		return PullRequestData{}, fmt.Errorf("invalid Bitbucket pull request URL: %s", prUrl)
+	}
+	id, _ := strconv.Atoi(matches[2])
+	return p.fetchCloudPullRequest(ctx, matches[1], id)
+}
+
+// Bitbucket Cloud
+
+type bitbucketCloudList struct {
+	path   string
+	params url.Values
+	// unsupported lists the qualifiers that couldn't be translated, as written by the user
+	unsupported []string
+}
+
+// buildCloudListParams translates a section filter into a Bitbucket Cloud endpoint and
+// a BBQL query. Pull requests can be listed per repository or per author
+func (p *BitbucketProvider) buildCloudListParams(ctx context.Context, query string, limit int, pageInfo *PageInfo) (bitbucketCloudList, error) {
+	params := url.Values{}
+	params.Set("pagelen", strconv.Itoa(min(limit, 50)))
+	params.Set("sort", "-updated_on")
+	params.Set("fields", "+values.participants,+values.reviewers")
+	page := "1"
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		page = pageInfo.EndCursor
+	}
+	params.Set("page", page)
+
+	parsed := ParseSearchQuery(query)
+	var conditions, unsupported []string
+	var author string
+	for _, filter := range parsed.Filters {
+		value := filter.Value
+		if value == "@me" {
+			_, uuid, err := p.currentUser(ctx)
+			if err != nil {
+				return bitbucketCloudList{}, err
+			}
+			value = uuid
+		}
+		field := "nickname"
+		if strings.HasPrefix(value, "{") {
+			field = "uuid"
+		}
+
+		switch {
+		case filter.Negated:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		case filter.Key == "is" || filter.Key == "state":
+			switch strings.ToLower(value) {
+			case "open":
+				params.Add("state", "OPEN")
+			case "merged":
+				params.Add("state", "MERGED")
+			case "closed":
+				params.Add("state", "MERGED")
+				params.Add("state", "DECLINED")
+				params.Add("state", "SUPERSEDED")
+			case "draft":
+				conditions = append(conditions, "draft=true")
+			default:
+				unsupported = append(unsupported, formatSearchFilter(filter))
+			}
+		case filter.Key == "draft":
+			conditions = append(conditions, "draft="+value)
+		case filter.Key == "author":
+			author = value
+			conditions = append(conditions, fmt.Sprintf("author.%s=%q", field, value))
+		case filter.Key == "review-requested" || filter.Key == "reviewed-by":
+			conditions = append(conditions, fmt.Sprintf("reviewers.%s=%q", field, value))
+		case filter.Key == "involves":
+			conditions = append(conditions, fmt.Sprintf("participants.user.%s=%q", field, value))
+		case filter.Key == "base":
+			conditions = append(conditions, fmt.Sprintf("destination.branch.name=%q", value))
+		case filter.Key == "head":
+			conditions = append(conditions, fmt.Sprintf("source.branch.name=%q", value))
+		case filter.Key == "repo":
+			// Already handled by picking the repository endpoint
+		default:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		}
+	}
+	if text := parsed.Text(); text != "" {
+		conditions = append(conditions, fmt.Sprintf("title~%q", text))
+	}
+	if len(conditions) > 0 {
+		params.Set("q", strings.Join(conditions, " AND "))
+	}
+	if len(unsupported) > 0 {
+		log.Debug("Unsupported Bitbucket filters", "filters", unsupported)
+	}
+
+	repo, ok := parsed.Get("repo")
+	if !ok {
+		repo = p.repository
+	}
+	switch {
+	case ok || (repo != "" && author == ""):
+		return bitbucketCloudList{path: fmt.Sprintf("/repositories/%s/pullrequests", repo), params: params, unsupported: unsupported}, nil
+	case author != "":
+		return bitbucketCloudList{path: fmt.Sprintf("/pullrequests/%s", url.PathEscape(author)), params: params, unsupported: unsupported}, nil
+	default:
+		return bitbucketCloudList{}, fmt.Errorf("Bitbucket needs a repo: or author: filter to list pull requests")
+	}
+}
+
+func (p *BitbucketProvider) fetchCloudPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	list, err := p.buildCloudListParams(ctx, query, limit, pageInfo)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+
+	log.Debug("Fetching Bitbucket pull requests", "path", list.path, "params", list.params.Encode())
+	var page BitbucketPage[BitbucketPullRequest]
+	if _, err := p.get(ctx, p.apiURL+list.path, list.params, &page); err != nil {
+		return PullRequestsResponse{}, err
+	}
+	log.Debug("Successfully fetched Bitbucket pull requests", "count", len(page.Values))
+
+	prs := make([]PullRequestData, len(page.Values))
+	forEachConcurrently(len(page.Values), func(i int) {
+		prs[i] = p.convertCloudPullRequestToData(page.Values[i], p.fetchCloudBuildStatuses(ctx, page.Values[i]))
+	})
+
+	pageInfoRes := PageInfo{StartCursor: list.params.Get("page")}
+	if page.Next != "" {
+		pageInfoRes.HasNextPage = true
+		pageInfoRes.EndCursor = strconv.Itoa(page.Page + 1)
+	}
+
+	totalCount := page.Size
+	if totalCount == 0 {
+		totalCount = len(prs)
+	}
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: totalCount,
+		PageInfo:   pageInfoRes,
+		Warnings:   unsupportedFiltersWarning("Bitbucket", list.unsupported),
+	}, nil
+}
+
+func (p *BitbucketProvider) fetchCloudBuildStatuses(ctx context.Context, bbPR BitbucketPullRequest) []BitbucketBuildStatus {
+	var statuses BitbucketPage[BitbucketBuildStatus]
+	statusesURL := fmt.Sprintf("%s/repositories/%s/pullrequests/%d/statuses", p.apiURL, bbPR.Destination.Repository.FullName, bbPR.Id)
+	if _, err := p.get(ctx, statusesURL, nil, &statuses); err != nil {
+		log.Debug("Failed fetching Bitbucket build statuses", "pr", bbPR.Links.HTML.Href, "err", err)
+	}
+	return statuses.Values
+}
+
+func (p *BitbucketProvider) fetchCloudPullRequest(ctx context.Context, repo string, id int) (PullRequestData, error) {
+	prURL := fmt.Sprintf("%s/repositories/%s/pullrequests/%d", p.apiURL, repo, id)
+	var bbPR BitbucketPullRequest
+	if _, err := p.get(ctx, prURL, nil, &bbPR); err != nil {
+		return PullRequestData{}, err
+	}
+	pr := p.convertCloudPullRequestToData(bbPR, p.fetchCloudBuildStatuses(ctx, bbPR))
+
+	var diffStats BitbucketPage[BitbucketDiffStat]
+	if _, err := p.get(ctx, prURL+"/diffstat", url.Values{"pagelen": {"100"}}, &diffStats); err != nil {
+		log.Debug("Failed fetching Bitbucket diffstat", "pr", bbPR.Links.HTML.Href, "err", err)
+	}
+	pr.Files = ChangedFiles{TotalCount: len(diffStats.Values)}
+	for _, diffStat := range diffStats.Values {
+		file := ChangedFile{
+			Additions:  diffStat.LinesAdded,
+			Deletions:  diffStat.LinesRemoved,
+			ChangeType: bitbucketChangeType(diffStat.Status),
+		}
+		if diffStat.New != nil {
+			file.Path = diffStat.New.Path
+		} else if diffStat.Old != nil {
+			file.Path = diffStat.Old.Path
+		}
+		pr.Additions += file.Additions
+		pr.Deletions += file.Deletions
+		pr.Files.Nodes = append(pr.Files.Nodes, file)
+	}
+
+	var comments BitbucketPage[BitbucketComment]
+	if _, err := p.get(ctx, prURL+"/comments", url.Values{"pagelen": {"50"}, "sort": {"-updated_on"}}, &comments); err != nil {
+		log.Debug("Failed fetching Bitbucket comments", "pr", bbPR.Links.HTML.Href, "err", err)
+	}
+	pr.Comments.Nodes = make([]Comment, 0, len(comments.Values))
+	for _, bbComment := range comments.Values {
+		if bbComment.Deleted {
+			continue
+		}
+		var comment Comment
+		comment.Author.Login = bbComment.User.Nickname
+		comment.Body = bbComment.Content.Raw
+		comment.UpdatedAt = bbComment.UpdatedOn
+		pr.Comments.Nodes = append(pr.Comments.Nodes, comment)
+	}
+
+	return pr, nil
+}
+
+func (p *BitbucketProvider) convertCloudPullRequestToData(bbPR BitbucketPullRequest, statuses []BitbucketBuildStatus) PullRequestData {
+	repo := bitbucketRepository(bbPR.Destination.Repository.FullName)
+	pr := PullRequestData{
+		Number:            bbPR.Id,
+		Title:             bbPR.Title,
+		Body:              bbPR.Description,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         bbPR.UpdatedOn,
+		CreatedAt:         bbPR.CreatedOn,
+		Url:               bbPR.Links.HTML.Href,
+		State:             bitbucketState(bbPR.State),
+		Mergeable:         "UNKNOWN",
+		MergeStateStatus:  "UNKNOWN",
+		HeadRefName:       bbPR.Source.Branch.Name,
+		BaseRefName:       bbPR.Destination.Branch.Name,
+		Repository:        repo,
+		Comments:          Comments{TotalCount: bbPR.CommentCount},
+		IsDraft:           bbPR.Draft,
+		Commits:           bitbucketCommits(statuses),
+	}
+	pr.Author.Login = bbPR.Author.Nickname
+	pr.HeadRef.Name = bbPR.Source.Branch.Name
+	pr.HeadRepository.Name = bbPR.Source.Repository.Name
+
+	reviewed := map[string]bool{}
+	for _, participant := range bbPR.Participants {
+		var review Review
+		review.Author.Login = participant.User.Nickname
+		switch {
+		case participant.Approved:
+			review.State = "APPROVED"
+		case participant.State == "changes_requested":
+			review.State = "CHANGES_REQUESTED"
+		default:
+			continue
+		}
+		reviewed[participant.User.UUID] = true
+		pr.Reviews.Nodes = append(pr.Reviews.Nodes, review)
+	}
+	pr.Reviews.TotalCount = len(pr.Reviews.Nodes)
+
+	for _, reviewer := range bbPR.Reviewers {
+		if !reviewed[reviewer.UUID] {
+			pr.ReviewRequests.Nodes = append(pr.ReviewRequests.Nodes, ReviewRequest{})
+		}
+	}
+	pr.ReviewRequests.TotalCount = len(pr.ReviewRequests.Nodes)
+	pr.ReviewDecision = bitbucketReviewDecision(pr.Reviews.Nodes, len(bbPR.Reviewers))
+
+	return pr
+}
+
+// Bitbucket Server
+
+func (p *BitbucketProvider) fetchServerPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("state", "ALL")
+	params.Set("order", "NEWEST")
+	start := "0"
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		start = pageInfo.EndCursor
+	}
+	params.Set("start", start)
+
+	parsed := ParseSearchQuery(query)
+	repo, hasRepo := parsed.Get("repo")
+	if !hasRepo {
+		repo = p.repository
+	}
+
+	// Without a repository only the current user's dashboard can be listed
+	var dashboardRole string
+	var unsupported []string
+	// Bitbucket Server has no state for every closed pull request, so those are
+	// listed with state=ALL and the open ones are dropped from each page
+	var closedOnly bool
+	participant := 1
+	for _, filter := range parsed.Filters {
+		value := filter.Value
+		isMe := value == "@me"
+		if isMe {
+			username, _, err := p.currentUser(ctx)
+			if err != nil {
+				return PullRequestsResponse{}, err
+			}
+			value = username
+		}
+
+		var role string
+		switch {
+		case filter.Negated:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		case filter.Key == "is" || filter.Key == "state":
+			switch strings.ToLower(value) {
+			case "open":
+				params.Set("state", "OPEN")
+				closedOnly = false
+			case "merged":
+				params.Set("state", "MERGED")
+				closedOnly = false
+			case "closed":
+				params.Set("state", "ALL")
+				closedOnly = true
+			default:
+				unsupported = append(unsupported, formatSearchFilter(filter))
+			}
+		case filter.Key == "author":
+			role = "AUTHOR"
+		case filter.Key == "review-requested" || filter.Key == "reviewed-by":
+			role = "REVIEWER"
+		case filter.Key == "involves":
+			role = "PARTICIPANT"
+		case filter.Key == "base":
+			params.Set("at", "refs/heads/"+value)
+		case filter.Key == "repo":
+			// Already handled by picking the repository endpoint
+		default:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		}
+
+		if role == "" {
+			continue
+		}
+		if isMe && dashboardRole == "" {
+			dashboardRole = role
+		}
+		params.Set(fmt.Sprintf("role.%d", participant), role)
+		params.Set(fmt.Sprintf("username.%d", participant), value)
+		participant++
+	}
+	if text := parsed.Text(); text != "" {
+		params.Set("filterText", text)
+	}
+	if len(unsupported) > 0 {
+		log.Debug("Unsupported Bitbucket Server filters", "filters", unsupported)
+	}
+
+	var apiURL string
+	switch {
+	case hasRepo || (repo != "" && dashboardRole == ""):
+		projectKey, slug, _ := strings.Cut(repo, "/")
+		apiURL = fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests", p.apiURL, projectKey, slug)
+	case dashboardRole != "":
+		apiURL = p.apiURL + "/dashboard/pull-requests"
+		params.Set("role", dashboardRole)
+	default:
+		return PullRequestsResponse{}, fmt.Errorf("Bitbucket Server needs a repo: filter or an @me filter to list pull requests")
+	}
+
+	log.Debug("Fetching Bitbucket Server pull requests", "url", apiURL, "params", params.Encode())
+	var page BitbucketServerPage[BitbucketServerPullRequest]
+	if _, err := p.get(ctx, apiURL, params, &page); err != nil {
+		return PullRequestsResponse{}, err
+	}
+	log.Debug("Successfully fetched Bitbucket Server pull requests", "count", len(page.Values))
+
+	values := page.Values
+	if closedOnly {
+		values = make([]BitbucketServerPullRequest, 0, len(page.Values))
+		for _, bbPR := range page.Values {
+			if bbPR.State != "OPEN" {
+				values = append(values, bbPR)
+			}
+		}
+	}
+
+	prs := make([]PullRequestData, len(values))
+	forEachConcurrently(len(values), func(i int) {
+		prs[i] = p.convertServerPullRequestToData(values[i], p.fetchServerBuildStatuses(ctx, values[i]))
+	})
+
+	pageInfoRes := PageInfo{StartCursor: start}
+	if !page.IsLastPage {
+		pageInfoRes.HasNextPage = true
+		pageInfoRes.EndCursor = strconv.Itoa(page.NextPageStart)
+	}
+
+	// Bitbucket Server doesn't report a total, only the last page tells it
+	totalCount := page.Start + len(prs)
+	if pageInfoRes.HasNextPage || (closedOnly && page.Start > 0) {
+		totalCount = UnknownTotalCount
+	}
+
+	warnings := unsupportedFiltersWarning("Bitbucket Server", unsupported)
+	if closedOnly {
+		warnings = append(warnings, "Bitbucket Server can't search closed pull requests, the open ones are filtered out page by page")
+	}
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: totalCount,
+		PageInfo:   pageInfoRes,
+		Warnings:   warnings,
+	}, nil
+}
+
+func (p *BitbucketProvider) fetchServerBuildStatuses(ctx context.Context, bbPR BitbucketServerPullRequest) []BitbucketBuildStatus {
+	if bbPR.FromRef.LatestCommit == "" {
+		return nil
+	}
+	var statuses BitbucketServerPage[BitbucketBuildStatus]
+	statusesURL := fmt.Sprintf("%s/rest/build-status/1.0/commits/%s", p.baseURL, bbPR.FromRef.LatestCommit)
+	if _, err := p.get(ctx, statusesURL, nil, &statuses); err != nil {
+		log.Debug("Failed fetching Bitbucket Server build statuses", "pr", bbPR.Id, "err", err)
+	}
+	return statuses.Values
+}
+
+func (p *BitbucketProvider) fetchServerPullRequest(ctx context.Context, repo string, id int) (PullRequestData, error) {
+	projectKey, slug, _ := strings.Cut(repo, "/")
+	prURL := fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", p.apiURL, projectKey, slug, id)
+	var bbPR BitbucketServerPullRequest
+	if _, err := p.get(ctx, prURL, nil, &bbPR); err != nil {
+		return PullRequestData{}, err
+	}
+	pr := p.convertServerPullRequestToData(bbPR, p.fetchServerBuildStatuses(ctx, bbPR))
+
+	var changes BitbucketServerPage[BitbucketServerChange]
+	if _, err := p.get(ctx, prURL+"/changes", url.Values{"limit": {"100"}}, &changes); err != nil {
+		log.Debug("Failed fetching Bitbucket Server changes", "pr", pr.Url, "err", err)
+	}
+	pr.Files = ChangedFiles{TotalCount: len(changes.Values)}
+	for _, change := range changes.Values {
+		pr.Files.Nodes = append(pr.Files.Nodes, ChangedFile{
+			Path:       change.Path.ToString,
+			ChangeType: bitbucketChangeType(change.Type),
+		})
+	}
+
+	var activities BitbucketServerPage[BitbucketServerActivity]
+	if _, err := p.get(ctx, prURL+"/activities", url.Values{"limit": {"50"}}, &activities); err != nil {
+		log.Debug("Failed fetching Bitbucket Server activities", "pr", pr.Url, "err", err)
+	}
+	pr.Comments.Nodes = make([]Comment, 0)
+	for _, activity := range activities.Values {
+		if activity.Action != "COMMENTED" || activity.Comment == nil {
+			continue
+		}
+		var comment Comment
+		comment.Author.Login = activity.Comment.Author.Name
+		comment.Body = activity.Comment.Text
+		comment.UpdatedAt = time.UnixMilli(activity.Comment.UpdatedDate)
+		pr.Comments.Nodes = append(pr.Comments.Nodes, comment)
+	}
+
+	return pr, nil
+}
+
+func (p *BitbucketProvider) convertServerPullRequestToData(bbPR BitbucketServerPullRequest, statuses []BitbucketBuildStatus) PullRequestData {
+	toRepo := bbPR.ToRef.Repository
+	repo := bitbucketRepository(toRepo.Project.Key + "/" + toRepo.Slug)
+
+	mergeable, mergeStateStatus := "UNKNOWN", "UNKNOWN"
+	switch bbPR.Properties.MergeResult.Outcome {
+	case "CLEAN":
+		mergeable, mergeStateStatus = "MERGEABLE", "CLEAN"
+	case "CONFLICTED":
+		mergeable, mergeStateStatus = "CONFLICTING", "DIRTY"
+	}
+
+	var prURL string
+	if len(bbPR.Links.Self) > 0 {
+		prURL = bbPR.Links.Self[0].Href
+	}
+
+	pr := PullRequestData{
+		Number:            bbPR.Id,
+		Title:             bbPR.Title,
+		Body:              bbPR.Description,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         time.UnixMilli(bbPR.UpdatedDate),
+		CreatedAt:         time.UnixMilli(bbPR.CreatedDate),
+		Url:               prURL,
+		State:             bitbucketState(bbPR.State),
+		Mergeable:         mergeable,
+		MergeStateStatus:  mergeStateStatus,
+		HeadRefName:       bbPR.FromRef.DisplayId,
+		BaseRefName:       bbPR.ToRef.DisplayId,
+		Repository:        repo,
+		Comments:          Comments{TotalCount: bbPR.Properties.CommentCount},
+		IsDraft:           bbPR.Draft,
+		Commits:           bitbucketCommits(statuses),
+	}
+	pr.Author.Login = bbPR.Author.User.Name
+	pr.HeadRef.Name = bbPR.FromRef.DisplayId
+	pr.HeadRepository.Name = bbPR.FromRef.Repository.Slug
+
+	for _, reviewer := range bbPR.Reviewers {
+		var review Review
+		review.Author.Login = reviewer.User.Name
+		switch reviewer.Status {
+		case "APPROVED":
+			review.State = "APPROVED"
+		case "NEEDS_WORK":
+			review.State = "CHANGES_REQUESTED"
+		default:
+			pr.ReviewRequests.Nodes = append(pr.ReviewRequests.Nodes, ReviewRequest{})
+			continue
+		}
+		pr.Reviews.Nodes = append(pr.Reviews.Nodes, review)
+	}
+	pr.Reviews.TotalCount = len(pr.Reviews.Nodes)
+	pr.ReviewRequests.TotalCount = len(pr.ReviewRequests.Nodes)
+	pr.ReviewDecision = bitbucketReviewDecision(pr.Reviews.Nodes, len(bbPR.Reviewers))
+
+	return pr
+}
+
+// Shared helpers
+
+func (p *BitbucketProvider) get(ctx context.Context, apiURL string, params url.Values, result interface{}) (http.Header, error) {
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuthHeader(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Bitbucket API error: %s", string(body))
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
+// setAuthHeader uses basic auth for `username:app-password` tokens and a bearer
+// token for repository, workspace and HTTP access tokens
+func (p *BitbucketProvider) setAuthHeader(req *http.Request) {
+	if p.token == "" {
+		return
+	}
+	if strings.Contains(p.token, ":") {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.token)))
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+}
+
+func bitbucketRepository(nameWithOwner string) Repository {
+	name := nameWithOwner
+	if idx := strings.LastIndex(nameWithOwner, "/"); idx >= 0 {
+		name = nameWithOwner[idx+1:]
+	}
+	return Repository{Name: name, NameWithOwner: nameWithOwner}
+}
+
+func bitbucketState(state string) string {
+	switch state {
+	case "MERGED":
+		return "MERGED"
+	case "DECLINED", "SUPERSEDED":
+		return "CLOSED"
+	default:
+		return "OPEN"
+	}
+}
+
+func bitbucketChangeType(status string) string {
+	switch strings.ToLower(status) {
+	case "added", "add":
+		return "ADDED"
+	case "removed", "delete":
+		return "DELETED"
+	case "renamed", "move":
+		return "RENAMED"
+	case "copy":
+		return "COPIED"
+	default:
+		return "MODIFIED"
+	}
+}
+
+func bitbucketReviewDecision(reviews []Review, reviewers int) string {
+	approved := false
+	for _, review := range reviews {
+		if review.State == "CHANGES_REQUESTED" {
+			return "CHANGES_REQUESTED"
+		}
+		if review.State == "APPROVED" {
+			approved = true
+		}
+	}
+	if approved {
+		return "APPROVED"
+	}
+	if reviewers > 0 {
+		return "REVIEW_REQUIRED"
+	}
+	return ""
+}
+
+// bitbucketCommits maps build statuses onto GitHub style check runs of the head commit
+func bitbucketCommits(statuses []BitbucketBuildStatus) Commits {
+	if len(statuses) == 0 {
+		return Commits{}
+	}
+
+	var node CommitNode
+	node.Commit.StatusCheckRollup.Contexts.TotalCount = len(statuses)
+	for _, status := range statuses {
+		checkRun := CheckRun{Name: status.Name, Status: "COMPLETED"}
+		if checkRun.Name == "" {
+			checkRun.Name = status.Key
+		}
+		checkRun.CheckSuite.Creator.Login = "bitbucket"
+		checkRun.CheckSuite.WorkflowRun.Workflow.Name = status.Key
+
+		switch status.State {
+		case "SUCCESSFUL":
+			checkRun.Conclusion = "SUCCESS"
+		case "FAILED":
+			checkRun.Conclusion = "FAILURE"
+		case "STOPPED":
+			checkRun.Conclusion = "CANCELLED"
+		case "INPROGRESS":
+			checkRun.Status = "IN_PROGRESS"
+		}
+
+		node.Commit.StatusCheckRollup.Contexts.Nodes = append(node.Commit.StatusCheckRollup.Contexts.Nodes, CheckContext{
+			Typename: "CheckRun",
+			CheckRun: checkRun,
+		})
+	}
+	return Commits{Nodes: []CommitNode{node}, TotalCount: 1}
+}
+
+// pullRequestWebURL returns the web page of a pull request, optionally one of its tabs
+func (p *BitbucketProvider) pullRequestWebURL(prNumber int, repoNameWithOwner string, tab string) string {
+	var prURL string
+	if p.server {
+		projectKey, slug, _ := strings.Cut(repoNameWithOwner, "/")
+		prURL = fmt.Sprintf("%s/projects/%s/repos/%s/pull-requests/%d", p.baseURL, projectKey, slug, prNumber)
+	} else {
+		prURL = fmt.Sprintf("%s/%s/pull-requests/%d", p.baseURL, repoNameWithOwner, prNumber)
+	}
+	if tab != "" {
+		prURL += "/" + tab
+	}
+	return prURL
+}
+
+// Command operations - Bitbucket has no official CLI, so open the pull request in the browser
+func (p *BitbucketProvider) GetDiffCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "diff")}, nil
+}
+
+func (p *BitbucketProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return nil, fmt.Errorf("checkout command not yet implemented for Bitbucket - use git fetch and git checkout manually")
+}
+
+func (p *BitbucketProvider) GetMergeCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
+
+func (p *BitbucketProvider) GetCloseCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
+
+func (p *BitbucketProvider) GetReopenCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
+
+func (p *BitbucketProvider) GetReadyCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
+
+func (p *BitbucketProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
+
+func (p *BitbucketProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", p.pullRequestWebURL(prNumber, repoNameWithOwner, "")}, nil
+}
@@ -0,0 +1,206 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func newBitbucketServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+
+	// Bitbucket Cloud
+	server.handle("/2.0/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		server.writeJSON(w, map[string]string{"uuid": "{me-uuid}", "nickname": "me"})
+	})
+	server.handle("/2.0/repositories/team/app/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, []string{"OPEN"}, q["state"])
+		assert.Equal(t, `reviewers.uuid="{me-uuid}"`, q.Get("q"))
+		assert.Equal(t, "+values.participants,+values.reviewers", q.Get("fields"))
+		server.writeJSON(w, map[string]interface{}{
+			"size": 2,
+			"page": 1,
+			"next": "https://api.bitbucket.org/2.0/repositories/team/app/pullrequests?page=2",
+			"values": []map[string]interface{}{{
+				"id":            4,
+				"title":         "Add login",
+				"state":         "OPEN",
+				"author":        map[string]string{"nickname": "alice", "uuid": "{alice}"},
+				"source":        map[string]interface{}{"branch": map[string]string{"name": "login"}, "repository": map[string]string{"name": "app", "full_name": "team/app"}},
+				"destination":   map[string]interface{}{"branch": map[string]string{"name": "main"}, "repository": map[string]string{"name": "app", "full_name": "team/app"}},
+				"reviewers":     []map[string]string{{"nickname": "bob", "uuid": "{bob}"}, {"nickname": "me", "uuid": "{me-uuid}"}},
+				"participants":  []map[string]interface{}{{"user": map[string]string{"nickname": "bob", "uuid": "{bob}"}, "role": "REVIEWER", "approved": true}},
+				"comment_count": 2,
+				"links":         map[string]interface{}{"html": map[string]string{"href": "https://bitbucket.org/team/app/pull-requests/4"}},
+			}},
+		})
+	})
+	server.handle("/2.0/repositories/team/app/pullrequests/4/statuses", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{"values": []map[string]string{
+			{"key": "build", "name": "Build", "state": "SUCCESSFUL"},
+			{"key": "test", "name": "Tests", "state": "INPROGRESS"},
+		}})
+	})
+
+	// Bitbucket Server
+	server.handle("/rest/api/1.0/application-properties", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-AUSERNAME", "me")
+		server.writeJSON(w, map[string]string{"version": "8.9.0"})
+	})
+	server.handle("/rest/api/1.0/dashboard/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "AUTHOR", q.Get("role"))
+		assert.Equal(t, "OPEN", q.Get("state"))
+		server.writeJSON(w, map[string]interface{}{
+			"isLastPage": true,
+			"start":      0,
+			"values": []map[string]interface{}{{
+				"id":          9,
+				"title":       "Bump deps",
+				"state":       "OPEN",
+				"author":      map[string]interface{}{"user": map[string]string{"name": "me"}},
+				"reviewers":   []map[string]interface{}{{"user": map[string]string{"name": "carol"}, "status": "NEEDS_WORK"}},
+				"fromRef":     map[string]interface{}{"displayId": "deps", "latestCommit": "abc", "repository": map[string]interface{}{"slug": "api", "project": map[string]string{"key": "PLAT"}}},
+				"toRef":       map[string]interface{}{"displayId": "master", "repository": map[string]interface{}{"slug": "api", "project": map[string]string{"key": "PLAT"}}},
+				"createdDate": 1704103200000,
+				"updatedDate": 1704189600000,
+				"properties":  map[string]interface{}{"mergeResult": map[string]string{"outcome": "CONFLICTED"}},
+				"links":       map[string]interface{}{"self": []map[string]string{{"href": "https://bitbucket.example.com/projects/PLAT/repos/api/pull-requests/9"}}},
+			}},
+		})
+	})
+	server.handle("/rest/build-status/1.0/commits/abc", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{"values": []map[string]string{{"key": "ci", "name": "CI", "state": "FAILED"}}})
+	})
+
+	return server
+}
+
+func TestBitbucketCloudFetchPullRequests(t *testing.T) {
+	server := newBitbucketServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Bitbucket})
+	require.False(t, provider.SupportsIssues())
+
+	res, err := provider.FetchPullRequests(context.Background(), "repo:team/app is:open review-requested:@me -label:wontfix milestone:v1", 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bitbucket ignored unsupported filters: -label:wontfix, milestone:v1"}, res.Warnings)
+	require.Equal(t, 2, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Equal(t, "2", res.PageInfo.EndCursor)
+	require.Len(t, res.Prs, 1)
+
+	pr := res.Prs[0]
+	require.Equal(t, 4, pr.Number)
+	require.Equal(t, "OPEN", pr.State)
+	require.Equal(t, "team/app", pr.Repository.NameWithOwner)
+	require.Equal(t, "login", pr.HeadRefName)
+	require.Equal(t, "APPROVED", pr.ReviewDecision)
+	require.Len(t, pr.Reviews.Nodes, 1)
+	require.Equal(t, "bob", pr.Reviews.Nodes[0].Author.Login)
+	require.Equal(t, 1, pr.ReviewRequests.TotalCount)
+
+	checks := pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes
+	require.Len(t, checks, 2)
+	require.Equal(t, "SUCCESS", checks[0].CheckRun.Conclusion)
+	require.Equal(t, "IN_PROGRESS", checks[1].CheckRun.Status)
+}
+
+func TestBitbucketServerFetchPullRequests(t *testing.T) {
+	server := newBitbucketServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.BitbucketServer})
+
+	res, err := provider.FetchPullRequests(context.Background(), "is:open author:@me -author:bob", 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bitbucket Server ignored unsupported filters: -author:bob"}, res.Warnings)
+	require.False(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Prs, 1)
+
+	pr := res.Prs[0]
+	require.Equal(t, 9, pr.Number)
+	require.Equal(t, "PLAT/api", pr.Repository.NameWithOwner)
+	require.Equal(t, "CHANGES_REQUESTED", pr.ReviewDecision)
+	require.Equal(t, "CONFLICTING", pr.Mergeable)
+	require.Equal(t, 2024, pr.CreatedAt.Year())
+	require.Equal(t, "FAILURE", pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes[0].CheckRun.Conclusion)
+}
+
+func TestBitbucketServerFetchClosedPullRequests(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("/rest/api/1.0/projects/PLAT/repos/api/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ALL", r.URL.Query().Get("state"))
+		pr := func(id int, state string) map[string]interface{} {
+			return map[string]interface{}{
+				"id":      id,
+				"state":   state,
+				"fromRef": map[string]interface{}{"displayId": "topic", "repository": map[string]interface{}{"slug": "api", "project": map[string]string{"key": "PLAT"}}},
+				"toRef":   map[string]interface{}{"displayId": "master", "repository": map[string]interface{}{"slug": "api", "project": map[string]string{"key": "PLAT"}}},
+			}
+		}
+		server.writeJSON(w, map[string]interface{}{
+			"isLastPage": true,
+			"start":      0,
+			"values":     []map[string]interface{}{pr(1, "OPEN"), pr(2, "DECLINED"), pr(3, "MERGED")},
+		})
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.BitbucketServer})
+
+	res, err := provider.FetchPullRequests(context.Background(), "repo:PLAT/api is:closed", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Prs, 2)
+	require.Equal(t, "CLOSED", res.Prs[0].State)
+	require.Equal(t, "MERGED", res.Prs[1].State)
+	require.Equal(t, 2, res.TotalCount)
+	require.Len(t, res.Warnings, 1)
+}
+
+func TestParseBitbucketRemoteURL(t *testing.T) {
+	testCases := map[string]struct {
+		remote string
+		want   providers.RemoteInfo
+	}{
+		"cloud https with user": {
+			remote: "https://alice@bitbucket.org/team/app.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.Bitbucket,
+				Organization: "team",
+				Repository:   "app",
+				BaseURL:      "https://bitbucket.org",
+			},
+		},
+		"cloud ssh": {
+			remote: "git@bitbucket.org:team/app.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.Bitbucket,
+				Organization: "team",
+				Repository:   "app",
+				BaseURL:      "https://bitbucket.org",
+			},
+		},
+		"server with context path": {
+			remote: "https://bitbucket.example.com/stash/scm/PLAT/api.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.BitbucketServer,
+				Organization: "PLAT",
+				Repository:   "api",
+				BaseURL:      "https://bitbucket.example.com/stash",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providers.ParseGitRemoteURL(tc.remote)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *got)
+			require.Equal(t, tc.want.Provider, providers.DetectProviderFromURL(tc.remote))
+		})
+	}
+}
@@ -0,0 +1,744 @@
+package providers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+	"github.com/dlvhdr/gh-dash/v4/providers/providertest"
+)
+
+// conformancePage pages through n items with the offset of the next item as
+// the cursor, returning the bounds of the page
+func conformancePage(n int, cursor string, limit int) (int, int) {
+	start, _ := strconv.Atoi(cursor)
+	start = min(start, n)
+	return start, min(start+limit, n)
+}
+
+// conformanceNumber returns the number at the end of a URL path
+func conformanceNumber(t *testing.T, path string) int {
+	number, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	assert.NoError(t, err, path)
+	return number
+}
+
+// conformancePageNumber pages through n items with the number of the page,
+// from 1, as the cursor, returning the bounds of the page and the number of the
+// next page when there is one
+func conformancePageNumber(n int, page string, perPage int) (int, int, string) {
+	number, err := strconv.Atoi(page)
+	if err != nil {
+		number = 1
+	}
+	start, end := conformancePage(n, strconv.Itoa((number-1)*perPage), perPage)
+	var next string
+	if end < n {
+		next = strconv.Itoa(number + 1)
+	}
+	return start, end, next
+}
+
+// conformancePullRequestsWithState returns the pull requests with one of the
+// states, or all of them when there are none
+func conformancePullRequestsWithState(states ...string) []providertest.PullRequest {
+	if len(states) == 0 {
+		return providertest.PullRequests
+	}
+	var prs []providertest.PullRequest
+	for _, pr := range providertest.PullRequests {
+		for _, state := range states {
+			if pr.State == state {
+				prs = append(prs, pr)
+			}
+		}
+	}
+	return prs
+}
+
+func findConformancePullRequest(number int) (providertest.PullRequest, bool) {
+	for _, pr := range providertest.PullRequests {
+		if pr.Number == number {
+			return pr, true
+		}
+	}
+	return providertest.PullRequest{}, false
+}
+
+// newGitHubConformanceBackend serves the search and resource queries of the
+// GraphQL API
+func newGitHubConformanceBackend(t *testing.T) providertest.Backend {
+	prURL := func(number int) string {
+		return fmt.Sprintf("https://ghe.example.com/%s/pull/%d", providertest.Repo, number)
+	}
+	repository := map[string]interface{}{"name": "widgets", "nameWithOwner": providertest.Repo}
+	prNode := func(pr providertest.PullRequest) map[string]interface{} {
+		return map[string]interface{}{
+			"number":     pr.Number,
+			"title":      pr.Title,
+			"author":     map[string]string{"login": pr.Author},
+			"state":      pr.State,
+			"isDraft":    pr.IsDraft,
+			"url":        prURL(pr.Number),
+			"createdAt":  pr.CreatedAt,
+			"updatedAt":  pr.UpdatedAt,
+			"repository": repository,
+		}
+	}
+
+	server := newFakeServer(t)
+	server.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if !server.readJSON(r, &req) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.Contains(req.Query, "search("):
+			query := req.Variables["query"].(string)
+			var nodes []map[string]interface{}
+			if strings.Contains(query, "is:issue") {
+				for _, issue := range providertest.Issues {
+					nodes = append(nodes, map[string]interface{}{
+						"number":     issue.Number,
+						"title":      issue.Title,
+						"author":     map[string]string{"login": issue.Author},
+						"state":      issue.State,
+						"url":        fmt.Sprintf("https://ghe.example.com/%s/issues/%d", providertest.Repo, issue.Number),
+						"createdAt":  issue.CreatedAt,
+						"updatedAt":  issue.UpdatedAt,
+						"repository": repository,
+					})
+				}
+			} else {
+				for _, pr := range providertest.PullRequests {
+					if (strings.Contains(query, "is:open") && pr.State != "OPEN") ||
+						(strings.Contains(query, "is:merged") && pr.State != "MERGED") {
+						continue
+					}
+					nodes = append(nodes, prNode(pr))
+				}
+			}
+
+			cursor, _ := req.Variables["endCursor"].(string)
+			start, end := conformancePage(len(nodes), cursor, int(req.Variables["limit"].(float64)))
+			server.writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+				"search": map[string]interface{}{
+					"nodes":      nodes[start:end],
+					"issueCount": len(nodes),
+					"pageInfo":   map[string]interface{}{"hasNextPage": end < len(nodes), "endCursor": strconv.Itoa(end)},
+				},
+			}})
+		case strings.Contains(req.Query, "resource(url: $url)"):
+			pr, ok := findConformancePullRequest(conformanceNumber(t, req.Variables["url"].(string)))
+			if !assert.True(t, ok) {
+				return
+			}
+			server.writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+				"resource": prNode(pr),
+			}})
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	})
+
+	return providertest.Backend{
+		Provider: newTestGitHubProvider(t, server),
+		URL:      prURL,
+		Fail:     server.fail,
+	}
+}
+
+// newAzureDevOpsConformanceBackend serves the pull requests, threads and work
+// items APIs for the acme project. Pull requests have no update date, only
+// their threads do
+func newAzureDevOpsConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	azureStatus := map[string]string{"OPEN": "active", "MERGED": "completed", "CLOSED": "abandoned"}
+	azurePR := func(pr providertest.PullRequest) map[string]interface{} {
+		azure := map[string]interface{}{
+			"pullRequestId": pr.Number,
+			"title":         pr.Title,
+			"status":        azureStatus[pr.State],
+			"isDraft":       pr.IsDraft,
+			"createdBy":     map[string]string{"displayName": pr.Author},
+			"creationDate":  pr.CreatedAt,
+			"repository": map[string]interface{}{
+				"id":      "widgets-id",
+				"name":    "widgets",
+				"project": map[string]string{"id": "acme-id", "name": "acme"},
+			},
+		}
+		if pr.State != "OPEN" {
+			azure["closedDate"] = pr.UpdatedAt.Add(-time.Hour)
+		}
+		return azure
+	}
+
+	server.handle("/org/acme/_apis/git/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var values []map[string]interface{}
+		for _, pr := range providertest.PullRequests {
+			if status := q.Get("searchCriteria.status"); status == azureStatus[pr.State] || status == "all" {
+				values = append(values, azurePR(pr))
+			}
+		}
+		top, err := strconv.Atoi(q.Get("$top"))
+		assert.NoError(t, err)
+		start, end := conformancePage(len(values), q.Get("$skip"), top)
+		server.writeJSON(w, map[string]interface{}{"count": end - start, "value": values[start:end]})
+	})
+	server.handle("/org/_apis/git/pullrequests/", func(w http.ResponseWriter, r *http.Request) {
+		pr, ok := findConformancePullRequest(conformanceNumber(t, r.URL.Path))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, azurePR(pr))
+	})
+	server.handle("/org/acme/_apis/git/repositories/widgets-id/pullrequests/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/org/acme/_apis/git/repositories/widgets-id/pullrequests/")
+		number, rest, _ := strings.Cut(path, "/")
+		if rest != "threads" {
+			server.writeJSON(w, map[string]interface{}{"value": []interface{}{}})
+			return
+		}
+		pr, ok := findConformancePullRequest(conformanceNumber(t, number))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{{
+			"id":              1,
+			"lastUpdatedDate": pr.UpdatedAt,
+			"properties":      map[string]interface{}{"CodeReviewThreadType": map[string]string{"$value": "RefUpdate"}},
+			"comments": []map[string]interface{}{{
+				"author": map[string]string{"displayName": pr.Author}, "content": "Updated the pull request",
+				"commentType": "system", "lastUpdatedDate": pr.UpdatedAt,
+			}},
+		}}})
+	})
+	server.handle("/org/acme/_apis/wit/wiql", func(w http.ResponseWriter, r *http.Request) {
+		var ids []map[string]int
+		for _, issue := range providertest.Issues {
+			ids = append(ids, map[string]int{"id": issue.Number})
+		}
+		server.writeJSON(w, map[string]interface{}{"workItems": ids})
+	})
+	server.handle("/org/acme/_apis/wit/workitemsbatch", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Ids []int `json:"ids"`
+		}
+		if !server.readJSON(r, &payload) {
+			return
+		}
+		var values []map[string]interface{}
+		for _, id := range payload.Ids {
+			for _, issue := range providertest.Issues {
+				if issue.Number != id {
+					continue
+				}
+				state := "Active"
+				if issue.State == "CLOSED" {
+					state = "Done"
+				}
+				values = append(values, map[string]interface{}{
+					"id": issue.Number,
+					"fields": map[string]interface{}{
+						"System.Title":       issue.Title,
+						"System.State":       state,
+						"System.TeamProject": "acme",
+						"System.CreatedBy":   map[string]string{"displayName": issue.Author},
+						"System.CreatedDate": issue.CreatedAt,
+						"System.ChangedDate": issue.UpdatedAt,
+					},
+				})
+			}
+		}
+		server.writeJSON(w, map[string]interface{}{"count": len(values), "value": values})
+	})
+	// Policy evaluations and builds
+	server.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{"value": []interface{}{}})
+	})
+
+	return providertest.Backend{
+		Provider: server.newProvider(providers.ProviderConfig{
+			Type:         providers.AzureDevOps,
+			Organization: "org",
+			Project:      "acme",
+		}),
+		URL: func(number int) string {
+			return fmt.Sprintf("%s/org/acme/_git/widgets/pullrequest/%d", server.URL, number)
+		},
+		Fail: server.fail,
+	}
+}
+
+// newGitLabConformanceBackend serves the merge requests and issues APIs. The
+// details of merge requests, such as pipelines and approvals, are empty
+func newGitLabConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	gitLabState := map[string]string{"OPEN": "opened", "MERGED": "merged", "CLOSED": "closed"}
+	mrURL := func(number int) string {
+		return fmt.Sprintf("%s/%s/-/merge_requests/%d", server.URL, providertest.Repo, number)
+	}
+	gitLabMR := func(pr providertest.PullRequest) map[string]interface{} {
+		return map[string]interface{}{
+			"iid":        pr.Number,
+			"project_id": 1,
+			"title":      pr.Title,
+			"state":      gitLabState[pr.State],
+			"draft":      pr.IsDraft,
+			"author":     map[string]string{"username": pr.Author},
+			"created_at": pr.CreatedAt,
+			"updated_at": pr.UpdatedAt,
+			"web_url":    mrURL(pr.Number),
+		}
+	}
+	// writePage answers a list request with the page it asks for, and the
+	// pagination headers of GitLab
+	writePage := func(w http.ResponseWriter, r *http.Request, values []map[string]interface{}) {
+		perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+		assert.NoError(t, err)
+		start, end, next := conformancePageNumber(len(values), r.URL.Query().Get("page"), perPage)
+		w.Header().Set("X-Total", strconv.Itoa(len(values)))
+		w.Header().Set("X-Next-Page", next)
+		server.writeJSON(w, values[start:end])
+	}
+
+	server.handle("/api/v4/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		var states []string
+		for state, gitLab := range gitLabState {
+			if r.URL.Query().Get("state") == gitLab {
+				states = append(states, state)
+			}
+		}
+		values := []map[string]interface{}{}
+		for _, pr := range conformancePullRequestsWithState(states...) {
+			values = append(values, gitLabMR(pr))
+		}
+		writePage(w, r, values)
+	})
+	server.handle("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.Path; {
+		case strings.HasSuffix(path, "/approvals"):
+			server.writeJSON(w, map[string]interface{}{})
+		case strings.HasSuffix(path, "/pipelines"), strings.HasSuffix(path, "/diffs"), strings.HasSuffix(path, "/notes"):
+			server.writeJSON(w, []interface{}{})
+		default:
+			pr, ok := findConformancePullRequest(conformanceNumber(t, path))
+			if !assert.True(t, ok) {
+				return
+			}
+			server.writeJSON(w, gitLabMR(pr))
+		}
+	})
+	server.handle("/api/v4/issues", func(w http.ResponseWriter, r *http.Request) {
+		values := []map[string]interface{}{}
+		for _, issue := range providertest.Issues {
+			state := "opened"
+			if issue.State == "CLOSED" {
+				state = "closed"
+			}
+			values = append(values, map[string]interface{}{
+				"iid":        issue.Number,
+				"project_id": 1,
+				"title":      issue.Title,
+				"state":      state,
+				"author":     map[string]string{"username": issue.Author},
+				"created_at": issue.CreatedAt,
+				"updated_at": issue.UpdatedAt,
+				"web_url":    fmt.Sprintf("%s/%s/-/issues/%d", server.URL, providertest.Repo, issue.Number),
+			})
+		}
+		writePage(w, r, values)
+	})
+
+	return providertest.Backend{
+		Provider: server.newProvider(providers.ProviderConfig{Type: providers.GitLab}),
+		URL:      mrURL,
+		Fail:     server.fail,
+	}
+}
+
+// newGiteaConformanceBackend serves the issue search and the pull requests of
+// the repository. Merged pull requests are closed issues, which the provider
+// filters
+func newGiteaConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	repository := map[string]string{"name": "widgets", "full_name": providertest.Repo}
+	prURL := func(number int) string {
+		return fmt.Sprintf("%s/%s/pulls/%d", server.URL, providertest.Repo, number)
+	}
+	giteaState := func(state string) string {
+		if state == "OPEN" {
+			return "open"
+		}
+		return "closed"
+	}
+
+	server.handle("/api/v1/repos/issues/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		values := []map[string]interface{}{}
+		if q.Get("type") == "pulls" {
+			var states []string
+			switch q.Get("state") {
+			case "open":
+				states = []string{"OPEN"}
+			case "closed":
+				states = []string{"MERGED", "CLOSED"}
+			}
+			for _, pr := range conformancePullRequestsWithState(states...) {
+				values = append(values, map[string]interface{}{
+					"number":       pr.Number,
+					"title":        pr.Title,
+					"state":        giteaState(pr.State),
+					"user":         map[string]string{"login": pr.Author},
+					"created_at":   pr.CreatedAt,
+					"updated_at":   pr.UpdatedAt,
+					"html_url":     prURL(pr.Number),
+					"repository":   repository,
+					"pull_request": map[string]bool{"merged": pr.State == "MERGED", "draft": pr.IsDraft},
+				})
+			}
+		} else {
+			for _, issue := range providertest.Issues {
+				values = append(values, map[string]interface{}{
+					"number":     issue.Number,
+					"title":      issue.Title,
+					"state":      giteaState(issue.State),
+					"user":       map[string]string{"login": issue.Author},
+					"created_at": issue.CreatedAt,
+					"updated_at": issue.UpdatedAt,
+					"html_url":   fmt.Sprintf("%s/%s/issues/%d", server.URL, providertest.Repo, issue.Number),
+					"repository": repository,
+				})
+			}
+		}
+
+		limit, err := strconv.Atoi(q.Get("limit"))
+		assert.NoError(t, err)
+		start, end, next := conformancePageNumber(len(values), q.Get("page"), limit)
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(values)))
+		if next != "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%s>; rel="next"`, server.URL, r.URL.Path, next))
+		}
+		server.writeJSON(w, values[start:end])
+	})
+	server.handle("/api/v1/repos/acme/widgets/", func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if !strings.Contains(path, "/pulls/") || strings.HasSuffix(path, "/reviews") || strings.HasSuffix(path, "/files") {
+			server.writeJSON(w, []interface{}{})
+			return
+		}
+		pr, ok := findConformancePullRequest(conformanceNumber(t, path))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, map[string]interface{}{
+			"number":     pr.Number,
+			"title":      pr.Title,
+			"state":      giteaState(pr.State),
+			"draft":      pr.IsDraft,
+			"merged":     pr.State == "MERGED",
+			"user":       map[string]string{"login": pr.Author},
+			"created_at": pr.CreatedAt,
+			"updated_at": pr.UpdatedAt,
+			"html_url":   prURL(pr.Number),
+			"base":       map[string]interface{}{"ref": "main", "repo": repository},
+		})
+	})
+
+	return providertest.Backend{
+		Provider: server.newProvider(providers.ProviderConfig{Type: providers.Gitea}),
+		URL:      prURL,
+		Fail:     server.fail,
+	}
+}
+
+// newBitbucketConformanceBackend serves the pull requests of the repository
+// the provider is configured with on Bitbucket Cloud
+func newBitbucketConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	repository := map[string]string{"name": "widgets", "full_name": providertest.Repo}
+	bitbucketState := map[string]string{"OPEN": "OPEN", "MERGED": "MERGED", "CLOSED": "DECLINED"}
+	prURL := func(number int) string {
+		return fmt.Sprintf("%s/%s/pull-requests/%d", server.URL, providertest.Repo, number)
+	}
+	bitbucketPR := func(pr providertest.PullRequest) map[string]interface{} {
+		return map[string]interface{}{
+			"id":          pr.Number,
+			"title":       pr.Title,
+			"state":       bitbucketState[pr.State],
+			"draft":       pr.IsDraft,
+			"author":      map[string]string{"nickname": pr.Author},
+			"destination": map[string]interface{}{"branch": map[string]string{"name": "main"}, "repository": repository},
+			"created_on":  pr.CreatedAt,
+			"updated_on":  pr.UpdatedAt,
+			"links":       map[string]interface{}{"html": map[string]string{"href": prURL(pr.Number)}},
+		}
+	}
+
+	server.handle("/2.0/repositories/acme/widgets/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var states []string
+		for state, bitbucket := range bitbucketState {
+			for _, requested := range q["state"] {
+				if requested == bitbucket {
+					states = append(states, state)
+				}
+			}
+		}
+		values := []map[string]interface{}{}
+		for _, pr := range conformancePullRequestsWithState(states...) {
+			values = append(values, bitbucketPR(pr))
+		}
+
+		pageLen, err := strconv.Atoi(q.Get("pagelen"))
+		assert.NoError(t, err)
+		start, end, next := conformancePageNumber(len(values), q.Get("page"), pageLen)
+		page, _ := strconv.Atoi(q.Get("page"))
+		res := map[string]interface{}{"size": len(values), "page": page, "values": values[start:end]}
+		if next != "" {
+			res["next"] = fmt.Sprintf("%s%s?page=%s", server.URL, r.URL.Path, next)
+		}
+		server.writeJSON(w, res)
+	})
+	server.handle("/2.0/repositories/acme/widgets/pullrequests/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/2.0/repositories/acme/widgets/pullrequests/")
+		number, rest, _ := strings.Cut(path, "/")
+		if rest != "" {
+			// Build statuses, diffstat and comments
+			server.writeJSON(w, map[string]interface{}{"values": []interface{}{}})
+			return
+		}
+		pr, ok := findConformancePullRequest(conformanceNumber(t, number))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, bitbucketPR(pr))
+	})
+
+	return providertest.Backend{
+		Provider: server.newProvider(providers.ProviderConfig{
+			Type:         providers.Bitbucket,
+			Organization: "acme",
+			Repository:   "widgets",
+		}),
+		URL:  prURL,
+		Fail: server.fail,
+	}
+}
+
+// newBitbucketServerConformanceBackend serves the pull requests of the
+// repository the provider is configured with on Bitbucket Server, which
+// doesn't count them
+func newBitbucketServerConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	repository := map[string]interface{}{"slug": "widgets", "project": map[string]string{"key": "acme"}}
+	bitbucketState := map[string]string{"OPEN": "OPEN", "MERGED": "MERGED", "CLOSED": "DECLINED"}
+	prURL := func(number int) string {
+		return fmt.Sprintf("%s/projects/acme/repos/widgets/pull-requests/%d", server.URL, number)
+	}
+	bitbucketPR := func(pr providertest.PullRequest) map[string]interface{} {
+		return map[string]interface{}{
+			"id":          pr.Number,
+			"title":       pr.Title,
+			"state":       bitbucketState[pr.State],
+			"draft":       pr.IsDraft,
+			"author":      map[string]interface{}{"user": map[string]string{"name": pr.Author}},
+			"toRef":       map[string]interface{}{"displayId": "main", "repository": repository},
+			"createdDate": pr.CreatedAt.UnixMilli(),
+			"updatedDate": pr.UpdatedAt.UnixMilli(),
+			"links":       map[string]interface{}{"self": []map[string]string{{"href": prURL(pr.Number)}}},
+		}
+	}
+
+	server.handle("/rest/api/1.0/projects/acme/repos/widgets/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var states []string
+		for state, bitbucket := range bitbucketState {
+			if q.Get("state") == bitbucket {
+				states = append(states, state)
+			}
+		}
+		values := []map[string]interface{}{}
+		for _, pr := range conformancePullRequestsWithState(states...) {
+			values = append(values, bitbucketPR(pr))
+		}
+
+		limit, err := strconv.Atoi(q.Get("limit"))
+		assert.NoError(t, err)
+		start, end := conformancePage(len(values), q.Get("start"), limit)
+		server.writeJSON(w, map[string]interface{}{
+			"start":         start,
+			"isLastPage":    end == len(values),
+			"nextPageStart": end,
+			"values":        values[start:end],
+		})
+	})
+	server.handle("/rest/api/1.0/projects/acme/repos/widgets/pull-requests/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/rest/api/1.0/projects/acme/repos/widgets/pull-requests/")
+		number, rest, _ := strings.Cut(path, "/")
+		if rest != "" {
+			// Changes and activities
+			server.writeJSON(w, map[string]interface{}{"isLastPage": true, "values": []interface{}{}})
+			return
+		}
+		pr, ok := findConformancePullRequest(conformanceNumber(t, number))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, bitbucketPR(pr))
+	})
+
+	return providertest.Backend{
+		Provider: server.newProvider(providers.ProviderConfig{
+			Type:         providers.BitbucketServer,
+			Organization: "acme",
+			Repository:   "widgets",
+		}),
+		URL:  prURL,
+		Fail: server.fail,
+	}
+}
+
+// newPluginConformanceBackend serves the methods of the plugin protocol, which
+// the plugin relay of the test binary posts to the server
+func newPluginConformanceBackend(t *testing.T) providertest.Backend {
+	server := newFakeServer(t)
+	prURL := func(number int) string {
+		return fmt.Sprintf("https://review.example.com/%s/changes/%d", providertest.Repo, number)
+	}
+	repository := providers.Repository{Name: "widgets", NameWithOwner: providertest.Repo}
+	pluginPR := func(pr providertest.PullRequest) providers.PullRequestData {
+		data := providers.PullRequestData{
+			Number:     pr.Number,
+			Title:      pr.Title,
+			State:      pr.State,
+			IsDraft:    pr.IsDraft,
+			Url:        prURL(pr.Number),
+			CreatedAt:  pr.CreatedAt,
+			UpdatedAt:  pr.UpdatedAt,
+			Repository: repository,
+		}
+		data.Author.Login = pr.Author
+		return data
+	}
+	type pluginParams struct {
+		Query  string `json:"query"`
+		Limit  int    `json:"limit"`
+		Cursor string `json:"cursor"`
+		Url    string `json:"url"`
+	}
+
+	server.handle("/initialize", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"name":         "conformance",
+			"pullRequests": true,
+			"issues":       true,
+			"commands": map[string][]string{
+				"diff": {"open", "https://review.example.com/{repo}/changes/{number}/diff"},
+			},
+		})
+	})
+	server.handle("/listPullRequests", func(w http.ResponseWriter, r *http.Request) {
+		var params pluginParams
+		if !server.readJSON(r, &params) {
+			return
+		}
+		var states []string
+		for _, state := range []string{"OPEN", "MERGED"} {
+			if strings.Contains(params.Query, "is:"+strings.ToLower(state)) {
+				states = append(states, state)
+			}
+		}
+		prs := []providers.PullRequestData{}
+		for _, pr := range conformancePullRequestsWithState(states...) {
+			prs = append(prs, pluginPR(pr))
+		}
+		start, end := conformancePage(len(prs), params.Cursor, params.Limit)
+		server.writeJSON(w, providers.PullRequestsResponse{
+			Prs:        prs[start:end],
+			TotalCount: len(prs),
+			PageInfo:   providers.PageInfo{HasNextPage: end < len(prs), EndCursor: strconv.Itoa(end)},
+		})
+	})
+	server.handle("/listIssues", func(w http.ResponseWriter, r *http.Request) {
+		var params pluginParams
+		if !server.readJSON(r, &params) {
+			return
+		}
+		var issues []providers.IssueData
+		for _, issue := range providertest.Issues {
+			data := providers.IssueData{
+				Number:     issue.Number,
+				Title:      issue.Title,
+				State:      issue.State,
+				Url:        fmt.Sprintf("https://review.example.com/%s/issues/%d", providertest.Repo, issue.Number),
+				CreatedAt:  issue.CreatedAt,
+				UpdatedAt:  issue.UpdatedAt,
+				Repository: repository,
+			}
+			data.Author.Login = issue.Author
+			issues = append(issues, data)
+		}
+		start, end := conformancePage(len(issues), params.Cursor, params.Limit)
+		server.writeJSON(w, providers.IssuesResponse{
+			Issues:     issues[start:end],
+			TotalCount: len(issues),
+			PageInfo:   providers.PageInfo{HasNextPage: end < len(issues), EndCursor: strconv.Itoa(end)},
+		})
+	})
+	server.handle("/getPullRequest", func(w http.ResponseWriter, r *http.Request) {
+		var params pluginParams
+		if !server.readJSON(r, &params) {
+			return
+		}
+		pr, ok := findConformancePullRequest(conformanceNumber(t, params.Url))
+		if !assert.True(t, ok) {
+			return
+		}
+		server.writeJSON(w, pluginPR(pr))
+	})
+
+	return providertest.Backend{
+		Provider: newPluginRelayProvider(t, server),
+		URL:      prURL,
+		Fail:     server.fail,
+	}
+}
+
+func TestGitHubConformance(t *testing.T) {
+	providertest.Run(t, newGitHubConformanceBackend)
+}
+
+func TestAzureDevOpsConformance(t *testing.T) {
+	providertest.Run(t, newAzureDevOpsConformanceBackend)
+}
+
+func TestGitLabConformance(t *testing.T) {
+	providertest.Run(t, newGitLabConformanceBackend)
+}
+
+func TestGiteaConformance(t *testing.T) {
+	providertest.Run(t, newGiteaConformanceBackend)
+}
+
+func TestBitbucketConformance(t *testing.T) {
+	providertest.Run(t, newBitbucketConformanceBackend)
+}
+
+func TestBitbucketServerConformance(t *testing.T) {
+	providertest.Run(t, newBitbucketServerConformanceBackend)
+}
+
+func TestPluginConformance(t *testing.T) {
+	providertest.Run(t, newPluginConformanceBackend)
+}
@@ -0,0 +1,195 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// defaultCredentialHosts are the hosts asked from the git credential helper
+// when no baseUrl is configured
+var defaultCredentialHosts = map[ProviderType]string{
+	GitHub:      "github.com",
+	AzureDevOps: "dev.azure.com",
+	GitLab:      "gitlab.com",
+	Bitbucket:   "bitbucket.org",
+}
+
+// ResolveToken reads the token from the credential sources of the provider:
+// the output of TokenCommand, the contents of TokenFile, or the password the
+// git credential helper has for the host. It returns an empty token when no
+// source is configured, and an error when a configured source fails
+func ResolveToken(cfg ProviderConfig) (string, string, error) {
+	if cfg.TokenCommand != "" {
+		token, err := tokenFromCommand(cfg.TokenCommand)
+		return token, "tokenCommand", err
+	}
+	if cfg.TokenFile != "" {
+		token, err := tokenFromFile(cfg.TokenFile)
+		return token, "tokenFile", err
+	}
+	if cfg.GitCredential {
+		token, err := tokenFromGitCredential(cfg)
+		return token, "git credential", err
+	}
+	return "", "", nil
+}
+
+func tokenFromCommand(command string) (string, error) {
+	log.Debug("Reading token from command")
+	c := shellCommand(command)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return "", fmt.Errorf("tokenCommand failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("tokenCommand printed no token")
+	}
+	return token, nil
+}
+
+// shellCommand runs a command line from the config through the shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// tokenFromFile reads a token file, refusing files that other users can read
+func tokenFromFile(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	log.Debug("Reading token from file", "path", path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	// Windows doesn't report permissions through the mode bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("token file %s can be read by other users, restrict it with chmod 600", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// tokenFromGitCredential asks the git credential helper for the password of
+// the host. Bitbucket Cloud app passwords are returned as `username:password`
+func tokenFromGitCredential(cfg ProviderConfig) (string, error) {
+	host := hostFromURL(cfg.BaseURL)
+	if host == "" {
+		host = defaultCredentialHosts[cfg.Type]
+	}
+	if host == "" {
+		return "", fmt.Errorf("gitCredential requires a baseUrl for %s", cfg.Type)
+	}
+	log.Debug("Reading token from git credential", "host", host)
+
+	c := exec.Command("git", "credential", "fill")
+	c.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	// Fail instead of prompting, which would break the UI
+	c.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := c.Output()
+	if err != nil {
+		return "", fmt.Errorf("git credential fill failed for %s: %w", host, err)
+	}
+
+	credential := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			credential[key] = value
+		}
+	}
+	password := credential["password"]
+	if password == "" {
+		return "", fmt.Errorf("git credential has no password for %s", host)
+	}
+	if cfg.Type == Bitbucket && credential["username"] != "" {
+		return credential["username"] + ":" + password, nil
+	}
+	return password, nil
+}
+
+// minSecretLength keeps short values, which are likely ordinary words, from
+// being redacted everywhere
+const minSecretLength = 8
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+	// urlCredentialsRe matches the credentials of URLs such as remotes with an embedded token
+	urlCredentialsRe = regexp.MustCompile(`(://[^/@\s:]+:)[^/@\s]+@`)
+)
+
+// RegisterSecret adds a token to the values RedactSecrets hides. The password
+// of `username:password` tokens is registered on its own too
+func RegisterSecret(secret string) {
+	values := []string{secret}
+	if _, password, ok := strings.Cut(secret, ":"); ok {
+		values = append(values, password)
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		if len(value) >= minSecretLength && !slices.Contains(secrets, value) {
+			secrets = append(secrets, value)
+		}
+	}
+}
+
+// RedactSecrets replaces the registered secrets and the credentials of URLs in s
+func RedactSecrets(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, "[REDACTED]")
+	}
+	return urlCredentialsRe.ReplaceAllString(s, "$1[REDACTED]@")
+}
+
+type redactingWriter struct {
+	w io.Writer
+}
+
+// NewRedactingWriter returns a writer that redacts secrets before writing to w.
+// It is used for the debug log, so tokens don't end up in debug.log
+func NewRedactingWriter(w io.Writer) io.Writer {
+	return redactingWriter{w: w}
+}
+
+func (rw redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, RedactSecrets(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
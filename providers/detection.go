@@ -3,6 +3,7 @@ package providers
 import (
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // ParseRemoteURL extracts provider information from a git remote URL
@@ -20,40 +21,208 @@ func ParseGitRemoteURL(remoteURL string) (*RemoteInfo, error) {
 	url := strings.TrimSpace(remoteURL)
 	
 	// Handle SSH URLs by converting them to HTTPS format for parsing
-	if strings.HasPrefix(url, "git@") {
-		url = convertSSHToHTTPS(url)
-	}
+	url = convertSSHToHTTPS(url)
 	
 	// Detect Azure DevOps
 	if azureInfo := parseAzureDevOpsURL(url); azureInfo != nil {
 		return azureInfo, nil
 	}
+
+	// Detect GitLab, both gitlab.com and registered self-hosted instances
+	if gitLabInfo := parseGitLabURL(url); gitLabInfo != nil {
+		return gitLabInfo, nil
+	}
+
+	// Detect Gitea and Forgejo instances
+	if giteaInfo := parseGiteaURL(url); giteaInfo != nil {
+		return giteaInfo, nil
+	}
+
+	// Detect Bitbucket Cloud and Bitbucket Server
+	if bitbucketInfo := parseBitbucketURL(url); bitbucketInfo != nil {
+		return bitbucketInfo, nil
+	}
 	
 	// Default to GitHub
 	return parseGitHubURL(url), nil
 }
 
+var (
+	sshURLRegexp       = regexp.MustCompile(`^(?:git\+)?ssh://(?:[^@/]+@)?([^/:]+)(?::\d+)?/(.+)$`)
+	scpLikeURLRegexp   = regexp.MustCompile(`^(?:[^@/:]+@)?([^@/:]+):(.+)$`)
+	azureSSHPathRegexp = regexp.MustCompile(`^v3/([^/]+)/([^/]+)/(.+)$`)
+	httpHostRegexp     = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/:]+)`)
+	urlUserinfoRegexp  = regexp.MustCompile(`^(https?://)[^@/]+@`)
+
+	gitLabURLRegexp    = regexp.MustCompile(`^https?://(?:[^@/]+@)?[^/]+/(.+)/([^/]+?)(?:\.git)?/?$`)
+	giteaURLRegexp     = regexp.MustCompile(`^(https?://(?:[^@/]+@)?[^/]+(?:/.+)?)/([^/]+)/([^/]+?)(?:\.git)?/?$`)
+	bitbucketURLRegexp = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/]+)/(.+/)?([^/]+)/([^/]+?)(?:\.git)?/?$`)
+	gitHubURLRegexp    = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/:]+)(?::\d+)?/([^/]+)/(.+?)(?:\.git)?/?$`)
+
+	// azureDevOpsURLRegexps match dev.azure.com, visualstudio.com and on-premises TFS URLs, in that order
+	azureDevOpsURLRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`https://dev\.azure\.com/([^/]+)/([^/]+)/_git/(.+?)(?:\.git)?/?$`),
+		regexp.MustCompile(`https://([^.]+)\.visualstudio\.com/([^/]+)/_git/(.+?)(?:\.git)?/?$`),
+		regexp.MustCompile(`https://([^/]+)/tfs/([^/]+)/([^/]+)/_git/(.+?)(?:\.git)?/?$`),
+	}
+	azureDevOpsServerURLRegexp = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/]+)/([^/]+)/([^/]+)/_git/(.+?)(?:\.git)?/?$`)
+)
+
+// convertSSHToHTTPS converts the SSH forms of a remote URL into the HTTPS URL
+// of the same repository, resolving host aliases from ~/.ssh/config:
+//   - scp-like: [user@]host:path, e.g. git@github.com:owner/repo.git or gh-work:owner/repo
+//   - URLs: ssh://[user@]host[:port]/path and git+ssh://...
+//
+// Azure DevOps SSH paths (v3/org/project/repo) are mapped onto their _git URLs.
+// Other URLs are returned unchanged
 func convertSSHToHTTPS(sshURL string) string {
-	// Convert git@github.com:owner/repo.git to https://github.com/owner/repo.git
-	// Convert git@ssh.dev.azure.com:v3/org/project/repo to https://dev.azure.com/org/project/_git/repo
-	
-	if strings.Contains(sshURL, "github.com") {
-		re := regexp.MustCompile(`git@github\.com:([^/]+)/(.+)\.git`)
-		matches := re.FindStringSubmatch(sshURL)
-		if len(matches) == 3 {
-			return "https://github.com/" + matches[1] + "/" + matches[2] + ".git"
-		}
+	var host, path string
+	if matches := sshURLRegexp.FindStringSubmatch(sshURL); len(matches) == 3 {
+		host, path = matches[1], matches[2]
+	} else if strings.Contains(sshURL, "://") {
+		return sshURL
+	} else if matches := scpLikeURLRegexp.FindStringSubmatch(sshURL); len(matches) == 3 {
+		host, path = matches[1], strings.TrimPrefix(matches[2], "/")
+	} else {
+		return sshURL
 	}
-	
-	if strings.Contains(sshURL, "dev.azure.com") || strings.Contains(sshURL, "ssh.dev.azure.com") {
-		re := regexp.MustCompile(`git@ssh\.dev\.azure\.com:v3/([^/]+)/([^/]+)/(.+)`)
-		matches := re.FindStringSubmatch(sshURL)
-		if len(matches) == 4 {
+	host = resolveSSHHost(host)
+
+	// Convert git@ssh.dev.azure.com:v3/org/project/repo to https://dev.azure.com/org/project/_git/repo
+	// and org@vs-ssh.visualstudio.com:v3/org/project/repo to https://org.visualstudio.com/project/_git/repo
+	if matches := azureSSHPathRegexp.FindStringSubmatch(path); len(matches) == 4 {
+		switch {
+		case host == "ssh.dev.azure.com":
 			return "https://dev.azure.com/" + matches[1] + "/" + matches[2] + "/_git/" + matches[3]
+		case host == "vs-ssh.visualstudio.com":
+			return "https://" + matches[1] + ".visualstudio.com/" + matches[2] + "/_git/" + matches[3]
 		}
 	}
-	
-	return sshURL
+
+	return "https://" + host + "/" + path
+}
+
+// hostProviders maps the hostnames of self-hosted forges to their provider type
+var (
+	hostProvidersMu sync.RWMutex
+	hostProviders   = map[string]ProviderType{}
+)
+
+// RegisterHost marks a self-hosted hostname as served by the given provider,
+// so remotes pointing to it are detected correctly
+func RegisterHost(host string, providerType ProviderType) {
+	if host == "" {
+		return
+	}
+	hostProvidersMu.Lock()
+	defer hostProvidersMu.Unlock()
+	hostProviders[strings.ToLower(host)] = providerType
+}
+
+// UnregisterHost forgets the provider RegisterHost set for a hostname
+func UnregisterHost(host string) {
+	hostProvidersMu.Lock()
+	defer hostProvidersMu.Unlock()
+	delete(hostProviders, strings.ToLower(host))
+}
+
+func lookupHostProvider(host string) (ProviderType, bool) {
+	hostProvidersMu.RLock()
+	defer hostProvidersMu.RUnlock()
+	providerType, ok := hostProviders[strings.ToLower(host)]
+	return providerType, ok
+}
+
+// hostFromURL returns the hostname of an http(s) URL, or "" when there is none
+func hostFromURL(rawURL string) string {
+	matches := httpHostRegexp.FindStringSubmatch(rawURL)
+	if len(matches) != 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+func parseGitLabURL(url string) *RemoteInfo {
+	// GitLab patterns:
+	// https://gitlab.com/{group}/{subgroup}/{repository}
+	// https://{self-hosted}/{group}/{repository}
+
+	host := hostFromURL(url)
+	if !isGitLabHost(host) {
+		return nil
+	}
+
+	matches := gitLabURLRegexp.FindStringSubmatch(url)
+	if len(matches) != 3 {
+		return nil
+	}
+
+	return &RemoteInfo{
+		Provider:     GitLab,
+		Organization: matches[1],
+		Project:      "",
+		Repository:   matches[2],
+		BaseURL:      "https://" + host,
+	}
+}
+
+func parseGiteaURL(url string) *RemoteInfo {
+	// Gitea and Forgejo patterns:
+	// https://{host}/{owner}/{repository}
+	// https://{host}/{subpath}/{owner}/{repository} when served below a subpath
+
+	host := hostFromURL(url)
+	providerType, ok := giteaProviderForHost(host)
+	if !ok {
+		return nil
+	}
+
+	matches := giteaURLRegexp.FindStringSubmatch(url)
+	if len(matches) != 4 {
+		return nil
+	}
+
+	return &RemoteInfo{
+		Provider:     providerType,
+		Organization: matches[2],
+		Project:      "",
+		Repository:   matches[3],
+		BaseURL:      urlUserinfoRegexp.ReplaceAllString(matches[1], "$1"),
+	}
+}
+
+func parseBitbucketURL(url string) *RemoteInfo {
+	// Bitbucket patterns:
+	// https://{user}@bitbucket.org/{workspace}/{repository}.git
+	// https://{server}/scm/{project}/{repository}.git
+	// https://{server}/{context}/scm/{project}/{repository}.git
+
+	providerType, ok := bitbucketProviderForHost(hostFromURL(url))
+	if !ok {
+		return nil
+	}
+
+	matches := bitbucketURLRegexp.FindStringSubmatch(url)
+	if len(matches) != 5 {
+		return nil
+	}
+
+	baseURL := "https://" + matches[1]
+	if providerType == BitbucketServer {
+		context := strings.TrimSuffix(strings.TrimSuffix(matches[2], "/"), "scm")
+		baseURL += "/" + strings.Trim(context, "/")
+		baseURL = strings.TrimSuffix(baseURL, "/")
+	} else if matches[2] != "" {
+		return nil
+	}
+
+	return &RemoteInfo{
+		Provider:     providerType,
+		Organization: matches[3],
+		Project:      "",
+		Repository:   matches[4],
+		BaseURL:      baseURL,
+	}
 }
 
 func parseAzureDevOpsURL(url string) *RemoteInfo {
@@ -62,14 +231,7 @@ func parseAzureDevOpsURL(url string) *RemoteInfo {
 	// https://{organization}.visualstudio.com/{project}/_git/{repository}
 	// https://{server}/{tfs}/{collection}/{project}/_git/{repository}
 	
-	patterns := []string{
-		`https://dev\.azure\.com/([^/]+)/([^/]+)/_git/(.+?)(?:\.git)?/?$`,
-		`https://([^.]+)\.visualstudio\.com/([^/]+)/_git/(.+?)(?:\.git)?/?$`,
-		`https://([^/]+)/tfs/([^/]+)/([^/]+)/_git/(.+?)(?:\.git)?/?$`,
-	}
-	
-	for i, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
+	for i, re := range azureDevOpsURLRegexps {
 		matches := re.FindStringSubmatch(url)
 		
 		switch i {
@@ -105,6 +267,20 @@ func parseAzureDevOpsURL(url string) *RemoteInfo {
 			}
 		}
 	}
+
+	// Azure DevOps Server on a host registered through hosts or a baseUrl:
+	// https://{server}/{collection}/{project}/_git/{repository}
+	if providerType, ok := lookupHostProvider(hostFromURL(url)); ok && providerType == AzureDevOps {
+		if matches := azureDevOpsServerURLRegexp.FindStringSubmatch(url); len(matches) == 5 {
+			return &RemoteInfo{
+				Provider:     AzureDevOps,
+				Organization: matches[2], // collection
+				Project:      matches[3],
+				Repository:   matches[4],
+				BaseURL:      "https://" + matches[1],
+			}
+		}
+	}
 	
 	return nil
 }
@@ -113,17 +289,17 @@ func parseGitHubURL(url string) *RemoteInfo {
 	// GitHub patterns:
 	// https://github.com/{owner}/{repository}
 	// git@github.com:{owner}/{repository}.git
+	// https://{enterprise-server}/{owner}/{repository}
 	
-	re := regexp.MustCompile(`https://github\.com/([^/]+)/(.+?)(?:\.git)?/?$`)
-	matches := re.FindStringSubmatch(url)
+	matches := gitHubURLRegexp.FindStringSubmatch(url)
 	
-	if len(matches) == 3 {
+	if len(matches) == 4 {
 		return &RemoteInfo{
 			Provider:     GitHub,
-			Organization: matches[1],
+			Organization: matches[2],
 			Project:      "", // GitHub doesn't have a separate project concept
-			Repository:   matches[2],
-			BaseURL:      "https://github.com",
+			Repository:   matches[3],
+			BaseURL:      "https://" + matches[1],
 		}
 	}
 	
@@ -148,6 +324,7 @@ func GetProviderConfigFromRemote(remoteURL string) (*ProviderConfig, error) {
 		Type:         info.Provider,
 		Organization: info.Organization,
 		Project:      info.Project,
+		Repository:   info.Repository,
 		BaseURL:      info.BaseURL,
 	}
 	
@@ -0,0 +1,137 @@
+package providers_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+const testSSHConfig = `# personal and work accounts
+Host github.com-personal
+    HostName github.com
+    User git
+
+Host gh-work
+	HostName=ghe.corp.example.com
+
+Host *.internal !legacy.internal
+    HostName %h.corp.example.com
+
+Host *
+    ServerAliveInterval 60
+`
+
+func TestParseSSHRemoteURL(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".ssh"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".ssh", "config"), []byte(testSSHConfig), 0o600))
+	t.Setenv("HOME", home)
+
+	providers.RegisterHost("ado.corp.example.com", providers.AzureDevOps)
+	t.Cleanup(func() { providers.UnregisterHost("ado.corp.example.com") })
+
+	testCases := map[string]struct {
+		remote string
+		want   providers.RemoteInfo
+	}{
+		"scp-like without .git": {
+			remote: "git@github.com:dlvhdr/gh-dash",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "dlvhdr", Repository: "gh-dash",
+				BaseURL: "https://github.com",
+			},
+		},
+		"scp-like alias with HostName": {
+			remote: "git@github.com-personal:dlvhdr/gh-dash.git",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "dlvhdr", Repository: "gh-dash",
+				BaseURL: "https://github.com",
+			},
+		},
+		"scp-like alias without user": {
+			remote: "gh-work:team/repo",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "team", Repository: "repo",
+				BaseURL: "https://ghe.corp.example.com",
+			},
+		},
+		"ssh URL with port": {
+			remote: "ssh://git@ghe.corp:2222/team/repo.git",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "team", Repository: "repo",
+				BaseURL: "https://ghe.corp",
+			},
+		},
+		"git+ssh URL": {
+			remote: "git+ssh://git@gitlab.com/group/subgroup/repo.git",
+			want: providers.RemoteInfo{
+				Provider: providers.GitLab, Organization: "group/subgroup", Repository: "repo",
+				BaseURL: "https://gitlab.com",
+			},
+		},
+		"wildcard alias with %h": {
+			remote: "git@code.internal:team/repo.git",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "team", Repository: "repo",
+				BaseURL: "https://code.internal.corp.example.com",
+			},
+		},
+		"negated wildcard alias": {
+			remote: "git@legacy.internal:team/repo.git",
+			want: providers.RemoteInfo{
+				Provider: providers.GitHub, Organization: "team", Repository: "repo",
+				BaseURL: "https://legacy.internal",
+			},
+		},
+		"azure devops ssh": {
+			remote: "git@ssh.dev.azure.com:v3/myorg/myproject/myrepo",
+			want: providers.RemoteInfo{
+				Provider: providers.AzureDevOps, Organization: "myorg", Project: "myproject", Repository: "myrepo",
+				BaseURL: "https://dev.azure.com",
+			},
+		},
+		"azure devops ssh URL": {
+			remote: "ssh://git@ssh.dev.azure.com/v3/myorg/myproject/myrepo",
+			want: providers.RemoteInfo{
+				Provider: providers.AzureDevOps, Organization: "myorg", Project: "myproject", Repository: "myrepo",
+				BaseURL: "https://dev.azure.com",
+			},
+		},
+		"visualstudio.com ssh": {
+			remote: "myorg@vs-ssh.visualstudio.com:v3/myorg/myproject/myrepo",
+			want: providers.RemoteInfo{
+				Provider: providers.AzureDevOps, Organization: "myorg", Project: "myproject", Repository: "myrepo",
+				BaseURL: "https://myorg.visualstudio.com",
+			},
+		},
+		"registered azure devops server": {
+			remote: "ssh://ado.corp.example.com:22/DefaultCollection/myproject/_git/myrepo",
+			want: providers.RemoteInfo{
+				Provider: providers.AzureDevOps, Organization: "DefaultCollection", Project: "myproject", Repository: "myrepo",
+				BaseURL: "https://ado.corp.example.com",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providers.ParseGitRemoteURL(tc.remote)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *got)
+			require.Equal(t, tc.want.Provider, providers.DetectProviderFromURL(tc.remote))
+		})
+	}
+}
+
+func TestUnregisterHost(t *testing.T) {
+	remote := "https://forge.example.com/team/repo.git"
+	providers.RegisterHost("Forge.example.com", providers.Gitea)
+	require.Equal(t, providers.Gitea, providers.DetectProviderFromURL(remote))
+
+	providers.UnregisterHost("forge.example.com")
+	require.Equal(t, providers.GitHub, providers.DetectProviderFromURL(remote))
+}
@@ -0,0 +1,114 @@
+package providers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// fakeServer is a fake API of the service of a provider. Its handlers run on
+// the goroutines of the server, where require can't stop the test, so they check
+// requests with assert. The server records the requests it receives for checks
+// on the test goroutine
+type fakeServer struct {
+	*httptest.Server
+	t   *testing.T
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	received []receivedRequest
+	failing  bool
+}
+
+// receivedRequest is a request the fake server received
+type receivedRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// newFakeServer starts a fake server, which is closed when the test ends
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	s := &fakeServer{t: t, mux: http.NewServeMux()}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(s.t, err)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.received = append(s.received, receivedRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+	failing := s.failing
+	s.mu.Unlock()
+
+	if failing {
+		http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle serves the requests matching the pattern with the handler
+func (s *fakeServer) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// fail makes the server answer the following requests with an error
+func (s *fakeServer) fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = true
+}
+
+// requests returns the received requests with the method, or any method when
+// it is empty, whose path starts with the prefix, in the order they were received
+func (s *fakeServer) requests(method string, pathPrefix string) []receivedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []receivedRequest
+	for _, req := range s.received {
+		if (method == "" || req.Method == method) && strings.HasPrefix(req.Path, pathPrefix) {
+			res = append(res, req)
+		}
+	}
+	return res
+}
+
+// writeJSON answers a request with v encoded as JSON
+func (s *fakeServer) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(s.t, json.NewEncoder(w).Encode(v))
+}
+
+// readJSON decodes the JSON body of a request into v, reporting whether it could
+func (s *fakeServer) readJSON(r *http.Request, v interface{}) bool {
+	return assert.NoError(s.t, json.NewDecoder(r.Body).Decode(v))
+}
+
+// newProvider creates a provider talking to the server, with the "secret"
+// token unless the config has one
+func (s *fakeServer) newProvider(config providers.ProviderConfig) providers.GitProvider {
+	s.t.Helper()
+	config.BaseURL = s.URL
+	if config.Token == "" {
+		config.Token = "secret"
+	}
+	provider, err := providers.NewProvider(config)
+	require.NoError(s.t, err)
+	return provider
+}
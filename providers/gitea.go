@@ -0,0 +1,865 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// GiteaProvider talks to Gitea and its Forgejo fork, which share the same REST API
+type GiteaProvider struct {
+	*RateLimitTracker
+
+	client       *http.Client
+	config       ProviderConfig
+	providerType ProviderType
+	baseURL      string
+	token        string
+
+	// usernameMu guards username, which the fetches of every section fill
+	usernameMu sync.Mutex
+	username   string
+}
+
+type GiteaUser struct {
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+type GiteaLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type GiteaRepository struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Archived bool   `json:"archived"`
+}
+
+type GiteaIssue struct {
+	Number      int              `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	State       string           `json:"state"`
+	User        GiteaUser        `json:"user"`
+	Assignees   []GiteaUser      `json:"assignees"`
+	Labels      []GiteaLabel     `json:"labels"`
+	Comments    int              `json:"comments"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	HTMLURL     string           `json:"html_url"`
+	Repository  *GiteaRepository `json:"repository"`
+	PullRequest *struct {
+		Merged bool `json:"merged"`
+		Draft  bool `json:"draft"`
+	} `json:"pull_request"`
+}
+
+type GiteaBranch struct {
+	Ref  string           `json:"ref"`
+	Sha  string           `json:"sha"`
+	Repo *GiteaRepository `json:"repo"`
+}
+
+type GiteaPullRequest struct {
+	Number             int          `json:"number"`
+	Title              string       `json:"title"`
+	Body               string       `json:"body"`
+	State              string       `json:"state"`
+	Draft              bool         `json:"draft"`
+	User               GiteaUser    `json:"user"`
+	Assignees          []GiteaUser  `json:"assignees"`
+	RequestedReviewers []GiteaUser  `json:"requested_reviewers"`
+	Labels             []GiteaLabel `json:"labels"`
+	Comments           int          `json:"comments"`
+	Additions          int          `json:"additions"`
+	Deletions          int          `json:"deletions"`
+	Mergeable          bool         `json:"mergeable"`
+	Merged             bool         `json:"merged"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+	HTMLURL            string       `json:"html_url"`
+	Head               GiteaBranch  `json:"head"`
+	Base               GiteaBranch  `json:"base"`
+}
+
+type GiteaReview struct {
+	User        GiteaUser `json:"user"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	Stale       bool      `json:"stale"`
+	Dismissed   bool      `json:"dismissed"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type GiteaCommitStatus struct {
+	Context     string    `json:"context"`
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	TargetURL   string    `json:"target_url"`
+	Creator     GiteaUser `json:"creator"`
+}
+
+type GiteaCombinedStatus struct {
+	State    string              `json:"state"`
+	Statuses []GiteaCommitStatus `json:"statuses"`
+}
+
+type GiteaChangedFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+type GiteaComment struct {
+	User      GiteaUser `json:"user"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewGiteaProvider(config ProviderConfig) (GitProvider, error) {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("%s base URL is required. Set provider.baseUrl in the config", config.Type)
+	}
+
+	providerType := config.Type
+	if providerType != Forgejo {
+		providerType = Gitea
+	}
+
+	log.Debug("Creating Gitea provider", "type", providerType, "baseURL", baseURL, "hasToken", config.Token != "")
+
+	rateLimits := &RateLimitTracker{}
+	return &GiteaProvider{
+		RateLimitTracker: rateLimits,
+		client:           newRateLimitedClient(rateLimits),
+		config:           config,
+		providerType:     providerType,
+		baseURL:          baseURL,
+		token:            config.Token,
+	}, nil
+}
+
+func (p *GiteaProvider) GetType() ProviderType {
+	return p.providerType
+}
+
+func (p *GiteaProvider) SupportsPullRequests() bool {
+	return true
+}
+
+func (p *GiteaProvider) SupportsIssues() bool {
+	return true
+}
+
+// Capabilities are the actions of tea, the others open the pull request in the browser
+func (p *GiteaProvider) Capabilities() Capabilities {
+	return Capabilities{
+		MergeStrategies: []string{"merge"},
+		Close:           true,
+		IssueClose:      true,
+		Labels:          true,
+		Checkout:        true,
+	}
+}
+
+func (p *GiteaProvider) GetAuthInfo() (AuthInfo, error) {
+	ctx, cancel := actionContext()
+	defer cancel()
+	username, err := p.currentUsername(ctx)
+	if err != nil {
+		return AuthInfo{IsLoggedIn: false, TokenSource: "Access Token"}, err
+	}
+	return AuthInfo{
+		Username:    username,
+		IsLoggedIn:  true,
+		TokenSource: "Access Token",
+	}, nil
+}
+
+func (p *GiteaProvider) currentUsername(ctx context.Context) (string, error) {
+	p.usernameMu.Lock()
+	defer p.usernameMu.Unlock()
+	if p.username != "" {
+		return p.username, nil
+	}
+	var user GiteaUser
+	if _, err := p.get(ctx, "/user", nil, &user); err != nil {
+		return "", err
+	}
+	p.username = user.Login
+	return p.username, nil
+}
+
+func (p *GiteaProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	if p.token == "" {
+		return PullRequestsResponse{}, fmt.Errorf("Gitea access token is required. Set GITEA_TOKEN or FORGEJO_TOKEN environment variable")
+	}
+
+	list, err := p.buildListParams(ctx, "pulls", query, limit, pageInfo)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+
+	log.Debug("Fetching Gitea pull requests", "path", list.path, "params", list.params.Encode())
+	var giteaIssues []GiteaIssue
+	header, err := p.get(ctx, list.path, list.params, &giteaIssues)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+	log.Debug("Successfully fetched Gitea pull requests", "count", len(giteaIssues))
+
+	if list.mergedOnly {
+		merged := giteaIssues[:0]
+		for _, issue := range giteaIssues {
+			if issue.PullRequest != nil && issue.PullRequest.Merged {
+				merged = append(merged, issue)
+			}
+		}
+		giteaIssues = merged
+	}
+
+	// The search endpoints only return the issue side of a pull request,
+	// so fetch the pull request itself for branches, reviewers and checks
+	prs := make([]PullRequestData, len(giteaIssues))
+	forEachConcurrently(len(giteaIssues), func(i int) {
+		issue := giteaIssues[i]
+		repo := list.repo
+		if issue.Repository != nil {
+			repo = issue.Repository.FullName
+		}
+		pr, err := p.fetchPullRequest(ctx, repo, issue.Number, false)
+		if err != nil {
+			log.Debug("Failed fetching Gitea pull request details", "url", issue.HTMLURL, "err", err)
+			pr = p.convertIssueToPullRequestData(issue, repo)
+		}
+		prs[i] = pr
+	})
+
+	pageInfoRes := giteaPageInfo(header, list.params.Get("page"))
+	totalCount := giteaTotalCount(header, len(prs))
+	warnings := unsupportedFiltersWarning("Gitea", list.unsupported)
+	if list.mergedOnly {
+		// Gitea has no merged state, the merged pull requests are picked out of
+		// the closed ones, so pages can be short and only a single page is counted
+		totalCount = UnknownTotalCount
+		if !pageInfoRes.HasNextPage && pageInfoRes.StartCursor == "1" {
+			totalCount = len(prs)
+		}
+		warnings = append(warnings, "Gitea can't search merged pull requests, they are filtered out of the closed ones page by page")
+	}
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: totalCount,
+		PageInfo:   pageInfoRes,
+		Warnings:   warnings,
+	}, nil
+}
+
+func (p *GiteaProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	if p.token == "" {
+		return IssuesResponse{}, fmt.Errorf("Gitea access token is required. Set GITEA_TOKEN or FORGEJO_TOKEN environment variable")
+	}
+
+	list, err := p.buildListParams(ctx, "issues", query, limit, pageInfo)
+	if err != nil {
+		return IssuesResponse{}, err
+	}
+
+	log.Debug("Fetching Gitea issues", "path", list.path, "params", list.params.Encode())
+	var giteaIssues []GiteaIssue
+	header, err := p.get(ctx, list.path, list.params, &giteaIssues)
+	if err != nil {
+		return IssuesResponse{}, err
+	}
+	log.Debug("Successfully fetched Gitea issues", "count", len(giteaIssues))
+
+	issues := make([]IssueData, 0, len(giteaIssues))
+	for _, giteaIssue := range giteaIssues {
+		repo := list.repo
+		if giteaIssue.Repository != nil {
+			repo = giteaIssue.Repository.FullName
+		}
+		issues = append(issues, p.convertIssueToData(giteaIssue, repo))
+	}
+
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: giteaTotalCount(header, len(issues)),
+		PageInfo:   giteaPageInfo(header, list.params.Get("page")),
+		Warnings:   unsupportedFiltersWarning("Gitea", list.unsupported),
+	}, nil
+}
+
+var giteaPullRequestURLRegexp = regexp.MustCompile(`^https?://.+?/([^/]+/[^/]+)/pulls/(\d+)`)
+
+func (p *GiteaProvider) FetchPullRequest(ctx context.Context, prUrl string) (PullRequestData, error) {
+	matches := giteaPullRequestURLRegexp.FindStringSubmatch(prUrl)
+	if len(matches) != 3 {
+		return PullRequestData{}, fmt.Errorf("invalid Gitea pull request URL: %s", prUrl)
+	}
+	number, _ := strconv.Atoi(matches[2])
+
+	log.Debug("Fetching Gitea pull request", "url", prUrl)
+	pr, err := p.fetchPullRequest(ctx, matches[1], number, true)
+	if err != nil {
+		return PullRequestData{}, err
+	}
+	log.Debug("Successfully fetched Gitea pull request", "url", prUrl)
+
+	return pr, nil
+}
+
+// fetchPullRequest fetches a pull request with its reviews and commit statuses.
+// withDetails also fetches the changed files and comments shown in the sidebar
+func (p *GiteaProvider) fetchPullRequest(ctx context.Context, repo string, number int, withDetails bool) (PullRequestData, error) {
+	prPath := fmt.Sprintf("/repos/%s/pulls/%d", repo, number)
+	var giteaPR GiteaPullRequest
+	if _, err := p.get(ctx, prPath, nil, &giteaPR); err != nil {
+		return PullRequestData{}, err
+	}
+
+	var reviews []GiteaReview
+	if _, err := p.get(ctx, prPath+"/reviews", nil, &reviews); err != nil {
+		log.Debug("Failed fetching Gitea reviews", "pr", giteaPR.HTMLURL, "err", err)
+	}
+
+	var status GiteaCombinedStatus
+	if giteaPR.Head.Sha != "" {
+		statusPath := fmt.Sprintf("/repos/%s/commits/%s/status", repo, giteaPR.Head.Sha)
+		if _, err := p.get(ctx, statusPath, nil, &status); err != nil {
+			log.Debug("Failed fetching Gitea commit status", "pr", giteaPR.HTMLURL, "err", err)
+		}
+	}
+
+	pr := p.convertPullRequestToData(giteaPR, repo, reviews, status)
+	if !withDetails {
+		return pr, nil
+	}
+
+	var files []GiteaChangedFile
+	if _, err := p.get(ctx, prPath+"/files", url.Values{"limit": {"100"}}, &files); err != nil {
+		log.Debug("Failed fetching Gitea changed files", "pr", giteaPR.HTMLURL, "err", err)
+	}
+	pr.Files = ChangedFiles{TotalCount: len(files)}
+	for _, file := range files {
+		pr.Files.Nodes = append(pr.Files.Nodes, ChangedFile{
+			Path:       file.Filename,
+			Additions:  file.Additions,
+			Deletions:  file.Deletions,
+			ChangeType: giteaChangeType(file.Status),
+		})
+	}
+
+	var comments []GiteaComment
+	commentsPath := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	if _, err := p.get(ctx, commentsPath, nil, &comments); err != nil {
+		log.Debug("Failed fetching Gitea comments", "pr", giteaPR.HTMLURL, "err", err)
+	}
+	pr.Comments.Nodes = make([]Comment, 0, len(comments))
+	for _, giteaComment := range comments {
+		var comment Comment
+		comment.Author.Login = giteaComment.User.Login
+		comment.Body = giteaComment.Body
+		comment.UpdatedAt = giteaComment.UpdatedAt
+		pr.Comments.Nodes = append(pr.Comments.Nodes, comment)
+	}
+
+	return pr, nil
+}
+
+type giteaListRequest struct {
+	path       string
+	params     url.Values
+	repo       string
+	mergedOnly bool
+	// unsupported lists the qualifiers that couldn't be translated, as written by the user
+	unsupported []string
+}
+
+// buildListParams translates a section filter into a Gitea list endpoint and its query parameters.
+// Without a repo: qualifier the cross repository search endpoint is used, which can only
+// filter on the authenticated user, so author/assignee/review-requested must be @me there
+func (p *GiteaProvider) buildListParams(ctx context.Context, issueType string, query string, limit int, pageInfo *PageInfo) (giteaListRequest, error) {
+	params := url.Values{}
+	params.Set("type", issueType)
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("state", "all")
+	page := "1"
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		page = pageInfo.EndCursor
+	}
+	params.Set("page", page)
+
+	parsed := ParseSearchQuery(query)
+	list := giteaListRequest{path: "/repos/issues/search", params: params}
+	if repo, ok := parsed.Get("repo"); ok {
+		list.repo = repo
+		list.path = fmt.Sprintf("/repos/%s/issues", repo)
+	}
+
+	var labels []string
+	for _, filter := range parsed.Filters {
+		value := filter.Value
+		isMe := value == "@me"
+		if isMe && list.repo != "" {
+			username, err := p.currentUsername(ctx)
+			if err != nil {
+				return giteaListRequest{}, err
+			}
+			value = username
+		}
+
+		switch {
+		case filter.Negated:
+			list.unsupported = append(list.unsupported, formatSearchFilter(filter))
+		case filter.Key == "is" || filter.Key == "state":
+			switch strings.ToLower(value) {
+			case "open":
+				params.Set("state", "open")
+			case "closed":
+				params.Set("state", "closed")
+			case "merged":
+				params.Set("state", "closed")
+				list.mergedOnly = true
+			}
+		case filter.Key == "author" && list.repo != "":
+			params.Set("created_by", value)
+		case filter.Key == "assignee" && list.repo != "":
+			params.Set("assigned_by", value)
+		case filter.Key == "mentions" && list.repo != "":
+			params.Set("mentioned_by", value)
+		case filter.Key == "author" && isMe && list.repo == "":
+			params.Set("created", "true")
+		case filter.Key == "assignee" && isMe && list.repo == "":
+			params.Set("assigned", "true")
+		case filter.Key == "mentions" && isMe && list.repo == "":
+			params.Set("mentioned", "true")
+		case filter.Key == "review-requested" && isMe && list.repo == "":
+			params.Set("review_requested", "true")
+		case (filter.Key == "org" || filter.Key == "user" || filter.Key == "owner") && list.repo == "":
+			params.Set("owner", value)
+		case filter.Key == "label":
+			labels = append(labels, value)
+		case filter.Key == "repo":
+			// Already handled by picking the repository endpoint
+		default:
+			list.unsupported = append(list.unsupported, formatSearchFilter(filter))
+		}
+	}
+	if len(labels) > 0 {
+		params.Set("labels", strings.Join(labels, ","))
+	}
+	if text := parsed.Text(); text != "" {
+		params.Set("q", text)
+	}
+	if len(list.unsupported) > 0 {
+		log.Debug("Unsupported Gitea filters", "filters", list.unsupported)
+	}
+
+	return list, nil
+}
+
+func (p *GiteaProvider) get(ctx context.Context, apiPath string, params url.Values, result interface{}) (http.Header, error) {
+	return p.do(ctx, "GET", apiPath, params, nil, result)
+}
+
+// do sends a request to the API, with the payload encoded as JSON when there
+// is one, and decodes the response into result when it isn't nil
+func (p *GiteaProvider) do(ctx context.Context, method string, apiPath string, params url.Values, payload interface{}, result interface{}) (http.Header, error) {
+	apiURL := fmt.Sprintf("%s/api/v1%s", p.baseURL, apiPath)
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "token "+p.token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Gitea API error: %s", string(body))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Header, nil
+}
+
+func giteaTotalCount(header http.Header, fallback int) int {
+	if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+		return total
+	}
+	return fallback
+}
+
+var giteaNextPageRegexp = regexp.MustCompile(`<[^>]*[?&]page=(\d+)[^>]*>;\s*rel="next"`)
+
+func giteaPageInfo(header http.Header, page string) PageInfo {
+	var nextPage string
+	if matches := giteaNextPageRegexp.FindStringSubmatch(header.Get("Link")); len(matches) == 2 {
+		nextPage = matches[1]
+	}
+	return PageInfo{
+		HasNextPage: nextPage != "",
+		StartCursor: page,
+		EndCursor:   nextPage,
+	}
+}
+
+func giteaRepository(repo *GiteaRepository, fullName string) Repository {
+	if repo != nil && repo.FullName != "" {
+		return Repository{Name: repo.Name, NameWithOwner: repo.FullName, IsArchived: repo.Archived}
+	}
+	name := fullName
+	if idx := strings.LastIndex(fullName, "/"); idx >= 0 {
+		name = fullName[idx+1:]
+	}
+	return Repository{Name: name, NameWithOwner: fullName}
+}
+
+// giteaIsDraft reports whether a pull request is a draft, which older Gitea
+// versions only express through a work in progress title prefix
+func giteaIsDraft(draft bool, title string) bool {
+	if draft {
+		return true
+	}
+	upper := strings.ToUpper(title)
+	return strings.HasPrefix(upper, "WIP:") || strings.HasPrefix(upper, "[WIP]")
+}
+
+func (p *GiteaProvider) convertPullRequestToData(giteaPR GiteaPullRequest, repo string, reviews []GiteaReview, status GiteaCombinedStatus) PullRequestData {
+	state := "OPEN"
+	if giteaPR.Merged {
+		state = "MERGED"
+	} else if giteaPR.State == "closed" {
+		state = "CLOSED"
+	}
+
+	// Gitea only checks open pull requests for conflicts, closed ones are never mergeable
+	mergeable := "UNKNOWN"
+	mergeStateStatus := "UNKNOWN"
+	if state == "OPEN" {
+		mergeable, mergeStateStatus = "MERGEABLE", "CLEAN"
+		if !giteaPR.Mergeable {
+			mergeable, mergeStateStatus = "CONFLICTING", "DIRTY"
+		}
+	}
+
+	pr := PullRequestData{
+		Number:            giteaPR.Number,
+		Title:             giteaPR.Title,
+		Body:              giteaPR.Body,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         giteaPR.UpdatedAt,
+		CreatedAt:         giteaPR.CreatedAt,
+		Url:               giteaPR.HTMLURL,
+		State:             state,
+		Mergeable:         mergeable,
+		MergeStateStatus:  mergeStateStatus,
+		Additions:         giteaPR.Additions,
+		Deletions:         giteaPR.Deletions,
+		HeadRefName:       giteaPR.Head.Ref,
+		BaseRefName:       giteaPR.Base.Ref,
+		Repository:        giteaRepository(giteaPR.Base.Repo, repo),
+		Assignees:         convertGiteaAssignees(giteaPR.Assignees),
+		Comments:          Comments{TotalCount: giteaPR.Comments},
+		IsDraft:           giteaIsDraft(giteaPR.Draft, giteaPR.Title),
+		Labels:            PRLabels{Nodes: convertGiteaLabels(giteaPR.Labels)},
+	}
+	pr.Author.Login = giteaPR.User.Login
+	pr.HeadRef.Name = giteaPR.Head.Ref
+	pr.HeadRepository.Name = pr.Repository.Name
+	if giteaPR.Head.Repo != nil {
+		pr.HeadRepository.Name = giteaPR.Head.Repo.Name
+	}
+
+	pr.ReviewRequests.TotalCount = len(giteaPR.RequestedReviewers)
+	for range giteaPR.RequestedReviewers {
+		pr.ReviewRequests.Nodes = append(pr.ReviewRequests.Nodes, ReviewRequest{})
+	}
+
+	for _, giteaReview := range reviews {
+		state := giteaReviewState(giteaReview.State)
+		if state == "" || giteaReview.Dismissed {
+			continue
+		}
+		var review Review
+		review.Author.Login = giteaReview.User.Login
+		review.Body = giteaReview.Body
+		review.State = state
+		review.UpdatedAt = giteaReview.SubmittedAt
+		pr.Reviews.Nodes = append(pr.Reviews.Nodes, review)
+	}
+	pr.Reviews.TotalCount = len(pr.Reviews.Nodes)
+	pr.ReviewDecision = giteaReviewDecision(pr.Reviews.Nodes, len(giteaPR.RequestedReviewers))
+
+	if len(status.Statuses) > 0 {
+		var node CommitNode
+		node.Commit.StatusCheckRollup.Contexts.TotalCount = len(status.Statuses)
+		for _, commitStatus := range status.Statuses {
+			context := CheckContext{Typename: "StatusContext"}
+			context.StatusContext.Context = commitStatus.Context
+			context.StatusContext.State = giteaStatusState(commitStatus.Status)
+			context.StatusContext.Creator.Login = commitStatus.Creator.Login
+			node.Commit.StatusCheckRollup.Contexts.Nodes = append(node.Commit.StatusCheckRollup.Contexts.Nodes, context)
+		}
+		pr.Commits = Commits{Nodes: []CommitNode{node}, TotalCount: 1}
+	}
+
+	return pr
+}
+
+// convertIssueToPullRequestData is used when the pull request itself couldn't be
+// fetched, so the row still shows what the search endpoint returned
+func (p *GiteaProvider) convertIssueToPullRequestData(issue GiteaIssue, repo string) PullRequestData {
+	state := "OPEN"
+	draft := false
+	if issue.PullRequest != nil {
+		draft = issue.PullRequest.Draft
+		if issue.PullRequest.Merged {
+			state = "MERGED"
+		}
+	}
+	if state != "MERGED" && issue.State == "closed" {
+		state = "CLOSED"
+	}
+
+	pr := PullRequestData{
+		Number:            issue.Number,
+		Title:             issue.Title,
+		Body:              issue.Body,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         issue.UpdatedAt,
+		CreatedAt:         issue.CreatedAt,
+		Url:               issue.HTMLURL,
+		State:             state,
+		MergeStateStatus:  "UNKNOWN",
+		Repository:        giteaRepository(issue.Repository, repo),
+		Assignees:         convertGiteaAssignees(issue.Assignees),
+		Comments:          Comments{TotalCount: issue.Comments},
+		IsDraft:           giteaIsDraft(draft, issue.Title),
+		Labels:            PRLabels{Nodes: convertGiteaLabels(issue.Labels)},
+	}
+	pr.Author.Login = issue.User.Login
+	pr.HeadRepository.Name = pr.Repository.Name
+	return pr
+}
+
+func (p *GiteaProvider) convertIssueToData(issue GiteaIssue, repo string) IssueData {
+	state := "OPEN"
+	if issue.State == "closed" {
+		state = "CLOSED"
+	}
+
+	data := IssueData{
+		Number:            issue.Number,
+		Title:             issue.Title,
+		Body:              issue.Body,
+		State:             state,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         issue.UpdatedAt,
+		CreatedAt:         issue.CreatedAt,
+		Url:               issue.HTMLURL,
+		Repository:        giteaRepository(issue.Repository, repo),
+		Assignees:         convertGiteaAssignees(issue.Assignees),
+		Comments:          IssueComments{TotalCount: issue.Comments},
+		Labels:            IssueLabels{Nodes: convertGiteaLabels(issue.Labels)},
+	}
+	data.Author.Login = issue.User.Login
+
+	return data
+}
+
+func convertGiteaAssignees(users []GiteaUser) Assignees {
+	assignees := Assignees{TotalCount: len(users)}
+	for _, user := range users {
+		assignees.Nodes = append(assignees.Nodes, Assignee{Login: user.Login})
+	}
+	return assignees
+}
+
+func convertGiteaLabels(giteaLabels []GiteaLabel) []Label {
+	var labels []Label
+	for _, label := range giteaLabels {
+		labels = append(labels, Label{Name: label.Name, Color: strings.TrimPrefix(label.Color, "#")})
+	}
+	return labels
+}
+
+func giteaChangeType(status string) string {
+	switch status {
+	case "added":
+		return "ADDED"
+	case "deleted":
+		return "DELETED"
+	case "renamed":
+		return "RENAMED"
+	case "copied":
+		return "COPIED"
+	default:
+		return "MODIFIED"
+	}
+}
+
+func giteaReviewState(state string) string {
+	switch state {
+	case "APPROVED":
+		return "APPROVED"
+	case "REQUEST_CHANGES":
+		return "CHANGES_REQUESTED"
+	case "COMMENT":
+		return "COMMENTED"
+	case "PENDING":
+		return "PENDING"
+	default:
+		// REQUEST_REVIEW entries are review requests, not reviews
+		return ""
+	}
+}
+
+// giteaReviewDecision derives GitHub's review decision from each reviewer's latest review
+func giteaReviewDecision(reviews []Review, requestedReviewers int) string {
+	latest := map[string]string{}
+	for _, review := range reviews {
+		if review.State == "APPROVED" || review.State == "CHANGES_REQUESTED" {
+			latest[review.Author.Login] = review.State
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		if state == "CHANGES_REQUESTED" {
+			return "CHANGES_REQUESTED"
+		}
+		approved = true
+	}
+	if approved {
+		return "APPROVED"
+	}
+	if requestedReviewers > 0 {
+		return "REVIEW_REQUIRED"
+	}
+	return ""
+}
+
+// giteaStatusState maps a Gitea commit status onto GitHub's StatusState values
+func giteaStatusState(status string) string {
+	switch status {
+	case "success":
+		return "SUCCESS"
+	case "pending":
+		return "PENDING"
+	case "failure":
+		return "FAILURE"
+	default:
+		return "ERROR"
+	}
+}
+
+func (p *GiteaProvider) CloseIssue(number int, repoNameWithOwner string) error {
+	return p.updateIssueState(number, repoNameWithOwner, "closed")
+}
+
+func (p *GiteaProvider) ReopenIssue(number int, repoNameWithOwner string) error {
+	return p.updateIssueState(number, repoNameWithOwner, "open")
+}
+
+func (p *GiteaProvider) updateIssueState(number int, repoNameWithOwner string, state string) error {
+	log.Debug("Updating Gitea issue state", "repo", repoNameWithOwner, "number", number, "state", state)
+	apiPath := fmt.Sprintf("/repos/%s/issues/%d", repoNameWithOwner, number)
+	ctx, cancel := actionContext()
+	defer cancel()
+	_, err := p.do(ctx, "PATCH", apiPath, nil, map[string]string{"state": state}, nil)
+	return err
+}
+
+// teaCommand runs a tea pulls subcommand against the instance of the provider,
+// through the tea login named after its host, which is the name tea gives
+// logins by default
+func (p *GiteaProvider) teaCommand(subcommand string, prNumber int, repoNameWithOwner string) []string {
+	args := []string{"tea", "pulls", subcommand, fmt.Sprint(prNumber), "--repo", repoNameWithOwner}
+	if baseURL, err := url.Parse(p.baseURL); err == nil && baseURL.Host != "" {
+		args = append(args, "--login", baseURL.Host)
+	}
+	return args
+}
+
+// Command operations for Gitea pull requests. Close, reopen, merge and checkout use the
+// tea CLI, the rest open the web UI since tea has no equivalent
+func (p *GiteaProvider) GetDiffCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", fmt.Sprintf("%s/%s/pulls/%d/files", p.baseURL, repoNameWithOwner, prNumber)}, nil
+}
+
+func (p *GiteaProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.teaCommand("checkout", prNumber, repoNameWithOwner), nil
+}
+
+func (p *GiteaProvider) GetMergeCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.teaCommand("merge", prNumber, repoNameWithOwner), nil
+}
+
+func (p *GiteaProvider) GetCloseCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.teaCommand("close", prNumber, repoNameWithOwner), nil
+}
+
+func (p *GiteaProvider) GetReopenCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.teaCommand("reopen", prNumber, repoNameWithOwner), nil
+}
+
+func (p *GiteaProvider) GetReadyCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", fmt.Sprintf("%s/%s/pulls/%d", p.baseURL, repoNameWithOwner, prNumber)}, nil
+}
+
+func (p *GiteaProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", fmt.Sprintf("%s/%s/pulls/%d", p.baseURL, repoNameWithOwner, prNumber)}, nil
+}
+
+func (p *GiteaProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"open", fmt.Sprintf("%s/%s/pulls/%d/checks", p.baseURL, repoNameWithOwner, prNumber)}, nil
+}
@@ -0,0 +1,315 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func newGiteaServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+	repo := map[string]interface{}{"name": "tools", "full_name": "infra/tools"}
+
+	server.handle("/api/v1/user", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]string{"login": "me"})
+	})
+	server.handle("/api/v1/repos/issues/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		q := r.URL.Query()
+		switch q.Get("type") {
+		case "pulls":
+			assert.Equal(t, "open", q.Get("state"))
+			assert.Equal(t, "true", q.Get("review_requested"))
+			assert.Equal(t, "bug", q.Get("labels"))
+			w.Header().Set("X-Total-Count", "3")
+			w.Header().Set("Link", `<`+server.URL+`/api/v1/repos/issues/search?limit=1&page=2&type=pulls>; rel="next"`)
+			server.writeJSON(w, []map[string]interface{}{{
+				"number":       5,
+				"title":        "Add retries",
+				"state":        "open",
+				"user":         map[string]string{"login": "alice"},
+				"html_url":     server.URL + "/infra/tools/pulls/5",
+				"repository":   repo,
+				"pull_request": map[string]interface{}{"merged": false},
+			}})
+		case "issues":
+			assert.Equal(t, "true", q.Get("created"))
+			server.writeJSON(w, []map[string]interface{}{{
+				"number":     8,
+				"title":      "Retries are flaky",
+				"state":      "closed",
+				"user":       map[string]string{"login": "me"},
+				"labels":     []map[string]string{{"name": "flaky", "color": "00aabb"}},
+				"comments":   3,
+				"html_url":   server.URL + "/infra/tools/issues/8",
+				"repository": repo,
+			}})
+		}
+	})
+	server.handle("/api/v1/repos/infra/tools/pulls/5", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"number":              5,
+			"title":               "WIP: Add retries",
+			"state":               "open",
+			"user":                map[string]string{"login": "alice"},
+			"requested_reviewers": []map[string]string{{"login": "me"}},
+			"labels":              []map[string]string{{"name": "bug", "color": "ff0000"}},
+			"mergeable":           true,
+			"additions":           10,
+			"deletions":           2,
+			"html_url":            server.URL + "/infra/tools/pulls/5",
+			"head":                map[string]interface{}{"ref": "retries", "sha": "abc123", "repo": repo},
+			"base":                map[string]interface{}{"ref": "main", "sha": "def456", "repo": repo},
+		})
+	})
+	server.handle("/api/v1/repos/infra/tools/pulls/5/reviews", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, []map[string]interface{}{
+			{"user": map[string]string{"login": "bob"}, "state": "APPROVED"},
+			{"user": map[string]string{"login": "carol"}, "state": "REQUEST_CHANGES"},
+			{"user": map[string]string{"login": "me"}, "state": "REQUEST_REVIEW"},
+		})
+	})
+	server.handle("/api/v1/repos/infra/tools/commits/abc123/status", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"state": "failure",
+			"statuses": []map[string]interface{}{
+				{"context": "ci/build", "status": "success"},
+				{"context": "ci/test", "status": "failure"},
+			},
+		})
+	})
+	server.handle("/api/v1/repos/infra/tools/pulls/5/files", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, []map[string]interface{}{
+			{"filename": "retry.go", "status": "added", "additions": 10, "deletions": 0},
+			{"filename": "client.go", "status": "changed", "additions": 0, "deletions": 2},
+		})
+	})
+	server.handle("/api/v1/repos/infra/tools/issues/5/comments", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, []map[string]interface{}{{"user": map[string]string{"login": "bob"}, "body": "LGTM"}})
+	})
+
+	return server
+}
+
+func TestGiteaFetchPullRequests(t *testing.T) {
+	server := newGiteaServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Forgejo})
+	require.Equal(t, providers.Forgejo, provider.GetType())
+
+	res, err := provider.FetchPullRequests(context.Background(), "is:open review-requested:@me label:bug", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Equal(t, "2", res.PageInfo.EndCursor)
+	require.Len(t, res.Prs, 1)
+
+	pr := res.Prs[0]
+	require.Equal(t, 5, pr.Number)
+	require.Equal(t, "OPEN", pr.State)
+	require.True(t, pr.IsDraft)
+	require.Equal(t, "infra/tools", pr.Repository.NameWithOwner)
+	require.Equal(t, "retries", pr.HeadRefName)
+	require.Equal(t, "main", pr.BaseRefName)
+	require.Equal(t, "CHANGES_REQUESTED", pr.ReviewDecision)
+	require.Len(t, pr.Reviews.Nodes, 2)
+	require.Equal(t, 1, pr.ReviewRequests.TotalCount)
+	require.Equal(t, []providers.Label{{Name: "bug", Color: "ff0000"}}, pr.Labels.Nodes)
+
+	checks := pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes
+	require.Len(t, checks, 2)
+	require.Equal(t, "StatusContext", checks[1].Typename)
+	require.Equal(t, "ci/test", checks[1].StatusContext.Context)
+	require.Equal(t, "FAILURE", checks[1].StatusContext.State)
+}
+
+func TestGiteaFetchRepoPullRequests(t *testing.T) {
+	server := newGiteaServer(t)
+	server.handle("/api/v1/repos/infra/tools/issues", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "pulls", q.Get("type"))
+		assert.Equal(t, "closed", q.Get("state"))
+		assert.Equal(t, "me", q.Get("assigned_by"))
+		assert.False(t, q.Has("review_requested"))
+		assert.False(t, q.Has("assigned"))
+		w.Header().Set("X-Total-Count", "40")
+		w.Header().Set("Link", `<`+server.URL+`/api/v1/repos/infra/tools/issues?page=2>; rel="next"`)
+		server.writeJSON(w, []map[string]interface{}{
+			{"number": 5, "state": "closed", "pull_request": map[string]interface{}{"merged": true}},
+			{"number": 6, "state": "closed", "pull_request": map[string]interface{}{"merged": false}},
+		})
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Gitea})
+
+	res, err := provider.FetchPullRequests(context.Background(), "repo:infra/tools is:merged assignee:@me review-requested:@me", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Prs, 1)
+	require.Equal(t, 5, res.Prs[0].Number)
+	// The closed pull requests are counted, not the merged ones
+	require.Equal(t, providers.UnknownTotalCount, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Equal(t, []string{
+		"Gitea ignored unsupported filters: review-requested:@me",
+		"Gitea can't search merged pull requests, they are filtered out of the closed ones page by page",
+	}, res.Warnings)
+}
+
+func TestGiteaFetchIssues(t *testing.T) {
+	server := newGiteaServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Forgejo})
+
+	res, err := provider.FetchIssues(context.Background(), "author:@me -label:wontfix milestone:v1", 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Gitea ignored unsupported filters: -label:wontfix, milestone:v1"}, res.Warnings)
+	require.False(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Issues, 1)
+
+	issue := res.Issues[0]
+	require.Equal(t, 8, issue.Number)
+	require.Equal(t, "CLOSED", issue.State)
+	require.Equal(t, "infra/tools", issue.Repository.NameWithOwner)
+	require.Equal(t, 3, issue.Comments.TotalCount)
+	require.Equal(t, []providers.Label{{Name: "flaky", Color: "00aabb"}}, issue.Labels.Nodes)
+}
+
+func TestGiteaFetchPullRequest(t *testing.T) {
+	server := newGiteaServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Forgejo})
+
+	pr, err := provider.FetchPullRequest(context.Background(), server.URL+"/infra/tools/pulls/5")
+	require.NoError(t, err)
+	require.Equal(t, 2, pr.Files.TotalCount)
+	require.Equal(t, "ADDED", pr.Files.Nodes[0].ChangeType)
+	require.Equal(t, "MODIFIED", pr.Files.Nodes[1].ChangeType)
+	require.Len(t, pr.Comments.Nodes, 1)
+	require.Equal(t, "LGTM", pr.Comments.Nodes[0].Body)
+
+	cmd, err := provider.GetMergeCommand(5, "infra/tools")
+	require.NoError(t, err)
+	// tea acts through the login of the instance, which is named after its host
+	require.Equal(t, []string{"tea", "pulls", "merge", "5", "--repo", "infra/tools", "--login", strings.TrimPrefix(server.URL, "http://")}, cmd)
+}
+
+func TestGiteaMergeable(t *testing.T) {
+	testCases := map[string]struct {
+		state                string
+		merged               bool
+		mergeable            bool
+		wantMergeable        string
+		wantMergeStateStatus string
+	}{
+		"open": {
+			state:                "open",
+			mergeable:            true,
+			wantMergeable:        "MERGEABLE",
+			wantMergeStateStatus: "CLEAN",
+		},
+		"open with conflicts": {
+			state:                "open",
+			wantMergeable:        "CONFLICTING",
+			wantMergeStateStatus: "DIRTY",
+		},
+		"merged": {
+			state:                "closed",
+			merged:               true,
+			wantMergeable:        "UNKNOWN",
+			wantMergeStateStatus: "UNKNOWN",
+		},
+		"closed": {
+			state:                "closed",
+			wantMergeable:        "UNKNOWN",
+			wantMergeStateStatus: "UNKNOWN",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := newFakeServer(t)
+			server.handle("/api/v1/repos/infra/tools/", func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/pulls/6"):
+					server.writeJSON(w, map[string]interface{}{
+						"number":    6,
+						"state":     tc.state,
+						"merged":    tc.merged,
+						"mergeable": tc.mergeable,
+						"head":      map[string]interface{}{"ref": "retries", "sha": "abc123"},
+					})
+				case strings.HasSuffix(r.URL.Path, "/status"):
+					server.writeJSON(w, map[string]interface{}{})
+				default:
+					server.writeJSON(w, []interface{}{})
+				}
+			})
+			provider := server.newProvider(providers.ProviderConfig{Type: providers.Gitea})
+
+			pr, err := provider.FetchPullRequest(context.Background(), server.URL+"/infra/tools/pulls/6")
+			require.NoError(t, err)
+			require.Equal(t, tc.wantMergeable, pr.Mergeable)
+			require.Equal(t, tc.wantMergeStateStatus, pr.MergeStateStatus)
+		})
+	}
+}
+
+func TestGiteaIssueActions(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("PATCH /api/v1/repos/infra/tools/issues/4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		server.writeJSON(w, map[string]interface{}{"number": 4})
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.Gitea})
+	actions, ok := provider.(providers.IssueActions)
+	require.True(t, ok)
+
+	require.NoError(t, actions.CloseIssue(4, "infra/tools"))
+	require.NoError(t, actions.ReopenIssue(4, "infra/tools"))
+
+	requests := server.requests("PATCH", "/api/v1/repos/infra/tools/issues/4")
+	require.Len(t, requests, 2)
+	require.JSONEq(t, `{"state": "closed"}`, string(requests[0].Body))
+	require.JSONEq(t, `{"state": "open"}`, string(requests[1].Body))
+}
+
+func TestParseGiteaRemoteURL(t *testing.T) {
+	providers.RegisterHost("git.internal.dev", providers.Gitea)
+	t.Cleanup(func() { providers.UnregisterHost("git.internal.dev") })
+
+	testCases := map[string]struct {
+		remote string
+		want   providers.RemoteInfo
+	}{
+		"codeberg ssh": {
+			remote: "git@codeberg.org:owner/repo.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.Forgejo,
+				Organization: "owner",
+				Repository:   "repo",
+				BaseURL:      "https://codeberg.org",
+			},
+		},
+		"registered host below a subpath": {
+			remote: "https://git.internal.dev/gitea/infra/tools.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.Gitea,
+				Organization: "infra",
+				Repository:   "tools",
+				BaseURL:      "https://git.internal.dev/gitea",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providers.ParseGitRemoteURL(tc.remote)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *got)
+			require.Equal(t, tc.want.Provider, providers.DetectProviderFromURL(tc.remote))
+		})
+	}
+}
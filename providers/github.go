@@ -1,13 +1,18 @@
 package providers
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/charmbracelet/log"
 	gh "github.com/cli/go-gh/v2/pkg/api"
+	ghauth "github.com/cli/go-gh/v2/pkg/auth"
 	graphql "github.com/cli/shurcooL-graphql"
 	"github.com/shurcooL/githubv4"
 
@@ -15,30 +20,81 @@ import (
 )
 
 type GitHubProvider struct {
+	*RateLimitTracker
+
 	client *gh.GraphQLClient
 	config ProviderConfig
+	// authInfoMu guards authInfo, which the fetches of every section fill
+	authInfoMu sync.Mutex
+	authInfo   *AuthInfo
+	// host is github.com or the host of a GitHub Enterprise Server instance
+	host string
 }
 
 func NewGitHubProvider(providerConfig ProviderConfig) (GitProvider, error) {
-	var client *gh.GraphQLClient
-	var err error
+	host := hostFromURL(providerConfig.BaseURL)
+	if host == "" {
+		host, _ = ghauth.DefaultHost()
+	}
 
+	p := &GitHubProvider{
+		RateLimitTracker: &RateLimitTracker{},
+		config:           providerConfig,
+		host:             host,
+	}
+	client, err := p.newGraphQLClient()
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	return p, nil
+}
+
+// newGraphQLClient builds a client for the host of the provider, using the
+// configured token or else the one gh has for that host
+func (p *GitHubProvider) newGraphQLClient() (*gh.GraphQLClient, error) {
 	if config.IsFeatureEnabled(config.FF_MOCK_DATA) {
 		log.Debug("using mock data", "server", "https://localhost:3000")
 		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-		client, err = gh.NewGraphQLClient(gh.ClientOptions{Host: "localhost:3000", AuthToken: "fake-token"})
-	} else {
-		client, err = gh.DefaultGraphQLClient()
+		return gh.NewGraphQLClient(gh.ClientOptions{Host: "localhost:3000", AuthToken: "fake-token", Transport: newRateLimitTransport(p.RateLimitTracker)})
 	}
+	return gh.NewGraphQLClient(gh.ClientOptions{Host: p.host, AuthToken: p.config.Token, Transport: newRateLimitTransport(p.RateLimitTracker)})
+}
 
-	if err != nil {
-		return nil, err
+// rateLimitError turns the error of a query the GraphQL API refused because the
+// rate limit is exhausted into a RateLimitError
+func (p *GitHubProvider) rateLimitError(err error) error {
+	var graphQLErr *gh.GraphQLError
+	if !errors.As(err, &graphQLErr) {
+		return err
 	}
+	for _, item := range graphQLErr.Errors {
+		if item.Type == "RATE_LIMITED" {
+			rateLimit, _ := p.RateLimit()
+			return &RateLimitError{ResetAt: rateLimit.ResetAt}
+		}
+	}
+	return err
+}
 
-	return &GitHubProvider{
-		client: client,
-		config: providerConfig,
-	}, nil
+// Host returns github.com or the host of the GitHub Enterprise Server instance
+func (p *GitHubProvider) Host() string {
+	return p.host
+}
+
+// IsDefaultHost reports whether the provider talks to the host gh uses by default
+func (p *GitHubProvider) IsDefaultHost() bool {
+	defaultHost, _ := ghauth.DefaultHost()
+	return ghauth.NormalizeHostname(p.host) == ghauth.NormalizeHostname(defaultHost)
+}
+
+// RepoArg returns a repository for the -R flag of gh, prefixed with the host
+// when it isn't github.com, e.g. ghe.mycompany.com/owner/repo
+func (p *GitHubProvider) RepoArg(repoNameWithOwner string) string {
+	if p.host == "" || ghauth.NormalizeHostname(p.host) == "github.com" {
+		return repoNameWithOwner
+	}
+	return p.host + "/" + repoNameWithOwner
 }
 
 func (p *GitHubProvider) GetType() ProviderType {
@@ -53,25 +109,86 @@ func (p *GitHubProvider) SupportsIssues() bool {
 	return true
 }
 
+func (p *GitHubProvider) Capabilities() Capabilities {
+	return GitHubCapabilities()
+}
+
+// githubTokenSources names the token sources reported by the GitHub CLI auth package
+var githubTokenSources = map[string]string{
+	"oauth_token": "GitHub CLI config",
+	"gh":          "GitHub CLI keyring",
+	"default":     "GitHub CLI",
+}
+
+// GetAuthInfo resolves the login of the viewer, and the scopes of classic tokens
+// from the X-OAuth-Scopes header. Fine-grained tokens don't report their scopes
 func (p *GitHubProvider) GetAuthInfo() (AuthInfo, error) {
-	// TODO: Implement GitHub auth info extraction
-	return AuthInfo{
-		Username:    "github-user",
+	p.authInfoMu.Lock()
+	defer p.authInfoMu.Unlock()
+	if p.authInfo != nil {
+		return *p.authInfo, nil
+	}
+
+	tokenSource := "GitHub CLI"
+	if p.config.Token != "" {
+		tokenSource = p.config.TokenSource
+	} else if !config.IsFeatureEnabled(config.FF_MOCK_DATA) {
+		_, source := ghauth.TokenForHost(p.host)
+		if name, ok := githubTokenSources[source]; ok {
+			tokenSource = name
+		} else {
+			tokenSource = source
+		}
+	}
+
+	var query struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	if err := p.client.Query("UserCurrent", &query, nil); err != nil {
+		return AuthInfo{IsLoggedIn: false, TokenSource: tokenSource}, err
+	}
+
+	authInfo := AuthInfo{
+		Username:    query.Viewer.Login,
 		IsLoggedIn:  true,
-		TokenSource: "GitHub CLI",
-	}, nil
+		TokenSource: tokenSource,
+		Scopes:      p.fetchTokenScopes(),
+	}
+	p.authInfo = &authInfo
+	return authInfo, nil
+}
+
+func (p *GitHubProvider) fetchTokenScopes() []string {
+	if config.IsFeatureEnabled(config.FF_MOCK_DATA) {
+		return nil
+	}
+	client, err := gh.NewRESTClient(gh.ClientOptions{Host: p.host, AuthToken: p.config.Token})
+	if err != nil {
+		log.Debug("Failed to create GitHub REST client", "error", err)
+		return nil
+	}
+	resp, err := client.Request("GET", "user", nil)
+	if err != nil {
+		log.Debug("Failed to fetch GitHub token scopes", "error", err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var scopes []string
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
 }
 
-func (p *GitHubProvider) FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+func (p *GitHubProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
 	var err error
 	if p.client == nil {
-		if config.IsFeatureEnabled(config.FF_MOCK_DATA) {
-			log.Debug("using mock data", "server", "https://localhost:3000")
-			http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			p.client, err = gh.NewGraphQLClient(gh.ClientOptions{Host: "localhost:3000", AuthToken: "fake-token"})
-		} else {
-			p.client, err = gh.DefaultGraphQLClient()
-		}
+		p.client, err = p.newGraphQLClient()
 		if err != nil {
 			return PullRequestsResponse{}, err
 		}
@@ -85,6 +202,7 @@ func (p *GitHubProvider) FetchPullRequests(query string, limit int, pageInfo *Pa
 			IssueCount int
 			PageInfo   PageInfo
 		} `graphql:"search(type: ISSUE, first: $limit, after: $endCursor, query: $query)"`
+		RateLimit RateLimit
 	}
 	var endCursor *string
 	if pageInfo != nil {
@@ -96,10 +214,11 @@ func (p *GitHubProvider) FetchPullRequests(query string, limit int, pageInfo *Pa
 		"endCursor": (*graphql.String)(endCursor),
 	}
 	log.Debug("Fetching PRs", "query", query, "limit", limit, "endCursor", endCursor)
-	err = p.client.Query("SearchPullRequests", &queryResult, variables)
+	err = p.client.QueryWithContext(ctx, "SearchPullRequests", &queryResult, variables)
 	if err != nil {
-		return PullRequestsResponse{}, err
+		return PullRequestsResponse{}, p.rateLimitError(err)
 	}
+	p.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched PRs", "count", queryResult.Search.IssueCount)
 
 	prs := make([]PullRequestData, 0, len(queryResult.Search.Nodes))
@@ -117,10 +236,10 @@ func (p *GitHubProvider) FetchPullRequests(query string, limit int, pageInfo *Pa
 	}, nil
 }
 
-func (p *GitHubProvider) FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+func (p *GitHubProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
 	var err error
 	if p.client == nil {
-		p.client, err = gh.DefaultGraphQLClient()
+		p.client, err = p.newGraphQLClient()
 		if err != nil {
 			return IssuesResponse{}, err
 		}
@@ -134,6 +253,7 @@ func (p *GitHubProvider) FetchIssues(query string, limit int, pageInfo *PageInfo
 			IssueCount int
 			PageInfo   PageInfo
 		} `graphql:"search(type: ISSUE, first: $limit, after: $endCursor, query: $query)"`
+		RateLimit RateLimit
 	}
 	var endCursor *string
 	if pageInfo != nil {
@@ -145,10 +265,11 @@ func (p *GitHubProvider) FetchIssues(query string, limit int, pageInfo *PageInfo
 		"endCursor": (*graphql.String)(endCursor),
 	}
 	log.Debug("Fetching issues", "query", query, "limit", limit, "endCursor", endCursor)
-	err = p.client.Query("SearchIssues", &queryResult, variables)
+	err = p.client.QueryWithContext(ctx, "SearchIssues", &queryResult, variables)
 	if err != nil {
-		return IssuesResponse{}, err
+		return IssuesResponse{}, p.rateLimitError(err)
 	}
+	p.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched issues", "query", query, "count", queryResult.Search.IssueCount)
 
 	issues := make([]IssueData, 0, len(queryResult.Search.Nodes))
@@ -166,10 +287,114 @@ func (p *GitHubProvider) FetchIssues(query string, limit int, pageInfo *PageInfo
 	}, nil
 }
 
-func (p *GitHubProvider) FetchPullRequest(prUrl string) (PullRequestData, error) {
+// FetchUpdatedPullRequests searches the updated pull requests and counts the
+// ones matching the query through an alias of the same search
+func (p *GitHubProvider) FetchUpdatedPullRequests(ctx context.Context, query string, updatedQuery string, limit int) (PullRequestsResponse, int, error) {
 	var err error
 	if p.client == nil {
-		p.client, err = gh.DefaultGraphQLClient()
+		p.client, err = p.newGraphQLClient()
+		if err != nil {
+			return PullRequestsResponse{}, 0, err
+		}
+	}
+
+	var queryResult struct {
+		Search struct {
+			Nodes []struct {
+				PullRequest PullRequestData `graphql:"... on PullRequest"`
+			}
+			IssueCount int
+			PageInfo   PageInfo
+		} `graphql:"search(type: ISSUE, first: $limit, query: $updatedQuery)"`
+		Matching struct {
+			IssueCount int
+		} `graphql:"matching: search(type: ISSUE, first: 1, query: $query)"`
+		RateLimit RateLimit
+	}
+	variables := map[string]interface{}{
+		"query":        graphql.String(makePullRequestsQuery(query)),
+		"updatedQuery": graphql.String(makePullRequestsQuery(updatedQuery)),
+		"limit":        graphql.Int(limit),
+	}
+	log.Debug("Fetching updated PRs", "query", updatedQuery, "limit", limit)
+	err = p.client.QueryWithContext(ctx, "SearchUpdatedPullRequests", &queryResult, variables)
+	if err != nil {
+		return PullRequestsResponse{}, 0, p.rateLimitError(err)
+	}
+	p.RecordRateLimit(queryResult.RateLimit)
+	log.Debug("Successfully fetched updated PRs", "count", queryResult.Search.IssueCount, "matching", queryResult.Matching.IssueCount)
+
+	prs := make([]PullRequestData, 0, len(queryResult.Search.Nodes))
+	for _, node := range queryResult.Search.Nodes {
+		if node.PullRequest.Repository.IsArchived {
+			continue
+		}
+		prs = append(prs, node.PullRequest)
+	}
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: queryResult.Search.IssueCount,
+		PageInfo:   queryResult.Search.PageInfo,
+	}, queryResult.Matching.IssueCount, nil
+}
+
+// FetchUpdatedIssues searches the updated issues and counts the ones matching
+// the query through an alias of the same search
+func (p *GitHubProvider) FetchUpdatedIssues(ctx context.Context, query string, updatedQuery string, limit int) (IssuesResponse, int, error) {
+	var err error
+	if p.client == nil {
+		p.client, err = p.newGraphQLClient()
+		if err != nil {
+			return IssuesResponse{}, 0, err
+		}
+	}
+
+	var queryResult struct {
+		Search struct {
+			Nodes []struct {
+				Issue IssueData `graphql:"... on Issue"`
+			}
+			IssueCount int
+			PageInfo   PageInfo
+		} `graphql:"search(type: ISSUE, first: $limit, query: $updatedQuery)"`
+		Matching struct {
+			IssueCount int
+		} `graphql:"matching: search(type: ISSUE, first: 1, query: $query)"`
+		RateLimit RateLimit
+	}
+	variables := map[string]interface{}{
+		"query":        graphql.String(makeIssuesQuery(query)),
+		"updatedQuery": graphql.String(makeIssuesQuery(updatedQuery)),
+		"limit":        graphql.Int(limit),
+	}
+	log.Debug("Fetching updated issues", "query", updatedQuery, "limit", limit)
+	err = p.client.QueryWithContext(ctx, "SearchUpdatedIssues", &queryResult, variables)
+	if err != nil {
+		return IssuesResponse{}, 0, p.rateLimitError(err)
+	}
+	p.RecordRateLimit(queryResult.RateLimit)
+	log.Debug("Successfully fetched updated issues", "count", queryResult.Search.IssueCount, "matching", queryResult.Matching.IssueCount)
+
+	issues := make([]IssueData, 0, len(queryResult.Search.Nodes))
+	for _, node := range queryResult.Search.Nodes {
+		if node.Issue.Repository.IsArchived {
+			continue
+		}
+		issues = append(issues, node.Issue)
+	}
+
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: queryResult.Search.IssueCount,
+		PageInfo:   queryResult.Search.PageInfo,
+	}, queryResult.Matching.IssueCount, nil
+}
+
+func (p *GitHubProvider) FetchPullRequest(ctx context.Context, prUrl string) (PullRequestData, error) {
+	var err error
+	if p.client == nil {
+		p.client, err = p.newGraphQLClient()
 		if err != nil {
 			return PullRequestData{}, err
 		}
@@ -179,6 +404,7 @@ func (p *GitHubProvider) FetchPullRequest(prUrl string) (PullRequestData, error)
 		Resource struct {
 			PullRequest PullRequestData `graphql:"... on PullRequest"`
 		} `graphql:"resource(url: $url)"`
+		RateLimit RateLimit
 	}
 	parsedUrl, err := url.Parse(prUrl)
 	if err != nil {
@@ -188,10 +414,11 @@ func (p *GitHubProvider) FetchPullRequest(prUrl string) (PullRequestData, error)
 		"url": githubv4.URI{URL: parsedUrl},
 	}
 	log.Debug("Fetching PR", "url", prUrl)
-	err = p.client.Query("FetchPullRequest", &queryResult, variables)
+	err = p.client.QueryWithContext(ctx, "FetchPullRequest", &queryResult, variables)
 	if err != nil {
-		return PullRequestData{}, err
+		return PullRequestData{}, p.rateLimitError(err)
 	}
+	p.RecordRateLimit(queryResult.RateLimit)
 	log.Debug("Successfully fetched PR", "url", prUrl)
 
 	return queryResult.Resource.PullRequest, nil
@@ -213,7 +440,7 @@ func (p *GitHubProvider) GetDiffCommand(prNumber int, repoNameWithOwner string)
 		"diff",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -224,7 +451,7 @@ func (p *GitHubProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner stri
 		"checkout",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -235,7 +462,7 @@ func (p *GitHubProvider) GetMergeCommand(prNumber int, repoNameWithOwner string)
 		"merge",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -246,7 +473,7 @@ func (p *GitHubProvider) GetCloseCommand(prNumber int, repoNameWithOwner string)
 		"close",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -257,7 +484,7 @@ func (p *GitHubProvider) GetReopenCommand(prNumber int, repoNameWithOwner string
 		"reopen",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -268,7 +495,7 @@ func (p *GitHubProvider) GetReadyCommand(prNumber int, repoNameWithOwner string)
 		"ready",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -279,7 +506,7 @@ func (p *GitHubProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string
 		"update-branch",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
 
@@ -291,6 +518,6 @@ func (p *GitHubProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner s
 		"--watch",
 		fmt.Sprint(prNumber),
 		"-R",
-		repoNameWithOwner,
+		p.RepoArg(repoNameWithOwner),
 	}, nil
 }
\ No newline at end of file
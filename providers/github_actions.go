@@ -0,0 +1,319 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/shurcooL/githubv4"
+)
+
+// githubMergeMethods maps the configured merge strategy onto the merge methods of GitHub
+var githubMergeMethods = map[string]githubv4.PullRequestMergeMethod{
+	"merge":  githubv4.PullRequestMergeMethodMerge,
+	"squash": githubv4.PullRequestMergeMethodSquash,
+	"rebase": githubv4.PullRequestMergeMethodRebase,
+}
+
+// githubPullRequest is the part of a pull request the mutations need
+type githubPullRequest struct {
+	ID         githubv4.ID
+	HeadRefOid githubv4.GitObjectID
+	Repository struct {
+		// ViewerDefaultMergeMethod is the merge method the viewer last used in
+		// the repository, or else the first one the repository allows
+		ViewerDefaultMergeMethod githubv4.PullRequestMergeMethod
+	}
+}
+
+func (p *GitHubProvider) ApprovePullRequest(prNumber int, repoNameWithOwner string, comment string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+
+	event := githubv4.PullRequestReviewEventApprove
+	input := githubv4.AddPullRequestReviewInput{PullRequestID: pr.ID, Event: &event}
+	if comment != "" {
+		input.Body = githubv4.NewString(githubv4.String(comment))
+	}
+	var mutation struct {
+		AddPullRequestReview struct {
+			ClientMutationID string
+		} `graphql:"addPullRequestReview(input: $input)"`
+	}
+	return p.mutate(ctx, "AddPullRequestReview", &mutation, input)
+}
+
+// MergePullRequest merges with the configured merge strategy, or else with the
+// merge method the viewer last used in the repository
+func (p *GitHubProvider) MergePullRequest(prNumber int, repoNameWithOwner string) error {
+	method, ok := githubMergeMethods[p.config.MergeStrategy]
+	if !ok && p.config.MergeStrategy != "" {
+		return fmt.Errorf("unknown GitHub merge strategy %q, use merge, squash or rebase", p.config.MergeStrategy)
+	}
+
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		method = pr.Repository.ViewerDefaultMergeMethod
+	}
+
+	input := githubv4.MergePullRequestInput{
+		PullRequestID: pr.ID,
+		MergeMethod:   &method,
+	}
+	var mutation struct {
+		MergePullRequest struct {
+			ClientMutationID string
+		} `graphql:"mergePullRequest(input: $input)"`
+	}
+	return p.mutate(ctx, "MergePullRequest", &mutation, input)
+}
+
+func (p *GitHubProvider) ClosePullRequest(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		ClosePullRequest struct {
+			ClientMutationID string
+		} `graphql:"closePullRequest(input: $input)"`
+	}
+	return p.mutate(ctx, "ClosePullRequest", &mutation, githubv4.ClosePullRequestInput{PullRequestID: pr.ID})
+}
+
+func (p *GitHubProvider) ReopenPullRequest(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		ReopenPullRequest struct {
+			ClientMutationID string
+		} `graphql:"reopenPullRequest(input: $input)"`
+	}
+	return p.mutate(ctx, "ReopenPullRequest", &mutation, githubv4.ReopenPullRequestInput{PullRequestID: pr.ID})
+}
+
+func (p *GitHubProvider) MarkPullRequestReady(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		MarkPullRequestReadyForReview struct {
+			ClientMutationID string
+		} `graphql:"markPullRequestReadyForReview(input: $input)"`
+	}
+	return p.mutate(ctx, "MarkPullRequestReadyForReview", &mutation,
+		githubv4.MarkPullRequestReadyForReviewInput{PullRequestID: pr.ID})
+}
+
+func (p *GitHubProvider) UpdatePullRequestBranch(prNumber int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	pr, err := p.fetchGitHubPullRequest(ctx, prNumber, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		UpdatePullRequestBranch struct {
+			ClientMutationID string
+		} `graphql:"updatePullRequestBranch(input: $input)"`
+	}
+	return p.mutate(ctx, "UpdatePullRequestBranch", &mutation, githubv4.UpdatePullRequestBranchInput{
+		PullRequestID:   pr.ID,
+		ExpectedHeadOid: &pr.HeadRefOid,
+	})
+}
+
+func (p *GitHubProvider) AddComment(number int, repoNameWithOwner string, body string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	id, err := p.fetchSubjectID(ctx, number, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		AddComment struct {
+			ClientMutationID string
+		} `graphql:"addComment(input: $input)"`
+	}
+	return p.mutate(ctx, "AddComment", &mutation, githubv4.AddCommentInput{
+		SubjectID: id,
+		Body:      githubv4.String(body),
+	})
+}
+
+func (p *GitHubProvider) AddAssignees(number int, repoNameWithOwner string, logins []string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	id, userIDs, err := p.fetchAssignableIDs(ctx, number, repoNameWithOwner, logins)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		AddAssigneesToAssignable struct {
+			ClientMutationID string
+		} `graphql:"addAssigneesToAssignable(input: $input)"`
+	}
+	return p.mutate(ctx, "AddAssigneesToAssignable", &mutation, githubv4.AddAssigneesToAssignableInput{
+		AssignableID: id,
+		AssigneeIDs:  userIDs,
+	})
+}
+
+func (p *GitHubProvider) RemoveAssignees(number int, repoNameWithOwner string, logins []string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	id, userIDs, err := p.fetchAssignableIDs(ctx, number, repoNameWithOwner, logins)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		RemoveAssigneesFromAssignable struct {
+			ClientMutationID string
+		} `graphql:"removeAssigneesFromAssignable(input: $input)"`
+	}
+	return p.mutate(ctx, "RemoveAssigneesFromAssignable", &mutation, githubv4.RemoveAssigneesFromAssignableInput{
+		AssignableID: id,
+		AssigneeIDs:  userIDs,
+	})
+}
+
+func (p *GitHubProvider) CloseIssue(number int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	id, err := p.fetchSubjectID(ctx, number, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		CloseIssue struct {
+			ClientMutationID string
+		} `graphql:"closeIssue(input: $input)"`
+	}
+	return p.mutate(ctx, "CloseIssue", &mutation, githubv4.CloseIssueInput{IssueID: id})
+}
+
+func (p *GitHubProvider) ReopenIssue(number int, repoNameWithOwner string) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	id, err := p.fetchSubjectID(ctx, number, repoNameWithOwner)
+	if err != nil {
+		return err
+	}
+	var mutation struct {
+		ReopenIssue struct {
+			ClientMutationID string
+		} `graphql:"reopenIssue(input: $input)"`
+	}
+	return p.mutate(ctx, "ReopenIssue", &mutation, githubv4.ReopenIssueInput{IssueID: id})
+}
+
+// mutate runs a mutation. Errors are returned as reported by GitHub, such as
+// the branch protection rules a merge violates, so they reach the task footer
+func (p *GitHubProvider) mutate(ctx context.Context, name string, mutation interface{}, input interface{}) error {
+	log.Debug("Running GitHub mutation", "name", name)
+	return p.client.MutateWithContext(ctx, name, mutation, map[string]interface{}{"input": input})
+}
+
+func (p *GitHubProvider) fetchGitHubPullRequest(ctx context.Context, prNumber int, repoNameWithOwner string) (githubPullRequest, error) {
+	owner, name, err := splitRepoNameWithOwner(repoNameWithOwner)
+	if err != nil {
+		return githubPullRequest{}, err
+	}
+
+	var query struct {
+		Repository struct {
+			PullRequest githubPullRequest `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(prNumber),
+	}
+	if err := p.client.QueryWithContext(ctx, "FetchPullRequestID", &query, variables); err != nil {
+		return githubPullRequest{}, err
+	}
+	return query.Repository.PullRequest, nil
+}
+
+// fetchSubjectID returns the node id of an issue or a pull request, which
+// share their numbers within a repository
+func (p *GitHubProvider) fetchSubjectID(ctx context.Context, number int, repoNameWithOwner string) (githubv4.ID, error) {
+	owner, name, err := splitRepoNameWithOwner(repoNameWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var query struct {
+		Repository struct {
+			IssueOrPullRequest struct {
+				Node struct {
+					ID githubv4.ID
+				} `graphql:"... on Node"`
+			} `graphql:"issueOrPullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+	}
+	if err := p.client.QueryWithContext(ctx, "FetchSubjectID", &query, variables); err != nil {
+		return nil, err
+	}
+	if query.Repository.IssueOrPullRequest.Node.ID == nil {
+		return nil, fmt.Errorf("no issue or pull request #%d in %s", number, repoNameWithOwner)
+	}
+	return query.Repository.IssueOrPullRequest.Node.ID, nil
+}
+
+// fetchAssignableIDs returns the node ids of an issue or a pull request and
+// of the users to assign, which the assignee mutations take instead of logins
+func (p *GitHubProvider) fetchAssignableIDs(ctx context.Context, number int, repoNameWithOwner string, logins []string) (githubv4.ID, []githubv4.ID, error) {
+	id, err := p.fetchSubjectID(ctx, number, repoNameWithOwner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userIDs := make([]githubv4.ID, 0, len(logins))
+	for _, login := range logins {
+		var query struct {
+			User struct {
+				ID githubv4.ID
+			} `graphql:"user(login: $login)"`
+		}
+		variables := map[string]interface{}{"login": githubv4.String(login)}
+		if err := p.client.QueryWithContext(ctx, "FetchUserID", &query, variables); err != nil {
+			return nil, nil, err
+		}
+		userIDs = append(userIDs, query.User.ID)
+	}
+	return id, userIDs, nil
+}
+
+func splitRepoNameWithOwner(repoNameWithOwner string) (string, string, error) {
+	owner, name, ok := strings.Cut(repoNameWithOwner, "/")
+	if !ok || owner == "" || name == "" {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/name", repoNameWithOwner)
+	}
+	return owner, name, nil
+}
@@ -0,0 +1,212 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func TestParseGitHubRemoteURL(t *testing.T) {
+	testCases := map[string]struct {
+		remote string
+		want   providers.RemoteInfo
+	}{
+		"github.com ssh": {
+			remote: "git@github.com:dlvhdr/gh-dash.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitHub,
+				Organization: "dlvhdr",
+				Repository:   "gh-dash",
+				BaseURL:      "https://github.com",
+			},
+		},
+		"enterprise server https": {
+			remote: "https://ghe.example.com/team/repo.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitHub,
+				Organization: "team",
+				Repository:   "repo",
+				BaseURL:      "https://ghe.example.com",
+			},
+		},
+		"enterprise server ssh": {
+			remote: "git@ghe.example.com:team/repo.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitHub,
+				Organization: "team",
+				Repository:   "repo",
+				BaseURL:      "https://ghe.example.com",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providers.ParseGitRemoteURL(tc.remote)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *got)
+		})
+	}
+}
+
+func TestGitHubEnterpriseServerCommands(t *testing.T) {
+	provider, err := providers.NewGitHubProvider(providers.ProviderConfig{
+		Type:    providers.GitHub,
+		BaseURL: "https://ghe.example.com",
+		Token:   "secret",
+	})
+	require.NoError(t, err)
+
+	args, err := provider.GetCheckoutCommand(7, "team/repo")
+	require.NoError(t, err)
+	require.Equal(t, []string{"gh", "pr", "checkout", "7", "-R", "ghe.example.com/team/repo"}, args)
+
+	require.Equal(t, "ghe.example.com", provider.(*providers.GitHubProvider).Host())
+
+	dotCom, err := providers.NewGitHubProvider(providers.ProviderConfig{
+		Type:    providers.GitHub,
+		BaseURL: "https://github.com",
+		Token:   "secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "team/repo", dotCom.(*providers.GitHubProvider).RepoArg("team/repo"))
+}
+
+// rewriteTransport sends every request to the test server
+type rewriteTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.transport.RoundTrip(req)
+}
+
+// newTestGitHubProvider creates a GitHub Enterprise Server provider whose
+// requests are sent to the fake server
+func newTestGitHubProvider(t *testing.T, server *fakeServer) providers.GitProvider {
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = rewriteTransport{target: target, transport: defaultTransport}
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+
+	provider, err := providers.NewGitHubProvider(providers.ProviderConfig{
+		Type:    providers.GitHub,
+		BaseURL: "https://ghe.example.com",
+		Token:   "secret",
+	})
+	require.NoError(t, err)
+	return provider
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// graphQLMutations returns the mutations the server received
+func graphQLMutations(t *testing.T, server *fakeServer) []graphQLRequest {
+	var mutations []graphQLRequest
+	for _, req := range server.requests("POST", "/api/graphql") {
+		var query graphQLRequest
+		require.NoError(t, json.Unmarshal(req.Body, &query))
+		if strings.HasPrefix(query.Query, "mutation") {
+			mutations = append(mutations, query)
+		}
+	}
+	return mutations
+}
+
+func TestGitHubPullRequestMutations(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if !server.readJSON(r, &req) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasPrefix(req.Query, "mutation"):
+			if strings.Contains(req.Query, "mergePullRequest") {
+				fmt.Fprint(w, `{"data":{"mergePullRequest":null},"errors":[{"type":"UNPROCESSABLE","message":"At least 2 approving reviews are required by reviewers with write access."}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{}}`)
+		case strings.Contains(req.Query, "pullRequest(number: $number)"):
+			fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"id":"PR_1","headRefOid":"abc123","repository":{"viewerDefaultMergeMethod":"SQUASH"}}}}}`)
+		case strings.Contains(req.Query, "issueOrPullRequest"):
+			fmt.Fprint(w, `{"data":{"repository":{"issueOrPullRequest":{"id":"I_1"}}}}`)
+		case strings.Contains(req.Query, "user(login: $login)"):
+			fmt.Fprintf(w, `{"data":{"user":{"id":"U_%s"}}}`, req.Variables["login"])
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	})
+	provider := newTestGitHubProvider(t, server)
+
+	require.NoError(t, provider.(providers.PullRequestActions).ClosePullRequest(7, "team/repo"))
+	require.NoError(t, provider.(providers.BranchUpdater).UpdatePullRequestBranch(7, "team/repo"))
+	require.NoError(t, provider.(providers.Commenter).AddComment(7, "team/repo", "LGTM"))
+	require.NoError(t, provider.(providers.Assigner).AddAssignees(7, "team/repo", []string{"octocat"}))
+	// Errors reported by GitHub, like unmet branch protection rules, are kept
+	err := provider.(providers.PullRequestActions).MergePullRequest(7, "team/repo")
+	require.ErrorContains(t, err, "At least 2 approving reviews are required")
+	require.NoError(t, provider.(providers.IssueActions).CloseIssue(7, "team/repo"))
+
+	mutations := graphQLMutations(t, server)
+	require.Len(t, mutations, 6)
+	require.Equal(t, map[string]interface{}{"pullRequestId": "PR_1"}, mutations[0].Variables["input"])
+	require.Equal(t, map[string]interface{}{"pullRequestId": "PR_1", "expectedHeadOid": "abc123"}, mutations[1].Variables["input"])
+	require.Equal(t, map[string]interface{}{"subjectId": "I_1", "body": "LGTM"}, mutations[2].Variables["input"])
+	require.Equal(t, map[string]interface{}{"assignableId": "I_1", "assigneeIds": []interface{}{"U_octocat"}}, mutations[3].Variables["input"])
+	require.Equal(t, map[string]interface{}{"pullRequestId": "PR_1", "mergeMethod": "SQUASH"}, mutations[4].Variables["input"])
+	require.Equal(t, map[string]interface{}{"issueId": "I_1"}, mutations[5].Variables["input"])
+}
+
+func TestGitHubFetchUpdated(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if !server.readJSON(r, &req) {
+			return
+		}
+		assert.Contains(t, req.Query, "matching: search(")
+		assert.Contains(t, req.Variables["query"], "author:@me")
+		assert.NotContains(t, req.Variables["query"], "updated:")
+		assert.Contains(t, req.Variables["updatedQuery"], "author:@me updated:>=2024-05-01T00:00:00Z")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{
+			"search":{"issueCount":1,"nodes":[{"number":7,"title":"Add search"}],"pageInfo":{"hasNextPage":false}},
+			"matching":{"issueCount":12}
+		}}`)
+	})
+	provider := newTestGitHubProvider(t, server).(providers.UpdatedFetcher)
+
+	prs, totalCount, err := provider.FetchUpdatedPullRequests(context.Background(), "author:@me", "author:@me updated:>=2024-05-01T00:00:00Z", 20)
+	require.NoError(t, err)
+	require.Equal(t, 12, totalCount)
+	require.Len(t, prs.Prs, 1)
+	require.Equal(t, "Add search", prs.Prs[0].Title)
+
+	issues, totalCount, err := provider.FetchUpdatedIssues(context.Background(), "author:@me", "author:@me updated:>=2024-05-01T00:00:00Z", 20)
+	require.NoError(t, err)
+	require.Equal(t, 12, totalCount)
+	require.Len(t, issues.Issues, 1)
+
+	// Each refresh is a single request
+	require.Len(t, server.requests("POST", "/api/graphql"), 2)
+}
@@ -0,0 +1,771 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const defaultGitLabBaseURL = "https://gitlab.com"
+
+type GitLabProvider struct {
+	*RateLimitTracker
+
+	client  *http.Client
+	config  ProviderConfig
+	baseURL string
+	token   string
+
+	// usernameMu guards username, which the fetches of every section fill
+	usernameMu sync.Mutex
+	username   string
+}
+
+type GitLabUser struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+type GitLabLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type GitLabPipeline struct {
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Ref    string `json:"ref"`
+	WebURL string `json:"web_url"`
+}
+
+type GitLabMergeRequest struct {
+	Iid                 int             `json:"iid"`
+	ProjectId           int             `json:"project_id"`
+	Title               string          `json:"title"`
+	Description         string          `json:"description"`
+	State               string          `json:"state"`
+	Draft               bool            `json:"draft"`
+	Author              GitLabUser      `json:"author"`
+	Assignees           []GitLabUser    `json:"assignees"`
+	Reviewers           []GitLabUser    `json:"reviewers"`
+	Labels              []GitLabLabel   `json:"labels"`
+	SourceBranch        string          `json:"source_branch"`
+	TargetBranch        string          `json:"target_branch"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+	WebURL              string          `json:"web_url"`
+	UserNotesCount      int             `json:"user_notes_count"`
+	HasConflicts        bool            `json:"has_conflicts"`
+	DetailedMergeStatus string          `json:"detailed_merge_status"`
+	HeadPipeline        *GitLabPipeline `json:"head_pipeline"`
+}
+
+type GitLabApprovals struct {
+	Approved          bool `json:"approved"`
+	ApprovalsRequired int  `json:"approvals_required"`
+	ApprovalsLeft     int  `json:"approvals_left"`
+	ApprovedBy        []struct {
+		User GitLabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+type GitLabIssue struct {
+	Iid            int           `json:"iid"`
+	ProjectId      int           `json:"project_id"`
+	Title          string        `json:"title"`
+	Description    string        `json:"description"`
+	State          string        `json:"state"`
+	Author         GitLabUser    `json:"author"`
+	Assignees      []GitLabUser  `json:"assignees"`
+	Labels         []GitLabLabel `json:"labels"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	WebURL         string        `json:"web_url"`
+	UserNotesCount int           `json:"user_notes_count"`
+	Upvotes        int           `json:"upvotes"`
+}
+
+type GitLabNote struct {
+	Body      string     `json:"body"`
+	Author    GitLabUser `json:"author"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	System    bool       `json:"system"`
+}
+
+type GitLabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+func NewGitLabProvider(config ProviderConfig) (GitProvider, error) {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultGitLabBaseURL
+	}
+
+	log.Debug("Creating GitLab provider", "baseURL", baseURL, "hasToken", config.Token != "")
+
+	rateLimits := &RateLimitTracker{}
+	return &GitLabProvider{
+		RateLimitTracker: rateLimits,
+		client:           newRateLimitedClient(rateLimits),
+		config:           config,
+		baseURL:          baseURL,
+		token:            config.Token,
+	}, nil
+}
+
+func (p *GitLabProvider) GetType() ProviderType {
+	return GitLab
+}
+
+func (p *GitLabProvider) SupportsPullRequests() bool {
+	return true
+}
+
+func (p *GitLabProvider) SupportsIssues() bool {
+	return true
+}
+
+// Capabilities are the actions of glab, which merges with the project's merge method
+func (p *GitLabProvider) Capabilities() Capabilities {
+	return Capabilities{
+		MergeStrategies: []string{"merge"},
+		Close:           true,
+		DraftToggle:     true,
+		UpdateBranch:    true,
+		IssueClose:      true,
+		Labels:          true,
+		Checkout:        true,
+	}
+}
+
+func (p *GitLabProvider) GetAuthInfo() (AuthInfo, error) {
+	ctx, cancel := actionContext()
+	defer cancel()
+	username, err := p.currentUsername(ctx)
+	if err != nil {
+		return AuthInfo{IsLoggedIn: false, TokenSource: "Personal Access Token"}, err
+	}
+	return AuthInfo{
+		Username:    username,
+		IsLoggedIn:  true,
+		TokenSource: "Personal Access Token",
+	}, nil
+}
+
+func (p *GitLabProvider) currentUsername(ctx context.Context) (string, error) {
+	p.usernameMu.Lock()
+	defer p.usernameMu.Unlock()
+	if p.username != "" {
+		return p.username, nil
+	}
+	var user GitLabUser
+	if _, err := p.get(ctx, "/user", nil, &user); err != nil {
+		return "", err
+	}
+	p.username = user.Username
+	return p.username, nil
+}
+
+func (p *GitLabProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	if p.token == "" {
+		return PullRequestsResponse{}, fmt.Errorf("GitLab access token is required. Set GITLAB_TOKEN or GITLAB_ACCESS_TOKEN environment variable")
+	}
+
+	list, err := p.buildListParams(ctx, "merge_requests", query, limit, pageInfo)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+
+	log.Debug("Fetching GitLab merge requests", "path", list.path, "params", list.params.Encode())
+	var mrs []GitLabMergeRequest
+	header, err := p.get(ctx, list.path, list.params, &mrs)
+	if err != nil {
+		return PullRequestsResponse{}, err
+	}
+	log.Debug("Successfully fetched GitLab merge requests", "count", len(mrs))
+
+	prs := make([]PullRequestData, len(mrs))
+	forEachConcurrently(len(mrs), func(i int) {
+		prs[i] = p.convertMergeRequestToData(mrs[i], p.fetchMergeRequestDetails(ctx, mrs[i]))
+	})
+
+	return PullRequestsResponse{
+		Prs:        prs,
+		TotalCount: gitLabTotalCount(header, len(prs)),
+		PageInfo:   gitLabPageInfo(header, list.params.Get("page")),
+		Warnings:   unsupportedFiltersWarning("GitLab", list.unsupported),
+	}, nil
+}
+
+func (p *GitLabProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	if p.token == "" {
+		return IssuesResponse{}, fmt.Errorf("GitLab access token is required. Set GITLAB_TOKEN or GITLAB_ACCESS_TOKEN environment variable")
+	}
+
+	list, err := p.buildListParams(ctx, "issues", query, limit, pageInfo)
+	if err != nil {
+		return IssuesResponse{}, err
+	}
+
+	log.Debug("Fetching GitLab issues", "path", list.path, "params", list.params.Encode())
+	var glIssues []GitLabIssue
+	header, err := p.get(ctx, list.path, list.params, &glIssues)
+	if err != nil {
+		return IssuesResponse{}, err
+	}
+	log.Debug("Successfully fetched GitLab issues", "count", len(glIssues))
+
+	issues := make([]IssueData, 0, len(glIssues))
+	for _, glIssue := range glIssues {
+		issues = append(issues, p.convertIssueToData(glIssue))
+	}
+
+	return IssuesResponse{
+		Issues:     issues,
+		TotalCount: gitLabTotalCount(header, len(issues)),
+		PageInfo:   gitLabPageInfo(header, list.params.Get("page")),
+		Warnings:   unsupportedFiltersWarning("GitLab", list.unsupported),
+	}, nil
+}
+
+var gitLabMergeRequestURLRegexp = regexp.MustCompile(`^https?://[^/]+/(.+?)/-/merge_requests/(\d+)`)
+
+func (p *GitLabProvider) FetchPullRequest(ctx context.Context, prUrl string) (PullRequestData, error) {
+	matches := gitLabMergeRequestURLRegexp.FindStringSubmatch(prUrl)
+	if len(matches) != 3 {
+		return PullRequestData{}, fmt.Errorf("invalid GitLab merge request URL: %s", prUrl)
+	}
+	projectPath, iid := matches[1], matches[2]
+
+	log.Debug("Fetching GitLab merge request", "url", prUrl)
+	var mr GitLabMergeRequest
+	mrPath := fmt.Sprintf("/projects/%s/merge_requests/%s", url.PathEscape(projectPath), iid)
+	if _, err := p.get(ctx, mrPath, nil, &mr); err != nil {
+		return PullRequestData{}, err
+	}
+
+	pr := p.convertMergeRequestToData(mr, p.fetchMergeRequestDetails(ctx, mr))
+
+	var diffs []GitLabDiff
+	if _, err := p.get(ctx, mrPath+"/diffs", url.Values{"per_page": {"100"}}, &diffs); err != nil {
+		log.Debug("Failed fetching GitLab merge request diffs", "url", prUrl, "err", err)
+	}
+	pr.Files = convertGitLabDiffs(diffs)
+	for _, file := range pr.Files.Nodes {
+		pr.Additions += file.Additions
+		pr.Deletions += file.Deletions
+	}
+
+	var notes []GitLabNote
+	params := url.Values{"sort": {"desc"}, "order_by": {"updated_at"}, "per_page": {"20"}}
+	if _, err := p.get(ctx, mrPath+"/notes", params, &notes); err != nil {
+		log.Debug("Failed fetching GitLab merge request notes", "url", prUrl, "err", err)
+	}
+	pr.Comments.Nodes = make([]Comment, 0, len(notes))
+	for _, note := range notes {
+		if note.System {
+			continue
+		}
+		var comment Comment
+		comment.Author.Login = note.Author.Username
+		comment.Body = note.Body
+		comment.UpdatedAt = note.UpdatedAt
+		pr.Comments.Nodes = append(pr.Comments.Nodes, comment)
+	}
+	log.Debug("Successfully fetched GitLab merge request", "url", prUrl)
+
+	return pr, nil
+}
+
+type gitLabMergeRequestDetails struct {
+	pipeline  *GitLabPipeline
+	approvals *GitLabApprovals
+}
+
+// fetchMergeRequestDetails fetches the data the merge request list endpoint
+// doesn't include: the latest pipeline and the approval state
+func (p *GitLabProvider) fetchMergeRequestDetails(ctx context.Context, mr GitLabMergeRequest) gitLabMergeRequestDetails {
+	var details gitLabMergeRequestDetails
+	mrPath := fmt.Sprintf("/projects/%d/merge_requests/%d", mr.ProjectId, mr.Iid)
+
+	details.pipeline = mr.HeadPipeline
+	if details.pipeline == nil {
+		var pipelines []GitLabPipeline
+		if _, err := p.get(ctx, mrPath+"/pipelines", url.Values{"per_page": {"1"}}, &pipelines); err != nil {
+			log.Debug("Failed fetching GitLab pipelines", "mr", mr.WebURL, "err", err)
+		} else if len(pipelines) > 0 {
+			details.pipeline = &pipelines[0]
+		}
+	}
+
+	var approvals GitLabApprovals
+	if _, err := p.get(ctx, mrPath+"/approvals", nil, &approvals); err != nil {
+		log.Debug("Failed fetching GitLab approvals", "mr", mr.WebURL, "err", err)
+	} else {
+		details.approvals = &approvals
+	}
+
+	return details
+}
+
+type gitLabListRequest struct {
+	path   string
+	params url.Values
+	// unsupported lists the qualifiers that couldn't be translated, as written by the user
+	unsupported []string
+}
+
+// buildListParams translates a section filter into a GitLab list endpoint and its query parameters
+func (p *GitLabProvider) buildListParams(ctx context.Context, resource string, query string, limit int, pageInfo *PageInfo) (gitLabListRequest, error) {
+	params := url.Values{}
+	params.Set("scope", "all")
+	params.Set("per_page", strconv.Itoa(limit))
+	params.Set("order_by", "updated_at")
+	params.Set("sort", "desc")
+	params.Set("with_labels_details", "true")
+	page := "1"
+	if pageInfo != nil && pageInfo.EndCursor != "" {
+		page = pageInfo.EndCursor
+	}
+	params.Set("page", page)
+
+	apiPath := "/" + resource
+	var labels, notLabels, unsupported []string
+	parsed := ParseSearchQuery(query)
+	for _, filter := range parsed.Filters {
+		value := filter.Value
+		if value == "@me" {
+			username, err := p.currentUsername(ctx)
+			if err != nil {
+				return gitLabListRequest{}, err
+			}
+			value = username
+		}
+
+		switch {
+		case filter.Key == "label" && filter.Negated:
+			notLabels = append(notLabels, value)
+		case filter.Negated:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		case filter.Key == "is" || filter.Key == "state":
+			switch strings.ToLower(value) {
+			case "open", "opened":
+				params.Set("state", "opened")
+			case "closed":
+				params.Set("state", "closed")
+			case "merged":
+				params.Set("state", "merged")
+			case "draft":
+				params.Set("wip", "yes")
+			}
+		case filter.Key == "draft":
+			if value == "true" {
+				params.Set("wip", "yes")
+			} else {
+				params.Set("wip", "no")
+			}
+		case filter.Key == "author":
+			params.Set("author_username", value)
+		case filter.Key == "assignee":
+			params.Set("assignee_username", value)
+		case filter.Key == "review-requested" || filter.Key == "reviewer":
+			params.Set("reviewer_username", value)
+		case filter.Key == "label":
+			labels = append(labels, value)
+		case filter.Key == "base":
+			params.Set("target_branch", value)
+		case filter.Key == "head":
+			params.Set("source_branch", value)
+		case filter.Key == "repo":
+			apiPath = fmt.Sprintf("/projects/%s/%s", url.PathEscape(value), resource)
+			params.Del("scope")
+		default:
+			unsupported = append(unsupported, formatSearchFilter(filter))
+		}
+	}
+	if len(labels) > 0 {
+		params.Set("labels", strings.Join(labels, ","))
+	}
+	if len(notLabels) > 0 {
+		params.Set("not[labels]", strings.Join(notLabels, ","))
+	}
+	if text := parsed.Text(); text != "" {
+		params.Set("search", text)
+	}
+	if len(unsupported) > 0 {
+		log.Debug("Unsupported GitLab filters", "filters", unsupported)
+	}
+
+	return gitLabListRequest{path: apiPath, params: params, unsupported: unsupported}, nil
+}
+
+func (p *GitLabProvider) get(ctx context.Context, apiPath string, params url.Values, result interface{}) (http.Header, error) {
+	return p.do(ctx, "GET", apiPath, params, nil, result)
+}
+
+// do sends a request to the API, with the payload encoded as JSON when there
+// is one, and decodes the response into result when it isn't nil
+func (p *GitLabProvider) do(ctx context.Context, method string, apiPath string, params url.Values, payload interface{}, result interface{}) (http.Header, error) {
+	apiURL := fmt.Sprintf("%s/api/v4%s", p.baseURL, apiPath)
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	p.setAuthHeader(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GitLab API error: %s", string(body))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Header, nil
+}
+
+func (p *GitLabProvider) setAuthHeader(req *http.Request) {
+	if p.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", p.token)
+	}
+}
+
+func gitLabTotalCount(header http.Header, fallback int) int {
+	if total, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+		return total
+	}
+	return fallback
+}
+
+func gitLabPageInfo(header http.Header, page string) PageInfo {
+	nextPage := header.Get("X-Next-Page")
+	return PageInfo{
+		HasNextPage: nextPage != "",
+		StartCursor: page,
+		EndCursor:   nextPage,
+	}
+}
+
+// projectPathFromWebURL extracts the project path with namespace from a
+// merge request or issue web URL, e.g. https://gitlab.com/group/sub/repo/-/issues/1
+func (p *GitLabProvider) projectPathFromWebURL(webURL string) string {
+	path := strings.TrimPrefix(webURL, p.baseURL+"/")
+	if idx := strings.Index(path, "/-/"); idx >= 0 {
+		path = path[:idx]
+	}
+	return path
+}
+
+func gitLabRepository(nameWithOwner string) Repository {
+	name := nameWithOwner
+	if idx := strings.LastIndex(nameWithOwner, "/"); idx >= 0 {
+		name = nameWithOwner[idx+1:]
+	}
+	return Repository{Name: name, NameWithOwner: nameWithOwner}
+}
+
+func (p *GitLabProvider) convertMergeRequestToData(mr GitLabMergeRequest, details gitLabMergeRequestDetails) PullRequestData {
+	var state string
+	switch mr.State {
+	case "merged":
+		state = "MERGED"
+	case "closed", "locked":
+		state = "CLOSED"
+	default:
+		state = "OPEN"
+	}
+
+	mergeable := "MERGEABLE"
+	if mr.HasConflicts {
+		mergeable = "CONFLICTING"
+	}
+
+	repo := gitLabRepository(p.projectPathFromWebURL(mr.WebURL))
+	pr := PullRequestData{
+		Number:            mr.Iid,
+		Title:             mr.Title,
+		Body:              mr.Description,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         mr.UpdatedAt,
+		CreatedAt:         mr.CreatedAt,
+		Url:               mr.WebURL,
+		State:             state,
+		Mergeable:         mergeable,
+		HeadRefName:       mr.SourceBranch,
+		BaseRefName:       mr.TargetBranch,
+		Repository:        repo,
+		Comments:          Comments{TotalCount: mr.UserNotesCount},
+		IsDraft:           mr.Draft,
+		Assignees:         convertGitLabAssignees(mr.Assignees),
+		MergeStateStatus:  gitLabMergeStateStatus(mr.DetailedMergeStatus),
+	}
+	pr.Author.Login = mr.Author.Username
+	pr.HeadRepository.Name = repo.Name
+	pr.HeadRef.Name = mr.SourceBranch
+
+	for _, label := range mr.Labels {
+		pr.Labels.Nodes = append(pr.Labels.Nodes, Label{Name: label.Name, Color: strings.TrimPrefix(label.Color, "#")})
+	}
+
+	pr.ReviewRequests.TotalCount = len(mr.Reviewers)
+	for range mr.Reviewers {
+		pr.ReviewRequests.Nodes = append(pr.ReviewRequests.Nodes, ReviewRequest{})
+	}
+
+	if details.approvals != nil {
+		for _, approver := range details.approvals.ApprovedBy {
+			var review Review
+			review.Author.Login = approver.User.Username
+			review.State = "APPROVED"
+			pr.Reviews.Nodes = append(pr.Reviews.Nodes, review)
+		}
+		pr.Reviews.TotalCount = len(pr.Reviews.Nodes)
+		pr.ReviewDecision = gitLabReviewDecision(*details.approvals)
+	}
+
+	if details.pipeline != nil {
+		var node CommitNode
+		node.Commit.StatusCheckRollup.Contexts.TotalCount = 1
+		node.Commit.StatusCheckRollup.Contexts.Nodes = []CheckContext{{
+			Typename: "CheckRun",
+			CheckRun: gitLabPipelineCheckRun(*details.pipeline),
+		}}
+		pr.Commits = Commits{Nodes: []CommitNode{node}, TotalCount: 1}
+	}
+
+	return pr
+}
+
+func (p *GitLabProvider) convertIssueToData(issue GitLabIssue) IssueData {
+	state := "OPEN"
+	if issue.State == "closed" {
+		state = "CLOSED"
+	}
+
+	data := IssueData{
+		Number:            issue.Iid,
+		Title:             issue.Title,
+		Body:              issue.Description,
+		State:             state,
+		AuthorAssociation: "MEMBER",
+		UpdatedAt:         issue.UpdatedAt,
+		CreatedAt:         issue.CreatedAt,
+		Url:               issue.WebURL,
+		Repository:        gitLabRepository(p.projectPathFromWebURL(issue.WebURL)),
+		Assignees:         convertGitLabAssignees(issue.Assignees),
+		Comments:          IssueComments{TotalCount: issue.UserNotesCount},
+		Reactions:         IssueReactions{TotalCount: issue.Upvotes},
+	}
+	data.Author.Login = issue.Author.Username
+	for _, label := range issue.Labels {
+		data.Labels.Nodes = append(data.Labels.Nodes, Label{Name: label.Name, Color: strings.TrimPrefix(label.Color, "#")})
+	}
+
+	return data
+}
+
+func convertGitLabAssignees(users []GitLabUser) Assignees {
+	assignees := Assignees{TotalCount: len(users)}
+	for _, user := range users {
+		assignees.Nodes = append(assignees.Nodes, Assignee{Login: user.Username})
+	}
+	return assignees
+}
+
+func convertGitLabDiffs(diffs []GitLabDiff) ChangedFiles {
+	files := ChangedFiles{TotalCount: len(diffs)}
+	for _, diff := range diffs {
+		file := ChangedFile{Path: diff.NewPath, ChangeType: "MODIFIED"}
+		switch {
+		case diff.NewFile:
+			file.ChangeType = "ADDED"
+		case diff.DeletedFile:
+			file.ChangeType = "DELETED"
+			file.Path = diff.OldPath
+		case diff.RenamedFile:
+			file.ChangeType = "RENAMED"
+		}
+		for _, line := range strings.Split(diff.Diff, "\n") {
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+				file.Additions++
+			} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
+				file.Deletions++
+			}
+		}
+		files.Nodes = append(files.Nodes, file)
+	}
+	return files
+}
+
+func gitLabReviewDecision(approvals GitLabApprovals) string {
+	if approvals.ApprovalsRequired > 0 {
+		if approvals.Approved && approvals.ApprovalsLeft == 0 {
+			return "APPROVED"
+		}
+		return "REVIEW_REQUIRED"
+	}
+	if len(approvals.ApprovedBy) > 0 {
+		return "APPROVED"
+	}
+	return ""
+}
+
+// gitLabMergeStateStatus maps GitLab's detailed_merge_status onto GitHub's MergeStateStatus values
+func gitLabMergeStateStatus(status string) string {
+	switch status {
+	case "mergeable":
+		return "CLEAN"
+	case "need_rebase":
+		return "BEHIND"
+	case "broken_status", "conflict":
+		return "DIRTY"
+	case "draft_status":
+		return "DRAFT"
+	case "ci_still_running":
+		return "UNSTABLE"
+	case "not_approved", "blocked_status", "discussions_not_resolved", "ci_must_pass",
+		"not_open", "external_status_checks", "jira_association_missing", "requested_changes":
+		return "BLOCKED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// gitLabPipelineCheckRun maps a GitLab pipeline onto a GitHub style check run
+func gitLabPipelineCheckRun(pipeline GitLabPipeline) CheckRun {
+	checkRun := CheckRun{Name: fmt.Sprintf("pipeline #%d", pipeline.Id)}
+	checkRun.CheckSuite.Creator.Login = "gitlab-ci"
+	checkRun.CheckSuite.WorkflowRun.Workflow.Name = pipeline.Source
+
+	switch pipeline.Status {
+	case "created", "waiting_for_resource", "preparing", "pending", "scheduled":
+		checkRun.Status = "QUEUED"
+	case "running":
+		checkRun.Status = "IN_PROGRESS"
+	case "manual":
+		checkRun.Status = "WAITING"
+	default:
+		checkRun.Status = "COMPLETED"
+	}
+
+	switch pipeline.Status {
+	case "success":
+		checkRun.Conclusion = "SUCCESS"
+	case "failed":
+		checkRun.Conclusion = "FAILURE"
+	case "canceled":
+		checkRun.Conclusion = "CANCELLED"
+	case "skipped":
+		checkRun.Conclusion = "SKIPPED"
+	}
+
+	return checkRun
+}
+
+func (p *GitLabProvider) CloseIssue(number int, repoNameWithOwner string) error {
+	return p.updateIssueState(number, repoNameWithOwner, "close")
+}
+
+func (p *GitLabProvider) ReopenIssue(number int, repoNameWithOwner string) error {
+	return p.updateIssueState(number, repoNameWithOwner, "reopen")
+}
+
+// updateIssueState closes or reopens an issue with the close or reopen state event
+func (p *GitLabProvider) updateIssueState(number int, repoNameWithOwner string, stateEvent string) error {
+	log.Debug("Updating GitLab issue state", "repo", repoNameWithOwner, "number", number, "stateEvent", stateEvent)
+	apiPath := fmt.Sprintf("/projects/%s/issues/%d", url.PathEscape(repoNameWithOwner), number)
+	ctx, cancel := actionContext()
+	defer cancel()
+	_, err := p.do(ctx, "PUT", apiPath, nil, map[string]string{"state_event": stateEvent}, nil)
+	return err
+}
+
+// repoArg returns the value for glab's -R flag, which needs the full URL
+// when the project doesn't live on gitlab.com
+func (p *GitLabProvider) repoArg(repoNameWithOwner string) string {
+	if p.baseURL == defaultGitLabBaseURL {
+		return repoNameWithOwner
+	}
+	return fmt.Sprintf("%s/%s", p.baseURL, repoNameWithOwner)
+}
+
+// Command operations for GitLab merge requests using the glab CLI
+func (p *GitLabProvider) GetDiffCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "diff", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "checkout", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetMergeCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "merge", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner), "--yes"}, nil
+}
+
+func (p *GitLabProvider) GetCloseCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "close", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetReopenCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "reopen", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetReadyCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "update", fmt.Sprint(prNumber), "--ready", "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return []string{"glab", "mr", "rebase", fmt.Sprint(prNumber), "-R", p.repoArg(repoNameWithOwner)}, nil
+}
+
+func (p *GitLabProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	// glab can only watch pipelines of a branch, so open the merge request pipelines page instead
+	return []string{"open", fmt.Sprintf("%s/%s/-/merge_requests/%d/pipelines",
+		p.baseURL, repoNameWithOwner, prNumber)}, nil
+}
@@ -0,0 +1,243 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func newGitLabServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+
+	server.handle("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		server.writeJSON(w, map[string]string{"username": "me"})
+	})
+	server.handle("/api/v4/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "opened", q.Get("state"))
+		assert.Equal(t, "me", q.Get("author_username"))
+		assert.Equal(t, "bug", q.Get("labels"))
+		assert.Equal(t, "all", q.Get("scope"))
+		w.Header().Set("X-Total", "3")
+		w.Header().Set("X-Next-Page", "2")
+		server.writeJSON(w, []map[string]interface{}{{
+			"iid":                   12,
+			"project_id":            7,
+			"title":                 "Fix the thing",
+			"state":                 "opened",
+			"draft":                 true,
+			"author":                map[string]string{"username": "me"},
+			"assignees":             []map[string]string{{"username": "alice"}},
+			"reviewers":             []map[string]string{{"username": "bob"}},
+			"labels":                []map[string]string{{"name": "bug", "color": "#ff0000"}},
+			"source_branch":         "fix",
+			"target_branch":         "main",
+			"created_at":            "2024-01-01T10:00:00Z",
+			"updated_at":            "2024-01-02T10:00:00Z",
+			"web_url":               server.URL + "/group/sub/repo/-/merge_requests/12",
+			"user_notes_count":      4,
+			"detailed_merge_status": "not_approved",
+		}})
+	})
+	server.handle("/api/v4/projects/7/merge_requests/12/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, []map[string]interface{}{{"id": 99, "status": "failed", "source": "push"}})
+	})
+	server.handle("/api/v4/projects/7/merge_requests/12/approvals", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{
+			"approved":           false,
+			"approvals_required": 2,
+			"approvals_left":     1,
+			"approved_by":        []map[string]interface{}{{"user": map[string]string{"username": "bob"}}},
+		})
+	})
+	server.handle("/api/v4/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "me", r.URL.Query().Get("assignee_username"))
+		server.writeJSON(w, []map[string]interface{}{{
+			"iid":              3,
+			"project_id":       7,
+			"title":            "It crashes",
+			"state":            "closed",
+			"author":           map[string]string{"username": "carol"},
+			"labels":           []map[string]string{{"name": "crash", "color": "#00ff00"}},
+			"created_at":       "2024-01-01T10:00:00Z",
+			"updated_at":       "2024-01-03T10:00:00Z",
+			"web_url":          server.URL + "/group/repo/-/issues/3",
+			"user_notes_count": 2,
+			"upvotes":          5,
+		}})
+	})
+
+	return server
+}
+
+func TestGitLabFetchPullRequests(t *testing.T) {
+	server := newGitLabServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	res, err := provider.FetchPullRequests(context.Background(), "is:open author:@me label:bug", 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Equal(t, "2", res.PageInfo.EndCursor)
+	require.Len(t, res.Prs, 1)
+
+	pr := res.Prs[0]
+	require.Equal(t, 12, pr.Number)
+	require.Equal(t, "OPEN", pr.State)
+	require.True(t, pr.IsDraft)
+	require.Equal(t, "me", pr.Author.Login)
+	require.Equal(t, "REVIEW_REQUIRED", pr.ReviewDecision)
+	require.Equal(t, "BLOCKED", pr.MergeStateStatus)
+	require.Equal(t, []providers.Assignee{{Login: "alice"}}, pr.Assignees.Nodes)
+	require.Equal(t, []providers.Label{{Name: "bug", Color: "ff0000"}}, pr.Labels.Nodes)
+	require.Len(t, pr.Reviews.Nodes, 1)
+	require.Equal(t, "APPROVED", pr.Reviews.Nodes[0].State)
+	require.Equal(t, 1, pr.ReviewRequests.TotalCount)
+	require.Len(t, pr.Commits.Nodes, 1)
+	check := pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes[0]
+	require.Equal(t, "CheckRun", check.Typename)
+	require.Equal(t, "FAILURE", check.CheckRun.Conclusion)
+}
+
+func TestGitLabFetchPullRequestsCancelled(t *testing.T) {
+	// The server hangs until the request is cancelled
+	server := newFakeServer(t)
+	server.handle("/", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := provider.FetchPullRequests(ctx, "is:open author:alice", 20, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.FetchIssues(ctx, "is:open author:alice", 20, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGitLabFetchPullRequestsBoundsDetailRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := newFakeServer(t)
+	server.handle("/api/v4/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		mrs := make([]map[string]int, 20)
+		for i := range mrs {
+			mrs[i] = map[string]int{"iid": i + 1, "project_id": 7}
+		}
+		server.writeJSON(w, mrs)
+	})
+	server.handle("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		server.writeJSON(w, []interface{}{})
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	res, err := provider.FetchPullRequests(context.Background(), "is:open author:alice", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Prs, 20)
+	require.LessOrEqual(t, maxInFlight.Load(), int32(4))
+}
+
+func TestGitLabFetchIssues(t *testing.T) {
+	server := newGitLabServer(t)
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	res, err := provider.FetchIssues(context.Background(), "assignee:@me -author:bob milestone:v1", 20, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"GitLab ignored unsupported filters: -author:bob, milestone:v1"}, res.Warnings)
+	require.False(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Issues, 1)
+
+	issue := res.Issues[0]
+	require.Equal(t, 3, issue.Number)
+	require.Equal(t, "CLOSED", issue.State)
+	require.Equal(t, "carol", issue.Author.Login)
+	require.Equal(t, 2, issue.Comments.TotalCount)
+	require.Equal(t, 5, issue.Reactions.TotalCount)
+	require.Equal(t, []providers.Label{{Name: "crash", Color: "00ff00"}}, issue.Labels.Nodes)
+}
+
+func TestGitLabIssueActions(t *testing.T) {
+	server := newFakeServer(t)
+	server.handle("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fsub%2Frepo/issues/3", r.URL.EscapedPath())
+		server.writeJSON(w, map[string]interface{}{"iid": 3})
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+	actions, ok := provider.(providers.IssueActions)
+	require.True(t, ok)
+
+	require.NoError(t, actions.CloseIssue(3, "group/sub/repo"))
+	require.NoError(t, actions.ReopenIssue(3, "group/sub/repo"))
+
+	requests := server.requests("PUT", "/api/v4/projects/")
+	require.Len(t, requests, 2)
+	require.JSONEq(t, `{"state_event": "close"}`, string(requests[0].Body))
+	require.JSONEq(t, `{"state_event": "reopen"}`, string(requests[1].Body))
+}
+
+func TestParseGitLabRemoteURL(t *testing.T) {
+	providers.RegisterHost("code.example.com", providers.GitLab)
+	t.Cleanup(func() { providers.UnregisterHost("code.example.com") })
+
+	testCases := map[string]struct {
+		remote string
+		want   providers.RemoteInfo
+	}{
+		"gitlab.com https": {
+			remote: "https://gitlab.com/group/repo.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitLab,
+				Organization: "group",
+				Repository:   "repo",
+				BaseURL:      "https://gitlab.com",
+			},
+		},
+		"gitlab.com ssh with subgroup": {
+			remote: "git@gitlab.com:group/sub/repo.git",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitLab,
+				Organization: "group/sub",
+				Repository:   "repo",
+				BaseURL:      "https://gitlab.com",
+			},
+		},
+		"registered self-hosted host": {
+			remote: "https://code.example.com/team/repo",
+			want: providers.RemoteInfo{
+				Provider:     providers.GitLab,
+				Organization: "team",
+				Repository:   "repo",
+				BaseURL:      "https://code.example.com",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providers.ParseGitRemoteURL(tc.remote)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, *got)
+			require.Equal(t, providers.GitLab, providers.DetectProviderFromURL(tc.remote))
+		})
+	}
+}
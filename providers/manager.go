@@ -1,8 +1,10 @@
 package providers
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/log"
 
@@ -13,34 +15,60 @@ import (
 type ProviderManager struct {
 	providers map[string]GitProvider
 	current   GitProvider
+	// configs are the configs of the providers, by profile name and under "" for
+	// the current provider
+	configs map[string]ProviderConfig
+	// currentErr and profileErrs are why the current provider and the profiles
+	// failed to initialize. They are returned to the sections using them
+	currentErr  error
+	profileErrs map[string]error
+	// hosts are the hostnames registered from the config, which are forgotten
+	// when the providers are initialized again
+	hosts []string
 }
 
 func NewProviderManager() *ProviderManager {
 	return &ProviderManager{
-		providers: make(map[string]GitProvider),
+		providers:   make(map[string]GitProvider),
+		configs:     make(map[string]ProviderConfig),
+		profileErrs: make(map[string]error),
 	}
 }
 
-// InitializeProvider initializes the appropriate provider based on config and git remote
+// InitializeProvider initializes the appropriate provider based on config and git
+// remote. It returns the errors of the config, while providers that fail to
+// initialize report their error when they are used, instead of being replaced by GitHub
 func (pm *ProviderManager) InitializeProvider(cfg *config.Config, repoPath string) error {
 	var providerConfig ProviderConfig
+	var errs []error
 
 	log.Debug("Initializing provider", "repoPath", repoPath)
+	pm.current, pm.currentErr = nil, nil
+	clear(pm.providers)
+	clear(pm.configs)
+	clear(pm.profileErrs)
+	for _, host := range pm.hosts {
+		UnregisterHost(host)
+	}
+	pm.hosts = nil
+
+	// Hosts are registered first, so they apply to the detection from the remote
+	for host, providerType := range cfg.Hosts {
+		if !isKnownProviderType(ProviderType(providerType)) {
+			errs = append(errs, fmt.Errorf("unknown provider type %q for host %s", providerType, host))
+			continue
+		}
+		pm.registerHost(host, ProviderType(providerType))
+	}
 
 	// If provider is explicitly configured, use that
 	if cfg.Provider != nil {
 		log.Debug("Using explicit provider configuration", "type", cfg.Provider.Type)
-		providerConfig = ProviderConfig{
-			Type:         ProviderType(cfg.Provider.Type),
-			Organization: cfg.Provider.Organization,
-			Project:      cfg.Provider.Project,
-			BaseURL:      cfg.Provider.BaseURL,
-			Token:        cfg.Provider.Token,
-		}
+		providerConfig = pm.newProviderConfig(*cfg.Provider)
 	} else {
 		// Auto-detect provider from git remote
 		log.Debug("Auto-detecting provider from git remote")
-		detectedConfig, err := pm.detectProviderFromGit(repoPath)
+		detectedConfig, err := pm.detectProviderFromGit(repoPath, cfg.Repo.Remotes)
 		if err != nil {
 			log.Debug("Failed to detect provider from git remote", "error", err)
 			// Fall back to GitHub as default
@@ -53,23 +81,86 @@ func (pm *ProviderManager) InitializeProvider(cfg *config.Config, repoPath strin
 		}
 	}
 
-	// Add token from environment if not set in config
+	if provider, err := pm.newProvider(providerConfig); err != nil {
+		log.Error("Failed to initialize provider", "type", providerConfig.Type, "error", err)
+		pm.current, pm.currentErr = nil, err
+	} else {
+		pm.current = provider
+		pm.configs[""] = providerConfig
+		log.Debug("Initialized provider", "type", providerConfig.Type, "organization", providerConfig.Organization)
+	}
+
+	// Named profiles are initialized next to the current provider, so that
+	// sections of different providers can be shown side by side
+	for name, profile := range cfg.Providers {
+		profileConfig := pm.newProviderConfig(profile)
+		provider, err := pm.newProvider(profileConfig)
+		if err != nil {
+			log.Error("Failed to initialize provider profile", "name", name, "error", err)
+			pm.profileErrs[name] = fmt.Errorf("provider %q: %w", name, err)
+			continue
+		}
+		pm.providers[name] = provider
+		pm.configs[name] = profileConfig
+		log.Debug("Initialized provider profile", "name", name, "type", profile.Type)
+	}
+	return errors.Join(errs...)
+}
+
+// newProviderConfig converts a provider from the config file
+func (pm *ProviderManager) newProviderConfig(cfg config.ProviderConfig) ProviderConfig {
+	providerConfig := ProviderConfig{
+		Type:          ProviderType(cfg.Type),
+		Organization:  cfg.Organization,
+		Project:       cfg.Project,
+		Repository:    cfg.Repository,
+		BaseURL:       cfg.BaseURL,
+		Token:         cfg.Token,
+		TokenCommand:  cfg.TokenCommand,
+		TokenFile:     cfg.TokenFile,
+		GitCredential: cfg.GitCredential,
+		MergeStrategy: cfg.MergeStrategy,
+		Command:       cfg.Command,
+	}
+	if providerConfig.Token != "" {
+		providerConfig.TokenSource = "config"
+	}
+	if providerConfig.BaseURL != "" {
+		pm.registerHost(hostFromURL(providerConfig.BaseURL), providerConfig.Type)
+	}
+	return providerConfig
+}
+
+// registerHost registers a hostname of the config and remembers it, so that it
+// is unregistered when the providers are initialized again
+func (pm *ProviderManager) registerHost(host string, providerType ProviderType) {
+	if host == "" {
+		return
+	}
+	RegisterHost(host, providerType)
+	pm.hosts = append(pm.hosts, host)
+}
+
+func (pm *ProviderManager) newProvider(providerConfig ProviderConfig) (GitProvider, error) {
+	// Read the token from the configured credential sources, or else from
+	// the environment, if it isn't set in config
+	if providerConfig.Token == "" {
+		token, source, err := ResolveToken(providerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s token: %w", providerConfig.Type, err)
+		}
+		providerConfig.Token, providerConfig.TokenSource = token, source
+	}
 	if providerConfig.Token == "" {
-		providerConfig.Token = pm.getTokenFromEnvironment(providerConfig.Type)
+		providerConfig.Token, providerConfig.TokenSource = pm.getTokenFromEnvironment(providerConfig.Type)
 	}
+	RegisterSecret(providerConfig.Token)
 
-	// Initialize the provider
 	provider, err := NewProvider(providerConfig)
 	if err != nil {
-		return fmt.Errorf("failed to initialize %s provider: %w", providerConfig.Type, err)
+		return nil, fmt.Errorf("failed to initialize %s provider: %w", providerConfig.Type, err)
 	}
-
-	key := string(providerConfig.Type)
-	pm.providers[key] = provider
-	pm.current = provider
-
-	log.Debug("Initialized provider", "type", providerConfig.Type, "organization", providerConfig.Organization)
-	return nil
+	return provider, nil
 }
 
 // GetCurrentProvider returns the currently active provider
@@ -77,14 +168,59 @@ func (pm *ProviderManager) GetCurrentProvider() GitProvider {
 	return pm.current
 }
 
-// detectProviderFromGit detects the provider type from git remote URL
-func (pm *ProviderManager) detectProviderFromGit(repoPath string) (*ProviderConfig, error) {
+// GetProvider returns the provider of a named profile, or the current provider
+// when the name is empty, along with why it failed to initialize
+func (pm *ProviderManager) GetProvider(name string) (GitProvider, error) {
+	if name == "" {
+		return pm.current, pm.currentErr
+	}
+	if err, ok := pm.profileErrs[name]; ok {
+		return nil, err
+	}
+	provider, ok := pm.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %q isn't configured under providers", name)
+	}
+	return provider, nil
+}
+
+// Account identifies the account of the provider of a named profile, or of the
+// current provider when the name is empty, by the provider type, base URL,
+// organization and login. Unlike the profile name, it tells apart the providers
+// detected in different clones and the users signed in to them
+func (pm *ProviderManager) Account(name string) (string, error) {
+	provider, err := pm.GetProvider(name)
+	if err != nil {
+		return "", err
+	}
+	if provider == nil {
+		return "", fmt.Errorf("no provider initialized")
+	}
+	authInfo, err := provider.GetAuthInfo()
+	if err != nil {
+		return "", err
+	}
+	if authInfo.Username == "" {
+		return "", fmt.Errorf("no user is signed in to %s", provider.GetType())
+	}
+	config := pm.configs[name]
+	return strings.Join([]string{string(provider.GetType()), config.BaseURL, config.Organization, authInfo.Username}, "\n"), nil
+}
+
+// MergeStrategy returns the merge strategy configured for the provider of a
+// named profile, or of the current provider when the name is empty
+func (pm *ProviderManager) MergeStrategy(name string) string {
+	return pm.configs[name].MergeStrategy
+}
+
+// detectProviderFromGit detects the provider type from the URL of the preferred git remote
+func (pm *ProviderManager) detectProviderFromGit(repoPath string, remotes []string) (*ProviderConfig, error) {
 	if repoPath == "" {
 		repoPath = "."
 	}
 
 	log.Debug("Getting git remote URL", "path", repoPath)
-	remoteURL, err := git.GetOriginUrl(repoPath)
+	remoteURL, err := git.GetRemoteUrl(repoPath, remotes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get git remote URL: %w", err)
 	}
@@ -100,34 +236,72 @@ func (pm *ProviderManager) detectProviderFromGit(repoPath string) (*ProviderConf
 	return config, nil
 }
 
-// getTokenFromEnvironment gets authentication token from environment variables
-func (pm *ProviderManager) getTokenFromEnvironment(providerType ProviderType) string {
+// getTokenFromEnvironment gets authentication token from environment variables,
+// along with the name of the variable it was found in
+func (pm *ProviderManager) getTokenFromEnvironment(providerType ProviderType) (string, string) {
 	switch providerType {
 	case GitHub:
 		// GitHub CLI handles authentication automatically
-		return ""
+		return "", ""
 	case AzureDevOps:
 		// Look for Azure DevOps Personal Access Token
 		log.Debug("Looking for Azure DevOps token in environment variables")
 		if token := os.Getenv("AZURE_DEVOPS_TOKEN"); token != "" {
 			log.Debug("Found AZURE_DEVOPS_TOKEN")
-			return token
+			return token, "AZURE_DEVOPS_TOKEN"
 		}
 		if token := os.Getenv("ADO_PAT"); token != "" {
 			log.Debug("Found ADO_PAT")
-			return token
+			return token, "ADO_PAT"
 		}
 		if token := os.Getenv("AZURE_PAT"); token != "" {
 			log.Debug("Found AZURE_PAT")
-			return token
+			return token, "AZURE_PAT"
 		}
 		log.Debug("No Azure DevOps token found in environment variables")
+	case GitLab:
+		// Same variables the glab CLI reads
+		log.Debug("Looking for GitLab token in environment variables")
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+			log.Debug("Found GITLAB_TOKEN")
+			return token, "GITLAB_TOKEN"
+		}
+		if token := os.Getenv("GITLAB_ACCESS_TOKEN"); token != "" {
+			log.Debug("Found GITLAB_ACCESS_TOKEN")
+			return token, "GITLAB_ACCESS_TOKEN"
+		}
+		log.Debug("No GitLab token found in environment variables")
+	case Gitea, Forgejo:
+		log.Debug("Looking for Gitea token in environment variables")
+		for _, name := range []string{"GITEA_TOKEN", "FORGEJO_TOKEN", "GITEA_SERVER_TOKEN"} {
+			if token := os.Getenv(name); token != "" {
+				log.Debug("Found " + name)
+				return token, name
+			}
+		}
+		log.Debug("No Gitea token found in environment variables")
+	case Bitbucket, BitbucketServer:
+		log.Debug("Looking for Bitbucket token in environment variables")
+		if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+			log.Debug("Found BITBUCKET_TOKEN")
+			return token, "BITBUCKET_TOKEN"
+		}
+		// App passwords are used together with the username through basic auth
+		username, appPassword := os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD")
+		if username != "" && appPassword != "" {
+			log.Debug("Found BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+			return username + ":" + appPassword, "BITBUCKET_APP_PASSWORD"
+		}
+		log.Debug("No Bitbucket token found in environment variables")
 	}
-	return ""
+	return "", ""
 }
 
 // GetProviderInfo returns information about the current provider
 func (pm *ProviderManager) GetProviderInfo() (ProviderType, AuthInfo, error) {
+	if pm.currentErr != nil {
+		return "", AuthInfo{}, pm.currentErr
+	}
 	if pm.current == nil {
 		return "", AuthInfo{}, fmt.Errorf("no provider initialized")
 	}
@@ -0,0 +1,136 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func TestProviderManagerProfiles(t *testing.T) {
+	pm := providers.NewProviderManager()
+	err := pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "azure-devops", Organization: "org", Project: "proj", Token: "secret"},
+		Providers: map[string]config.ProviderConfig{
+			"oss": {Type: "gitlab", BaseURL: "https://gitlab.example.com", Token: "secret"},
+		},
+	}, "")
+	require.NoError(t, err)
+
+	current, err := pm.GetProvider("")
+	require.NoError(t, err)
+	require.Equal(t, providers.AzureDevOps, current.GetType())
+
+	oss, err := pm.GetProvider("oss")
+	require.NoError(t, err)
+	require.Equal(t, providers.GitLab, oss.GetType())
+
+	_, err = pm.GetProvider("missing")
+	require.Error(t, err)
+}
+
+func TestProviderManagerFailedProviders(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing-plugin")
+	pm := providers.NewProviderManager()
+	err := pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "plugin", Command: missing},
+		Providers: map[string]config.ProviderConfig{
+			"broken": {Type: "plugin", Command: missing},
+		},
+	}, "")
+	require.NoError(t, err)
+
+	// The sections of failed providers show their error rather than GitHub's results
+	current, err := pm.GetProvider("")
+	require.ErrorContains(t, err, "failed to initialize plugin provider")
+	require.Nil(t, current)
+
+	_, err = pm.GetProvider("broken")
+	require.ErrorContains(t, err, `provider "broken": failed to initialize plugin provider`)
+
+	_, _, err = pm.GetProviderInfo()
+	require.Error(t, err)
+}
+
+func TestProviderManagerReinitialize(t *testing.T) {
+	pm := providers.NewProviderManager()
+	err := pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "gitlab", Token: "secret"},
+		Hosts:    map[string]string{"code.reinit.example.com": "gitlab"},
+		Providers: map[string]config.ProviderConfig{
+			"oss": {Type: "gitea", BaseURL: "https://forge.reinit.example.com", Token: "secret"},
+		},
+	}, "")
+	require.NoError(t, err)
+	info, err := providers.ParseGitRemoteURL("https://code.reinit.example.com/team/app.git")
+	require.NoError(t, err)
+	require.Equal(t, providers.GitLab, info.Provider)
+
+	// The profiles and hosts of the previous config don't outlive it
+	err = pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "gitlab", Token: "secret"},
+	}, "")
+	require.NoError(t, err)
+	_, err = pm.GetProvider("oss")
+	require.ErrorContains(t, err, `provider "oss" isn't configured`)
+	for _, remote := range []string{"https://code.reinit.example.com/team/app.git", "https://forge.reinit.example.com/team/app.git"} {
+		info, err = providers.ParseGitRemoteURL(remote)
+		require.NoError(t, err)
+		require.Equal(t, providers.GitHub, info.Provider, remote)
+	}
+}
+
+func TestProviderManagerUnknownHostType(t *testing.T) {
+	pm := providers.NewProviderManager()
+	err := pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "gitlab", Token: "secret"},
+		Hosts:    map[string]string{"code.example.com": "subversion"},
+	}, "")
+	require.ErrorContains(t, err, `unknown provider type "subversion" for host code.example.com`)
+}
+
+func TestProviderManagerRoutesSectionsToTheirProfile(t *testing.T) {
+	gitLab := newGitLabServer(t)
+	forge := newGiteaServer(t)
+	forge.handle("PATCH /api/v1/repos/infra/tools/issues/4", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	pm := providers.NewProviderManager()
+	err := pm.InitializeProvider(&config.Config{
+		Provider: &config.ProviderConfig{Type: "gitlab", BaseURL: gitLab.URL, Token: "secret"},
+		Providers: map[string]config.ProviderConfig{
+			"forge": {Type: "forgejo", BaseURL: forge.URL, Token: "secret"},
+		},
+	}, "")
+	require.NoError(t, err)
+
+	prs := config.PrsSectionConfig{Title: "Mine", Filters: "is:open author:@me label:bug"}
+	issues := config.IssuesSectionConfig{Title: "Forge", Filters: "author:@me", Provider: "forge"}
+
+	current, err := pm.GetProvider(prs.Provider)
+	require.NoError(t, err)
+	res, err := current.FetchPullRequests(context.Background(), prs.Filters, 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Prs, 1)
+
+	profile, err := pm.GetProvider(issues.Provider)
+	require.NoError(t, err)
+	_, err = profile.FetchIssues(context.Background(), issues.Filters, 20, nil)
+	require.NoError(t, err)
+	require.NoError(t, profile.(providers.IssueActions).CloseIssue(4, "infra/tools"))
+
+	require.NotEmpty(t, gitLab.requests("GET", "/api/v4/merge_requests"))
+	require.Empty(t, gitLab.requests("", "/api/v4/issues"))
+	require.NotEmpty(t, forge.requests("GET", "/api/v1/repos/issues/search"))
+	require.Len(t, forge.requests("PATCH", "/api/v1/repos/infra/tools/issues/4"), 1)
+	require.Empty(t, forge.requests("", "/api/v1/repos/infra/tools/pulls"))
+
+	_, err = pm.GetProvider(config.PrsSectionConfig{Provider: "missing"}.Provider)
+	require.ErrorContains(t, err, `provider "missing" isn't configured under providers`)
+}
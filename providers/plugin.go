@@ -0,0 +1,530 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Plugin providers are executables that speak JSON over stdin and stdout, one
+// message per line. gh-dash writes requests and the plugin answers each with a
+// response carrying the same id, in any order:
+//
+//	{"id": 1, "method": "listPullRequests", "params": {"query": "is:open", "limit": 20}}
+//	{"id": 1, "result": {"prs": [...], "totalCount": 42, "pageInfo": {...}}}
+//	{"id": 2, "error": {"message": "not found"}}
+//
+// The methods are initialize, listPullRequests, listIssues, getPullRequest and
+// action. cancel is a notification without a response, telling the plugin that
+// gh-dash stopped waiting for a request. The plugin should exit once its stdin
+// is closed. See providers/README.md and the reference plugin in
+// examples/fake-plugin
+const pluginProtocolVersion = 1
+
+const (
+	// maxPluginMessageSize is the longest line a plugin can answer with
+	maxPluginMessageSize = 64 * 1024 * 1024
+	// pluginStopTimeout is how long a plugin has to exit once its stdin is
+	// closed, before it is killed
+	pluginStopTimeout = 5 * time.Second
+)
+
+type pluginRequest struct {
+	Id     int         `json:"id,omitempty"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+type pluginResponse struct {
+	Id     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type pluginInitializeParams struct {
+	ProtocolVersion int    `json:"protocolVersion"`
+	Organization    string `json:"organization,omitempty"`
+	Project         string `json:"project,omitempty"`
+	Repository      string `json:"repository,omitempty"`
+	BaseURL         string `json:"baseUrl,omitempty"`
+	Token           string `json:"token,omitempty"`
+	MergeStrategy   string `json:"mergeStrategy,omitempty"`
+}
+
+// pluginInfo is the result of initialize, describing what the plugin supports
+type pluginInfo struct {
+	Name               string       `json:"name"`
+	Username           string       `json:"username"`
+	PullRequests       bool         `json:"pullRequests"`
+	Issues             bool         `json:"issues"`
+	Capabilities       Capabilities `json:"capabilities"`
+	ExpandsMeQualifier bool         `json:"expandsMeQualifier"`
+	// Commands are the command lines run for diff, checkout, merge, close,
+	// reopen, ready, update and watchChecks. {number} and {repo} in their
+	// arguments are replaced by the pull request
+	Commands map[string][]string `json:"commands"`
+}
+
+type pluginListParams struct {
+	Query  string `json:"query"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type pluginGetParams struct {
+	Url string `json:"url"`
+}
+
+// pluginActionParams asks the plugin to approve, merge, close, reopen, ready,
+// updateBranch, comment, assign or unassign
+type pluginActionParams struct {
+	Action string   `json:"action"`
+	Number int      `json:"number"`
+	Repo   string   `json:"repo"`
+	Body   string   `json:"body,omitempty"`
+	Logins []string `json:"logins,omitempty"`
+}
+
+// PluginProvider adapts a plugin executable to GitProvider. The plugin is
+// launched when the provider is created, and again when it has exited
+type PluginProvider struct {
+	config ProviderConfig
+
+	mu      sync.Mutex
+	process *pluginProcess
+	info    pluginInfo
+}
+
+func NewPluginProvider(config ProviderConfig) (GitProvider, error) {
+	if config.Command == "" {
+		return nil, fmt.Errorf("the plugin provider requires a command")
+	}
+	p := &PluginProvider{config: config}
+	if _, err := p.start(); err != nil {
+		return nil, err
+	}
+	log.Debug("Created plugin provider", "command", config.Command, "name", p.info.Name)
+	return p, nil
+}
+
+// start returns the running plugin process, launching and initializing it
+// when it isn't running
+func (p *PluginProvider) start() (*pluginProcess, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.process != nil && !p.process.exited() {
+		return p.process, nil
+	}
+
+	process, err := startPluginProcess(p.config.Command)
+	if err != nil {
+		return nil, err
+	}
+	params := pluginInitializeParams{
+		ProtocolVersion: pluginProtocolVersion,
+		Organization:    p.config.Organization,
+		Project:         p.config.Project,
+		Repository:      p.config.Repository,
+		BaseURL:         p.config.BaseURL,
+		Token:           p.config.Token,
+		MergeStrategy:   p.config.MergeStrategy,
+	}
+	ctx, cancel := actionContext()
+	defer cancel()
+	var info pluginInfo
+	if err := process.call(ctx, "initialize", params, &info); err != nil {
+		process.stop()
+		return nil, fmt.Errorf("failed to initialize plugin %q: %w", p.config.Command, err)
+	}
+	if info.Name == "" {
+		info.Name = "plugin"
+	}
+	p.process, p.info = process, info
+	return process, nil
+}
+
+// Close stops the plugin, killing it when it doesn't exit in time. A later
+// request launches it again
+func (p *PluginProvider) Close() error {
+	p.mu.Lock()
+	process := p.process
+	p.process = nil
+	p.mu.Unlock()
+	if process == nil {
+		return nil
+	}
+
+	process.stop()
+	select {
+	case <-process.done:
+		return nil
+	case <-time.After(pluginStopTimeout):
+		log.Debug("Killing plugin that didn't exit", "command", p.config.Command)
+		err := process.cmd.Process.Kill()
+		<-process.done
+		return fmt.Errorf("plugin %q didn't exit in time and was killed: %w", p.config.Command, err)
+	}
+}
+
+func (p *PluginProvider) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	process, err := p.start()
+	if err != nil {
+		return err
+	}
+	if err := process.call(ctx, method, params, result); err != nil {
+		return fmt.Errorf("%s: %w", p.pluginInfo().Name, err)
+	}
+	return nil
+}
+
+func (p *PluginProvider) pluginInfo() pluginInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info
+}
+
+func (p *PluginProvider) GetType() ProviderType {
+	return Plugin
+}
+
+func (p *PluginProvider) SupportsPullRequests() bool {
+	return p.pluginInfo().PullRequests
+}
+
+func (p *PluginProvider) SupportsIssues() bool {
+	return p.pluginInfo().Issues
+}
+
+func (p *PluginProvider) Capabilities() Capabilities {
+	return p.pluginInfo().Capabilities
+}
+
+func (p *PluginProvider) GetAuthInfo() (AuthInfo, error) {
+	info := p.pluginInfo()
+	tokenSource := p.config.TokenSource
+	if tokenSource == "" {
+		tokenSource = info.Name
+	}
+	return AuthInfo{
+		Username:    info.Username,
+		IsLoggedIn:  info.Username != "",
+		TokenSource: tokenSource,
+	}, nil
+}
+
+func (p *PluginProvider) FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error) {
+	var res PullRequestsResponse
+	err := p.call(ctx, "listPullRequests", p.newListParams(query, limit, pageInfo), &res)
+	return res, err
+}
+
+func (p *PluginProvider) FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error) {
+	var res IssuesResponse
+	err := p.call(ctx, "listIssues", p.newListParams(query, limit, pageInfo), &res)
+	return res, err
+}
+
+func (p *PluginProvider) FetchPullRequest(ctx context.Context, url string) (PullRequestData, error) {
+	var pr PullRequestData
+	err := p.call(ctx, "getPullRequest", pluginGetParams{Url: url}, &pr)
+	return pr, err
+}
+
+// newListParams replaces @me with the username the plugin reported when it
+// asked for it, as its search doesn't understand the qualifier
+func (p *PluginProvider) newListParams(query string, limit int, pageInfo *PageInfo) pluginListParams {
+	if info := p.pluginInfo(); info.ExpandsMeQualifier {
+		query = ExpandMeQualifier(query, info.Username)
+	}
+	params := pluginListParams{Query: query, Limit: limit}
+	if pageInfo != nil {
+		params.Cursor = pageInfo.EndCursor
+	}
+	return params
+}
+
+func (p *PluginProvider) action(params pluginActionParams) error {
+	ctx, cancel := actionContext()
+	defer cancel()
+	log.Debug("Running plugin action", "action", params.Action, "number", params.Number, "repo", params.Repo)
+	return p.call(ctx, "action", params, nil)
+}
+
+func (p *PluginProvider) ApprovePullRequest(prNumber int, repoNameWithOwner string, comment string) error {
+	return p.action(pluginActionParams{Action: "approve", Number: prNumber, Repo: repoNameWithOwner, Body: comment})
+}
+
+func (p *PluginProvider) MergePullRequest(prNumber int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "merge", Number: prNumber, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) ClosePullRequest(prNumber int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "close", Number: prNumber, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) ReopenPullRequest(prNumber int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "reopen", Number: prNumber, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) MarkPullRequestReady(prNumber int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "ready", Number: prNumber, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) UpdatePullRequestBranch(prNumber int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "updateBranch", Number: prNumber, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) AddComment(number int, repoNameWithOwner string, body string) error {
+	return p.action(pluginActionParams{Action: "comment", Number: number, Repo: repoNameWithOwner, Body: body})
+}
+
+func (p *PluginProvider) AddAssignees(number int, repoNameWithOwner string, logins []string) error {
+	return p.action(pluginActionParams{Action: "assign", Number: number, Repo: repoNameWithOwner, Logins: logins})
+}
+
+func (p *PluginProvider) RemoveAssignees(number int, repoNameWithOwner string, logins []string) error {
+	return p.action(pluginActionParams{Action: "unassign", Number: number, Repo: repoNameWithOwner, Logins: logins})
+}
+
+func (p *PluginProvider) CloseIssue(number int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "closeIssue", Number: number, Repo: repoNameWithOwner})
+}
+
+func (p *PluginProvider) ReopenIssue(number int, repoNameWithOwner string) error {
+	return p.action(pluginActionParams{Action: "reopenIssue", Number: number, Repo: repoNameWithOwner})
+}
+
+// command fills in the command line the plugin declared for an operation
+func (p *PluginProvider) command(name string, prNumber int, repoNameWithOwner string) ([]string, error) {
+	info := p.pluginInfo()
+	template, ok := info.Commands[name]
+	if !ok || len(template) == 0 {
+		return nil, fmt.Errorf("the %s plugin has no %s command", info.Name, name)
+	}
+	replacer := strings.NewReplacer("{number}", strconv.Itoa(prNumber), "{repo}", repoNameWithOwner)
+	args := make([]string, len(template))
+	for i, arg := range template {
+		args[i] = replacer.Replace(arg)
+	}
+	return args, nil
+}
+
+func (p *PluginProvider) GetDiffCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("diff", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetCheckoutCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("checkout", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetMergeCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("merge", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetCloseCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("close", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetReopenCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("reopen", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetReadyCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("ready", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetUpdateCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("update", prNumber, repoNameWithOwner)
+}
+
+func (p *PluginProvider) GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error) {
+	return p.command("watchChecks", prNumber, repoNameWithOwner)
+}
+
+// pluginProcess is a running plugin. Requests are written as they come and
+// matched with their responses by id, so sections can fetch concurrently
+type pluginProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *pluginStderr
+	done   chan struct{}
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextId  int
+	pending map[int]chan pluginResponse
+	err     error
+}
+
+func startPluginProcess(command string) (*pluginProcess, error) {
+	cmd := shellCommand(command)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	process := &pluginProcess{
+		cmd:     cmd,
+		stdin:   stdin,
+		stderr:  &pluginStderr{},
+		done:    make(chan struct{}),
+		pending: map[int]chan pluginResponse{},
+	}
+	cmd.Stderr = process.stderr
+
+	log.Debug("Launching plugin", "command", command)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to launch plugin %q: %w", command, err)
+	}
+	go process.read(stdout)
+	return process, nil
+}
+
+// read delivers the responses of the plugin until it exits
+func (pp *pluginProcess) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxPluginMessageSize)
+	for scanner.Scan() {
+		var resp pluginResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			log.Debug("Ignoring invalid plugin output", "err", err, "line", scanner.Text())
+			continue
+		}
+		pp.mu.Lock()
+		ch, ok := pp.pending[resp.Id]
+		delete(pp.pending, resp.Id)
+		pp.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+
+	err := pp.cmd.Wait()
+	if scanErr := scanner.Err(); scanErr != nil {
+		err = scanErr
+	}
+	exitErr := errors.New("plugin exited")
+	if err != nil {
+		exitErr = fmt.Errorf("plugin exited: %w", err)
+	}
+	if last := pp.stderr.lastLine(); last != "" {
+		exitErr = fmt.Errorf("%w: %s", exitErr, last)
+	}
+	log.Debug("Plugin exited", "err", exitErr)
+
+	pp.mu.Lock()
+	pp.err = exitErr
+	pp.mu.Unlock()
+	close(pp.done)
+}
+
+func (pp *pluginProcess) exited() bool {
+	select {
+	case <-pp.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// call sends a request and decodes the result of its response into result,
+// unless it is nil
+func (pp *pluginProcess) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	pp.mu.Lock()
+	if pp.err != nil {
+		pp.mu.Unlock()
+		return pp.err
+	}
+	pp.nextId++
+	id := pp.nextId
+	ch := make(chan pluginResponse, 1)
+	pp.pending[id] = ch
+	pp.mu.Unlock()
+
+	if err := pp.send(pluginRequest{Id: id, Method: method, Params: params}); err != nil {
+		pp.forget(id)
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return errors.New(resp.Error.Message)
+		}
+		if result == nil || len(resp.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-pp.done:
+		pp.mu.Lock()
+		defer pp.mu.Unlock()
+		return pp.err
+	case <-ctx.Done():
+		pp.forget(id)
+		_ = pp.send(pluginRequest{Method: "cancel", Params: map[string]int{"id": id}})
+		return ctx.Err()
+	}
+}
+
+func (pp *pluginProcess) send(req pluginRequest) error {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	pp.writeMu.Lock()
+	defer pp.writeMu.Unlock()
+	_, err = pp.stdin.Write(append(line, '\n'))
+	return err
+}
+
+func (pp *pluginProcess) forget(id int) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	delete(pp.pending, id)
+}
+
+// stop closes the stdin of the plugin, which tells it to exit
+func (pp *pluginProcess) stop() {
+	pp.stdin.Close()
+}
+
+// pluginStderr logs what the plugin writes to stderr, keeping the last line
+// to explain why it exited
+type pluginStderr struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (w *pluginStderr) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			log.Debug("Plugin stderr", "line", line)
+			w.last = line
+		}
+	}
+	return len(b), nil
+}
+
+func (w *pluginStderr) lastLine() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
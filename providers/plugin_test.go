@@ -0,0 +1,246 @@
+package providers_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// pluginRelayEnv makes the test binary run as a plugin relaying every request
+// to a fake server, see runPluginRelay
+const pluginRelayEnv = "GH_DASH_TEST_PLUGIN_RELAY"
+
+// The reference plugin is built once, by the first test using it, into
+// fakePluginDir, which is removed after the tests
+var (
+	buildFakePlugin sync.Once
+	fakePluginDir   string
+	fakePluginBin   string
+	fakePluginErr   error
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(pluginRelayEnv) != "" {
+		runPluginRelay()
+		return
+	}
+	code := m.Run()
+	if fakePluginDir != "" {
+		_ = os.RemoveAll(fakePluginDir)
+	}
+	os.Exit(code)
+}
+
+// runPluginRelay is a plugin posting the params of every request to
+// {baseUrl}/{method}, where baseUrl comes from initialize, and answering with
+// the response of the server. It exits once its stdin is closed
+func runPluginRelay() {
+	var baseURL string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			Id     int             `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.Id == 0 {
+			// cancel notifications have no id
+			continue
+		}
+		if req.Method == "initialize" {
+			var params struct {
+				BaseURL string `json:"baseUrl"`
+			}
+			_ = json.Unmarshal(req.Params, &params)
+			baseURL = params.BaseURL
+		}
+
+		resp := map[string]interface{}{"id": req.Id}
+		if result, err := relayPluginRequest(baseURL+"/"+req.Method, req.Params); err != nil {
+			resp["error"] = map[string]string{"message": err.Error()}
+		} else {
+			resp["result"] = result
+		}
+		line, _ := json.Marshal(resp)
+		_, _ = os.Stdout.Write(append(line, '\n'))
+	}
+}
+
+func relayPluginRequest(url string, params json.RawMessage) (json.RawMessage, error) {
+	resp, err := http.Post(url, "application/json", bytes.NewReader(params))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// newPluginRelayProvider launches the test binary as a plugin relaying its
+// requests to the server, whose handlers are named after the methods
+func newPluginRelayProvider(t *testing.T, server *fakeServer) providers.GitProvider {
+	t.Helper()
+	t.Setenv(pluginRelayEnv, "1")
+	return newPluginProvider(t, os.Args[0], server.URL)
+}
+
+// newFakePluginProvider launches the reference plugin, which is stopped when
+// the test ends
+func newFakePluginProvider(t *testing.T) providers.GitProvider {
+	t.Helper()
+	buildFakePlugin.Do(func() {
+		fakePluginDir, fakePluginErr = os.MkdirTemp("", "fake-plugin")
+		if fakePluginErr != nil {
+			return
+		}
+		fakePluginBin = filepath.Join(fakePluginDir, "fake-plugin")
+		out, err := exec.Command("go", "build", "-o", fakePluginBin, "../examples/fake-plugin").CombinedOutput()
+		if err != nil {
+			fakePluginErr = fmt.Errorf("%w: %s", err, out)
+		}
+	})
+	require.NoError(t, fakePluginErr)
+
+	return newPluginProvider(t, fakePluginBin, "")
+}
+
+// newPluginProvider launches a plugin, which is stopped when the test ends
+func newPluginProvider(t *testing.T, command string, baseURL string) providers.GitProvider {
+	t.Helper()
+	provider, err := providers.NewProvider(providers.ProviderConfig{
+		Type:    providers.Plugin,
+		Command: command,
+		BaseURL: baseURL,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, provider.(*providers.PluginProvider).Close())
+	})
+	return provider
+}
+
+func TestPluginFetch(t *testing.T) {
+	provider := newFakePluginProvider(t)
+	require.Equal(t, providers.Plugin, provider.GetType())
+	require.True(t, provider.SupportsPullRequests())
+	require.True(t, provider.Capabilities().Close)
+	require.Equal(t, []string{"merge"}, provider.Capabilities().MergeStrategies)
+
+	auth, err := provider.GetAuthInfo()
+	require.NoError(t, err)
+	require.Equal(t, "fake-user", auth.Username)
+	require.Equal(t, "fake", auth.TokenSource)
+
+	res, err := provider.FetchPullRequests(context.Background(), "is:open", 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.TotalCount)
+	require.True(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Prs, 1)
+	require.Equal(t, 3, res.Prs[0].Number)
+	require.Equal(t, "fake-user", res.Prs[0].Author.Login)
+	require.Equal(t, "acme/widgets", res.Prs[0].Repository.NameWithOwner)
+
+	res, err = provider.FetchPullRequests(context.Background(), "is:open", 1, &res.PageInfo)
+	require.NoError(t, err)
+	require.False(t, res.PageInfo.HasNextPage)
+	require.Len(t, res.Prs, 1)
+	require.Equal(t, 2, res.Prs[0].Number)
+	require.True(t, res.Prs[0].IsDraft)
+	require.Equal(t, "fake-user", res.Prs[0].Assignees.Nodes[0].Login)
+
+	issues, err := provider.FetchIssues(context.Background(), "author:alice", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, issues.Issues, 1)
+	require.Equal(t, "Widgets render twice", issues.Issues[0].Title)
+
+	// The plugin asked for @me to be replaced by its username
+	issues, err = provider.FetchIssues(context.Background(), "author:@me", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, issues.Issues, 1)
+	require.Equal(t, "Document the API", issues.Issues[0].Title)
+
+	pr, err := provider.FetchPullRequest(context.Background(), "https://review.example.com/acme/widgets/changes/3")
+	require.NoError(t, err)
+	require.Equal(t, "plugins", pr.HeadRefName)
+
+	_, err = provider.FetchPullRequest(context.Background(), "https://review.example.com/acme/widgets/changes/42")
+	require.ErrorContains(t, err, "fake: no pull request at")
+}
+
+func TestPluginActions(t *testing.T) {
+	provider := newFakePluginProvider(t)
+	actions, ok := provider.(providers.PullRequestActions)
+	require.True(t, ok)
+
+	require.NoError(t, actions.ClosePullRequest(3, "acme/widgets"))
+	require.NoError(t, provider.(providers.Commenter).AddComment(3, "acme/widgets", "Superseded"))
+	pr, err := provider.FetchPullRequest(context.Background(), "https://review.example.com/acme/widgets/changes/3")
+	require.NoError(t, err)
+	require.Equal(t, "CLOSED", pr.State)
+	require.Equal(t, "Superseded", pr.Comments.Nodes[0].Body)
+
+	require.NoError(t, provider.(providers.Assigner).RemoveAssignees(2, "acme/widgets", []string{"fake-user"}))
+	res, err := provider.FetchPullRequests(context.Background(), "assignee:fake-user", 20, nil)
+	require.NoError(t, err)
+	require.Empty(t, res.Prs)
+
+	require.NoError(t, provider.(providers.IssueActions).CloseIssue(10, "acme/widgets"))
+	issues, err := provider.FetchIssues(context.Background(), "is:closed", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, issues.Issues, 2)
+
+	require.ErrorContains(t, provider.(providers.BranchUpdater).UpdatePullRequestBranch(3, "acme/widgets"), "can't updateBranch")
+}
+
+func TestPluginCommands(t *testing.T) {
+	provider := newFakePluginProvider(t)
+
+	diff, err := provider.GetDiffCommand(3, "acme/widgets")
+	require.NoError(t, err)
+	require.Equal(t, []string{"echo", "diff", "acme/widgets", "3"}, diff)
+
+	_, err = provider.GetCheckoutCommand(3, "acme/widgets")
+	require.ErrorContains(t, err, "the fake plugin has no checkout command")
+}
+
+func TestPluginClose(t *testing.T) {
+	provider := newFakePluginProvider(t)
+	require.NoError(t, provider.(*providers.PluginProvider).Close())
+
+	// The next request launches the plugin again
+	res, err := provider.FetchPullRequests(context.Background(), "is:open", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Prs, 2)
+}
+
+func TestPluginExited(t *testing.T) {
+	_, err := providers.NewProvider(providers.ProviderConfig{
+		Type:    providers.Plugin,
+		Command: "echo 'not logged in' >&2; exit 1",
+	})
+	require.ErrorContains(t, err, "not logged in")
+
+	_, err = providers.NewProvider(providers.ProviderConfig{Type: providers.Plugin})
+	require.ErrorContains(t, err, "requires a command")
+}
@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"github.com/dlvhdr/gh-dash/v4/ui/theme"
@@ -11,17 +13,27 @@ type ProviderType string
 const (
 	GitHub     ProviderType = "github"
 	AzureDevOps ProviderType = "azure-devops"
+	GitLab      ProviderType = "gitlab"
+	Gitea       ProviderType = "gitea"
+	Forgejo     ProviderType = "forgejo"
+	Bitbucket   ProviderType = "bitbucket"
+	// BitbucketServer is the self-hosted Bitbucket Server / Data Center
+	BitbucketServer ProviderType = "bitbucket-server"
+	// Plugin is an external executable speaking the plugin protocol, see plugin.go
+	Plugin ProviderType = "plugin"
 )
 
 type GitProvider interface {
 	GetType() ProviderType
-	FetchPullRequests(query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error)
-	FetchIssues(query string, limit int, pageInfo *PageInfo) (IssuesResponse, error)
-	FetchPullRequest(url string) (PullRequestData, error)
+	// The fetches stop when ctx is cancelled, e.g. when a newer fetch supersedes them
+	FetchPullRequests(ctx context.Context, query string, limit int, pageInfo *PageInfo) (PullRequestsResponse, error)
+	FetchIssues(ctx context.Context, query string, limit int, pageInfo *PageInfo) (IssuesResponse, error)
+	FetchPullRequest(ctx context.Context, url string) (PullRequestData, error)
 	
 	// Provider-specific operations
 	SupportsPullRequests() bool
 	SupportsIssues() bool
+	Capabilities() Capabilities
 	GetAuthInfo() (AuthInfo, error)
 	
 	// Command operations - return command arguments for execution
@@ -35,18 +47,143 @@ type GitProvider interface {
 	GetWatchChecksCommand(prNumber int, repoNameWithOwner string) ([]string, error)
 }
 
+// PullRequestActions is implemented by providers that act on pull requests
+// through their API. When available it is used instead of the command operations
+type PullRequestActions interface {
+	ApprovePullRequest(prNumber int, repoNameWithOwner string, comment string) error
+	MergePullRequest(prNumber int, repoNameWithOwner string) error
+	ClosePullRequest(prNumber int, repoNameWithOwner string) error
+	ReopenPullRequest(prNumber int, repoNameWithOwner string) error
+	MarkPullRequestReady(prNumber int, repoNameWithOwner string) error
+}
+
+// BranchUpdater is implemented by providers that update the branch of pull
+// requests with their base branch through their API
+type BranchUpdater interface {
+	UpdatePullRequestBranch(prNumber int, repoNameWithOwner string) error
+}
+
+// Commenter is implemented by providers that comment on pull requests and
+// issues through their API
+type Commenter interface {
+	AddComment(number int, repoNameWithOwner string, body string) error
+}
+
+// Assigner is implemented by providers that assign pull requests and issues
+// through their API
+type Assigner interface {
+	AddAssignees(number int, repoNameWithOwner string, logins []string) error
+	RemoveAssignees(number int, repoNameWithOwner string, logins []string) error
+}
+
+// IssueActions is implemented by providers that close and reopen issues
+// through their API
+type IssueActions interface {
+	CloseIssue(number int, repoNameWithOwner string) error
+	ReopenIssue(number int, repoNameWithOwner string) error
+}
+
+// GitCheckout is implemented by providers without a CLI to check out pull requests.
+// Their pull requests are checked out with git, from the source branch or PullRequestRef
+type GitCheckout interface {
+	PullRequestRef(prNumber int) string
+}
+
+// Capabilities describes the pull request and issue actions a provider performs
+// itself, through its API or CLI, rather than by opening them in the browser.
+// The UI refuses the other actions
+type Capabilities struct {
+	// MergeStrategies are the supported merge strategies, none when the provider can't merge
+	MergeStrategies []string
+	// Close covers closing and reopening pull requests
+	Close       bool
+	DraftToggle bool
+	// UpdateBranch updates the pull request with its base branch
+	UpdateBranch bool
+	// Reviews covers approving pull requests
+	Reviews     bool
+	WatchChecks bool
+	// Comments and Assignees cover pull requests and issues
+	Comments  bool
+	Assignees bool
+	// IssueClose covers closing and reopening issues
+	IssueClose bool
+	// Labels reports whether pull requests and issues carry labels
+	Labels   bool
+	Checkout bool
+	// UpdatedSince reports whether searches filter on the `updated:>=` qualifier,
+	// so that refreshes only fetch what changed since the last fetch
+	UpdatedSince bool
+	// ListsDetails reports whether listed pull requests carry their changed files
+	// and comments. Otherwise they are fetched for the pull request in the sidebar
+	ListsDetails bool
+}
+
+func (c Capabilities) CanMerge() bool {
+	return len(c.MergeStrategies) > 0
+}
+
+// GitHubCapabilities are the capabilities of gh, which performs every action
+func GitHubCapabilities() Capabilities {
+	return Capabilities{
+		MergeStrategies: []string{"merge", "squash", "rebase"},
+		Close:           true,
+		DraftToggle:     true,
+		UpdateBranch:    true,
+		Reviews:         true,
+		WatchChecks:     true,
+		Comments:        true,
+		Assignees:       true,
+		IssueClose:      true,
+		Labels:          true,
+		Checkout:        true,
+		UpdatedSince:    true,
+		ListsDetails:    true,
+	}
+}
+
 type AuthInfo struct {
 	Username    string
 	IsLoggedIn  bool
 	TokenSource string
+	// Scopes are the scopes granted to the token, when the provider reports them
+	Scopes []string
+}
+
+// RateLimitReporter is implemented by providers that keep track of the API
+// budget their responses report
+type RateLimitReporter interface {
+	RateLimit() (RateLimit, bool)
+}
+
+// UpdatedFetcher is implemented by providers that fetch the rows matching
+// updatedQuery, those updated since the last fetch of a section, along with the
+// number of rows matching the query of the section, in a single request
+type UpdatedFetcher interface {
+	FetchUpdatedPullRequests(ctx context.Context, query string, updatedQuery string, limit int) (PullRequestsResponse, int, error)
+	FetchUpdatedIssues(ctx context.Context, query string, updatedQuery string, limit int) (IssuesResponse, int, error)
 }
 
 type ProviderConfig struct {
 	Type         ProviderType `yaml:"type"`
 	Organization string       `yaml:"organization,omitempty"`
 	Project      string       `yaml:"project,omitempty"`
+	Repository   string       `yaml:"repository,omitempty"`
 	BaseURL      string       `yaml:"baseUrl,omitempty"`
 	Token        string       `yaml:"token,omitempty"`
+	// MergeStrategy is used by providers that merge pull requests through their
+	// API, e.g. squash or rebase on Azure DevOps
+	MergeStrategy string `yaml:"mergeStrategy,omitempty"`
+	// TokenCommand, TokenFile and GitCredential are read, in that order, when
+	// no Token is configured. See ResolveToken
+	TokenCommand  string `yaml:"tokenCommand,omitempty"`
+	TokenFile     string `yaml:"tokenFile,omitempty"`
+	GitCredential bool   `yaml:"gitCredential,omitempty"`
+	// Command launches the executable of a plugin provider
+	Command string `yaml:"command,omitempty"`
+	// TokenSource is where Token was read from, the config, a credential source
+	// or an environment variable
+	TokenSource string `yaml:"-"`
 }
 
 // Common data interfaces that both providers should implement
@@ -67,25 +204,95 @@ func NewProvider(config ProviderConfig) (GitProvider, error) {
 		return NewGitHubProvider(config)
 	case AzureDevOps:
 		return NewAzureDevOpsProvider(config)
+	case GitLab:
+		return NewGitLabProvider(config)
+	case Gitea, Forgejo:
+		return NewGiteaProvider(config)
+	case Bitbucket, BitbucketServer:
+		return NewBitbucketProvider(config)
+	case Plugin:
+		return NewPluginProvider(config)
 	default:
 		return NewGitHubProvider(config) // Default to GitHub for backward compatibility
 	}
 }
 
+func isKnownProviderType(providerType ProviderType) bool {
+	switch providerType {
+	case GitHub, AzureDevOps, GitLab, Gitea, Forgejo, Bitbucket, BitbucketServer:
+		return true
+	}
+	return false
+}
+
 // Provider detection based on remote URL
 func DetectProviderFromURL(remoteURL string) ProviderType {
 	if isAzureDevOpsURL(remoteURL) {
 		return AzureDevOps
 	}
+	if isGitLabURL(remoteURL) {
+		return GitLab
+	}
+	host := hostFromURL(convertSSHToHTTPS(remoteURL))
+	if providerType, ok := giteaProviderForHost(host); ok {
+		return providerType
+	}
+	if providerType, ok := bitbucketProviderForHost(host); ok {
+		return providerType
+	}
 	return GitHub // Default to GitHub
 }
 
 func isAzureDevOpsURL(url string) bool {
+	if providerType, ok := lookupHostProvider(hostFromURL(convertSSHToHTTPS(url))); ok {
+		return providerType == AzureDevOps
+	}
 	return contains(url, "dev.azure.com") || 
 		   contains(url, "visualstudio.com") ||
 		   contains(url, ".tfs.")
 }
 
+func isGitLabURL(url string) bool {
+	return isGitLabHost(hostFromURL(convertSSHToHTTPS(url)))
+}
+
+func isGitLabHost(host string) bool {
+	if providerType, ok := lookupHostProvider(host); ok {
+		return providerType == GitLab
+	}
+	return host == "gitlab.com" || strings.HasPrefix(host, "gitlab.")
+}
+
+// giteaProviderForHost reports whether the host runs Gitea or Forgejo, either
+// because it was registered through the config or from its well known name
+func giteaProviderForHost(host string) (ProviderType, bool) {
+	if providerType, ok := lookupHostProvider(host); ok {
+		return providerType, providerType == Gitea || providerType == Forgejo
+	}
+	switch {
+	case host == "codeberg.org" || strings.HasPrefix(host, "forgejo."):
+		return Forgejo, true
+	case host == "gitea.com" || strings.HasPrefix(host, "gitea."):
+		return Gitea, true
+	}
+	return "", false
+}
+
+// bitbucketProviderForHost reports whether the host is Bitbucket Cloud or a
+// Bitbucket Server instance
+func bitbucketProviderForHost(host string) (ProviderType, bool) {
+	if providerType, ok := lookupHostProvider(host); ok {
+		return providerType, providerType == Bitbucket || providerType == BitbucketServer
+	}
+	switch {
+	case host == "bitbucket.org":
+		return Bitbucket, true
+	case strings.HasPrefix(host, "bitbucket."):
+		return BitbucketServer, true
+	}
+	return "", false
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && 
 		   (len(substr) == 0 || findIndex(s, substr) >= 0)
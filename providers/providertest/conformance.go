@@ -0,0 +1,287 @@
+// Package providertest checks that providers map the data of their service onto
+// PullRequestData and IssueData alike. Each provider is tested against a fake
+// server of its service serving the same pull requests and issues, in the
+// format of that service
+package providertest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// Repo is the repository of the pull requests and issues, as owner/repo or
+// project/repo
+const Repo = "acme/widgets"
+
+type PullRequest struct {
+	Number    int
+	Title     string
+	Author    string
+	State     string
+	IsDraft   bool
+	CreatedAt time.Time
+	// UpdatedAt is the last activity, which some services only report as the
+	// date of the last comment, push or vote
+	UpdatedAt time.Time
+}
+
+type Issue struct {
+	Number    int
+	Title     string
+	Author    string
+	State     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var epoch = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// PullRequests are served most recently updated first
+var PullRequests = []PullRequest{
+	{Number: 7, Title: "Add search", Author: "alice", State: "OPEN", CreatedAt: epoch, UpdatedAt: epoch.Add(70 * time.Hour)},
+	{Number: 6, Title: "Speed up the build", Author: "bob", State: "MERGED", CreatedAt: epoch, UpdatedAt: epoch.Add(60 * time.Hour)},
+	{Number: 5, Title: "Draft the new theme", Author: "alice", State: "OPEN", IsDraft: true, CreatedAt: epoch, UpdatedAt: epoch.Add(50 * time.Hour)},
+	{Number: 4, Title: "Drop the legacy API", Author: "carol", State: "CLOSED", CreatedAt: epoch, UpdatedAt: epoch.Add(40 * time.Hour)},
+	{Number: 3, Title: "Fix the login redirect", Author: "bob", State: "OPEN", CreatedAt: epoch, UpdatedAt: epoch.Add(30 * time.Hour)},
+	{Number: 2, Title: "Add a changelog", Author: "carol", State: "OPEN", CreatedAt: epoch, UpdatedAt: epoch.Add(20 * time.Hour)},
+}
+
+// Issues are served most recently updated first
+var Issues = []Issue{
+	{Number: 12, Title: "Search is slow", Author: "bob", State: "OPEN", CreatedAt: epoch, UpdatedAt: epoch.Add(12 * time.Hour)},
+	{Number: 11, Title: "Login loops", Author: "alice", State: "CLOSED", CreatedAt: epoch, UpdatedAt: epoch.Add(11 * time.Hour)},
+	{Number: 10, Title: "Document the API", Author: "carol", State: "OPEN", CreatedAt: epoch, UpdatedAt: epoch.Add(10 * time.Hour)},
+}
+
+// Backend is a provider talking to a fake server that serves PullRequests and
+// Issues. is:open and is:merged filters must be applied by the server, or by
+// the provider
+type Backend struct {
+	Provider providers.GitProvider
+	// URL is the web URL of a pull request, as the provider reports it
+	URL func(number int) string
+	// Fail makes the server answer the following requests with an error
+	Fail func()
+}
+
+// Run checks the provider of a fresh backend for every test
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	t.Run("states", func(t *testing.T) {
+		backend := newBackend(t)
+		for _, state := range []string{"OPEN", "MERGED"} {
+			query := "is:" + strings.ToLower(state)
+			res, err := backend.Provider.FetchPullRequests(context.Background(), query, 20, nil)
+			require.NoError(t, err, query)
+			require.Equal(t, numbersWithState(state), pullRequestNumbers(res.Prs), query)
+			for _, pr := range res.Prs {
+				require.Equal(t, state, pr.State, "#%d", pr.Number)
+			}
+		}
+
+		for _, want := range PullRequests {
+			pr, err := backend.Provider.FetchPullRequest(context.Background(), backend.URL(want.Number))
+			require.NoError(t, err, "#%d", want.Number)
+			require.Equal(t, want.State, pr.State, "#%d", want.Number)
+		}
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		backend := newBackend(t)
+		res, err := backend.Provider.FetchPullRequests(context.Background(), "is:open", 20, nil)
+		require.NoError(t, err)
+		for _, pr := range res.Prs {
+			requirePullRequest(t, backend, pr)
+		}
+
+		for _, want := range PullRequests {
+			pr, err := backend.Provider.FetchPullRequest(context.Background(), backend.URL(want.Number))
+			require.NoError(t, err)
+			requirePullRequest(t, backend, pr)
+		}
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		backend := newBackend(t)
+		var numbers []int
+		var pageInfo *providers.PageInfo
+		for page := 0; ; page++ {
+			require.Less(t, page, len(PullRequests), "the pages don't end")
+			res, err := backend.Provider.FetchPullRequests(context.Background(), "is:open", 2, pageInfo)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(res.Prs), 2)
+			numbers = append(numbers, pullRequestNumbers(res.Prs)...)
+			// Providers that can't count say so until the last page
+			if res.TotalCount != providers.UnknownTotalCount || !res.PageInfo.HasNextPage {
+				require.GreaterOrEqual(t, res.TotalCount, len(numbers), "the total count is below the pull requests fetched so far")
+			}
+			if !res.PageInfo.HasNextPage {
+				break
+			}
+			pageInfo = &res.PageInfo
+		}
+		require.Equal(t, numbersWithState("OPEN"), numbers)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		backend := newBackend(t)
+		if !backend.Provider.SupportsIssues() {
+			t.Skip("the provider doesn't support issues")
+		}
+
+		var issues []providers.IssueData
+		var pageInfo *providers.PageInfo
+		for page := 0; ; page++ {
+			require.Less(t, page, len(Issues), "the pages don't end")
+			res, err := backend.Provider.FetchIssues(context.Background(), "", 2, pageInfo)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(res.Issues), 2)
+			issues = append(issues, res.Issues...)
+			if !res.PageInfo.HasNextPage {
+				break
+			}
+			pageInfo = &res.PageInfo
+		}
+
+		require.Len(t, issues, len(Issues))
+		for i, want := range Issues {
+			issue := issues[i]
+			require.Equal(t, want.Number, issue.Number)
+			require.Equal(t, want.Title, issue.Title, "#%d", want.Number)
+			require.Equal(t, want.Author, issue.Author.Login, "#%d", want.Number)
+			require.Equal(t, want.State, issue.State, "#%d", want.Number)
+			require.True(t, want.CreatedAt.Equal(issue.CreatedAt), "#%d was created at %s", want.Number, issue.CreatedAt)
+			require.True(t, want.UpdatedAt.Equal(issue.UpdatedAt), "#%d was updated at %s", want.Number, issue.UpdatedAt)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		backend := newBackend(t)
+		backend.Fail()
+
+		res, err := backend.Provider.FetchPullRequests(context.Background(), "is:open", 20, nil)
+		require.Error(t, err)
+		require.Empty(t, res.Prs)
+
+		_, err = backend.Provider.FetchPullRequest(context.Background(), backend.URL(PullRequests[0].Number))
+		require.Error(t, err)
+
+		if backend.Provider.SupportsIssues() {
+			issues, err := backend.Provider.FetchIssues(context.Background(), "", 20, nil)
+			require.Error(t, err)
+			require.Empty(t, issues.Issues)
+		}
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		backend := newBackend(t)
+		provider := backend.Provider
+		_, repo, _ := strings.Cut(Repo, "/")
+		commands := map[string]func(int, string) ([]string, error){
+			"diff":        provider.GetDiffCommand,
+			"checkout":    provider.GetCheckoutCommand,
+			"merge":       provider.GetMergeCommand,
+			"close":       provider.GetCloseCommand,
+			"reopen":      provider.GetReopenCommand,
+			"ready":       provider.GetReadyCommand,
+			"update":      provider.GetUpdateCommand,
+			"watchChecks": provider.GetWatchChecksCommand,
+		}
+		for name, command := range commands {
+			args, err := command(42, Repo)
+			if err != nil {
+				// The provider doesn't have the command
+				continue
+			}
+			require.NotEmpty(t, args, name)
+			for _, arg := range args {
+				require.NotEmpty(t, arg, "%s: %q", name, args)
+			}
+			line := strings.Join(args, " ")
+			require.Contains(t, line, "42", "%s doesn't target the pull request", name)
+			require.Contains(t, line, repo, "%s doesn't target the repository", name)
+		}
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		provider := newBackend(t).Provider
+		capabilities := provider.Capabilities()
+		if capabilities.Reviews {
+			require.Implements(t, (*providers.PullRequestActions)(nil), provider)
+		}
+		if _, ok := provider.(providers.PullRequestActions); ok {
+			if capabilities.UpdateBranch {
+				require.Implements(t, (*providers.BranchUpdater)(nil), provider)
+			}
+		} else {
+			// Providers without an API for the actions run their commands
+			commands := map[string]func(int, string) ([]string, error){}
+			if capabilities.CanMerge() {
+				commands["merge"] = provider.GetMergeCommand
+			}
+			if capabilities.Close {
+				commands["close"] = provider.GetCloseCommand
+				commands["reopen"] = provider.GetReopenCommand
+			}
+			if capabilities.DraftToggle {
+				commands["ready"] = provider.GetReadyCommand
+			}
+			if capabilities.UpdateBranch {
+				commands["update"] = provider.GetUpdateCommand
+			}
+			for name, command := range commands {
+				_, err := command(42, Repo)
+				require.NoError(t, err, "the %s capability has no command", name)
+			}
+		}
+		if capabilities.Comments {
+			require.Implements(t, (*providers.Commenter)(nil), provider)
+		}
+		if capabilities.Assignees {
+			require.Implements(t, (*providers.Assigner)(nil), provider)
+		}
+	})
+}
+
+// requirePullRequest compares a pull request with the one it was served as
+func requirePullRequest(t *testing.T, backend Backend, pr providers.PullRequestData) {
+	t.Helper()
+	var want *PullRequest
+	for i := range PullRequests {
+		if PullRequests[i].Number == pr.Number {
+			want = &PullRequests[i]
+		}
+	}
+	require.NotNil(t, want, "#%d wasn't served", pr.Number)
+
+	require.Equal(t, want.Title, pr.Title, "#%d", pr.Number)
+	require.Equal(t, want.Author, pr.Author.Login, "#%d", pr.Number)
+	require.Equal(t, want.IsDraft, pr.IsDraft, "#%d", pr.Number)
+	require.Equal(t, backend.URL(pr.Number), pr.Url, "#%d", pr.Number)
+	require.Equal(t, Repo, pr.Repository.NameWithOwner, "#%d", pr.Number)
+	require.True(t, want.CreatedAt.Equal(pr.CreatedAt), "#%d was created at %s", pr.Number, pr.CreatedAt)
+	require.True(t, want.UpdatedAt.Equal(pr.UpdatedAt), "#%d was updated at %s", pr.Number, pr.UpdatedAt)
+}
+
+func numbersWithState(state string) []int {
+	var numbers []int
+	for _, pr := range PullRequests {
+		if pr.State == state {
+			numbers = append(numbers, pr.Number)
+		}
+	}
+	return numbers
+}
+
+func pullRequestNumbers(prs []providers.PullRequestData) []int {
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.Number)
+	}
+	return numbers
+}
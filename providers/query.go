@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchFilter is a single qualifier of a GitHub style search query,
+// e.g. `author:@me` or `-label:"needs review"`
+type SearchFilter struct {
+	Key     string
+	Value   string
+	Negated bool
+}
+
+// SearchQuery is a GitHub style search query split into its qualifiers and
+// free text terms, so that providers without GitHub search can translate it
+type SearchQuery struct {
+	Filters []SearchFilter
+	Terms   []string
+}
+
+// ParseSearchQuery splits a section filter such as
+// `is:open author:@me label:"needs review" crash` into qualifiers and terms
+func ParseSearchQuery(query string) SearchQuery {
+	var res SearchQuery
+	for _, token := range tokenizeSearchQuery(query) {
+		key, value, found := strings.Cut(token, ":")
+		if !found || key == "" || key == "-" {
+			res.Terms = append(res.Terms, strings.Trim(token, `"`))
+			continue
+		}
+
+		filter := SearchFilter{Key: strings.ToLower(key), Value: strings.Trim(value, `"`)}
+		if strings.HasPrefix(filter.Key, "-") {
+			filter.Negated = true
+			filter.Key = strings.TrimPrefix(filter.Key, "-")
+		}
+		res.Filters = append(res.Filters, filter)
+	}
+	return res
+}
+
+// Get returns the value of the first non negated qualifier with the given key
+func (q SearchQuery) Get(key string) (string, bool) {
+	for _, filter := range q.Filters {
+		if filter.Key == key && !filter.Negated {
+			return filter.Value, true
+		}
+	}
+	return "", false
+}
+
+// GetAll returns the values of all non negated qualifiers with the given key
+func (q SearchQuery) GetAll(key string) []string {
+	var values []string
+	for _, filter := range q.Filters {
+		if filter.Key == key && !filter.Negated {
+			values = append(values, filter.Value)
+		}
+	}
+	return values
+}
+
+// Has reports whether the query contains the exact qualifier, e.g. Has("is", "open")
+func (q SearchQuery) Has(key string, value string) bool {
+	for _, filter := range q.Filters {
+		if filter.Key == key && !filter.Negated && strings.EqualFold(filter.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Text returns the free text terms joined by spaces
+func (q SearchQuery) Text() string {
+	return strings.Join(q.Terms, " ")
+}
+
+// ExpandMeQualifier replaces @me as the value of a qualifier, e.g. `author:@me`,
+// with the given username for providers whose search doesn't understand it.
+// Quoted values such as `label:"@me"` are kept as written
+func ExpandMeQualifier(query string, username string) string {
+	if username == "" || !strings.Contains(query, "@me") {
+		return query
+	}
+
+	tokens := tokenizeSearchQuery(query)
+	for i, token := range tokens {
+		key, value, found := strings.Cut(token, ":")
+		if found && key != "" && key != "-" && value == "@me" {
+			tokens[i] = key + ":" + username
+			if strings.Contains(username, " ") {
+				tokens[i] = fmt.Sprintf("%s:%q", key, username)
+			}
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+func tokenizeSearchQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// formatSearchFilter writes a qualifier back the way it is written in a search query
+func formatSearchFilter(filter SearchFilter) string {
+	res := filter.Key + ":" + filter.Value
+	if strings.Contains(filter.Value, " ") {
+		res = fmt.Sprintf("%s:%q", filter.Key, filter.Value)
+	}
+	if filter.Negated {
+		res = "-" + res
+	}
+	return res
+}
+
+// unsupportedFiltersWarning explains which qualifiers of a section filter the
+// provider ignored
+func unsupportedFiltersWarning(provider string, unsupported []string) []string {
+	if len(unsupported) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s ignored unsupported filters: %s", provider, strings.Join(unsupported, ", "))}
+}
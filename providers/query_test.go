@@ -0,0 +1,21 @@
+package providers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+func TestExpandMeQualifier(t *testing.T) {
+	require.Equal(t,
+		`is:open author:alice -assignee:alice label:"@me" @me`,
+		providers.ExpandMeQualifier(`is:open author:@me -assignee:@me label:"@me" @me`, "alice"),
+	)
+	require.Equal(t,
+		`review-requested:"Alice Smith"`,
+		providers.ExpandMeQualifier("review-requested:@me", "Alice Smith"),
+	)
+	require.Equal(t, "author:@me", providers.ExpandMeQualifier("author:@me", ""))
+}
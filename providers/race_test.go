@@ -0,0 +1,90 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// TestConcurrentFetches fetches from every provider in parallel, the way the
+// sections of a dashboard do, so that -race catches unguarded lazy caches
+func TestConcurrentFetches(t *testing.T) {
+	testCases := map[string]struct {
+		newProvider func(t *testing.T) providers.GitProvider
+		fetch       func(provider providers.GitProvider) error
+	}{
+		"GitHub": {
+			newProvider: func(t *testing.T) providers.GitProvider {
+				server := newFakeServer(t)
+				server.handle("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+					server.writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"viewer": map[string]string{"login": "me"}}})
+				})
+				server.handle("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-OAuth-Scopes", "repo")
+					server.writeJSON(w, map[string]string{"login": "me"})
+				})
+				return newTestGitHubProvider(t, server)
+			},
+			fetch: getAuthInfo,
+		},
+		"GitLab": {
+			newProvider: func(t *testing.T) providers.GitProvider {
+				return newGitLabServer(t).newProvider(providers.ProviderConfig{Type: providers.GitLab})
+			},
+			fetch: func(provider providers.GitProvider) error {
+				_, err := provider.FetchPullRequests(context.Background(), "is:open author:@me label:bug", 20, nil)
+				return err
+			},
+		},
+		"Gitea": {
+			newProvider: func(t *testing.T) providers.GitProvider {
+				return newGiteaServer(t).newProvider(providers.ProviderConfig{Type: providers.Forgejo})
+			},
+			fetch: getAuthInfo,
+		},
+		"Bitbucket": {
+			newProvider: func(t *testing.T) providers.GitProvider {
+				return newBitbucketServer(t).newProvider(providers.ProviderConfig{Type: providers.Bitbucket})
+			},
+			fetch: func(provider providers.GitProvider) error {
+				_, err := provider.FetchPullRequests(context.Background(), "repo:team/app is:open review-requested:@me", 20, nil)
+				return err
+			},
+		},
+		"Azure DevOps": {
+			newProvider: func(t *testing.T) providers.GitProvider {
+				return newTestAzureDevOpsProvider(newAzureDevOpsServer(t))
+			},
+			fetch: getAuthInfo,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			provider := tc.newProvider(t)
+			var wg sync.WaitGroup
+			errs := make([]error, 8)
+			for i := range errs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = tc.fetch(provider)
+				}()
+			}
+			wg.Wait()
+			for _, err := range errs {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func getAuthInfo(provider providers.GitProvider) error {
+	_, err := provider.GetAuthInfo()
+	return err
+}
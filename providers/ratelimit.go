@@ -0,0 +1,243 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate limited request is retried
+	maxRateLimitRetries = 3
+	// maxRateLimitWait is the longest wait before a retry. When the server asks
+	// for a longer one the request fails with a RateLimitError instead
+	maxRateLimitWait = 10 * time.Second
+	// rateLimitBackoff is the first wait when the server doesn't say how long to wait
+	rateLimitBackoff = time.Second
+)
+
+// RateLimit is the API budget a provider reported on its last response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// IsLow reports whether less than a tenth of the budget is left until it resets
+func (r RateLimit) IsLow() bool {
+	if r.Limit <= 0 || r.IsReset() {
+		return false
+	}
+	return r.Remaining*10 < r.Limit
+}
+
+// IsReset reports whether the budget was renewed since it was reported
+func (r RateLimit) IsReset() bool {
+	return !r.ResetAt.IsZero() && time.Now().After(r.ResetAt)
+}
+
+// RateLimitError is returned when the API budget is used up for longer than
+// is worth waiting for
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "API rate limit exceeded, try again later"
+	}
+	return fmt.Sprintf("API rate limit exceeded, resets at %s", e.ResetAt.Local().Format("15:04"))
+}
+
+// RateLimitTracker keeps the last rate limit reported to a provider. Providers
+// embed it to implement RateLimitReporter
+type RateLimitTracker struct {
+	mu        sync.Mutex
+	rateLimit *RateLimit
+}
+
+// RateLimit returns the last reported rate limit, if any
+func (t *RateLimitTracker) RateLimit() (RateLimit, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *t.rateLimit, true
+}
+
+// RecordRateLimit replaces the last reported rate limit
+func (t *RateLimitTracker) RecordRateLimit(rateLimit RateLimit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rateLimit = &rateLimit
+}
+
+// rateLimitTransport records the rate limit headers of the responses, and
+// retries rate limited requests after the wait the server asks for, or else
+// with an exponential backoff. Waits get a random jitter, so sections that
+// were limited together don't retry together
+type rateLimitTransport struct {
+	tracker *RateLimitTracker
+	// base defaults to http.DefaultTransport
+	base http.RoundTripper
+}
+
+func newRateLimitTransport(tracker *RateLimitTracker) *rateLimitTransport {
+	return &rateLimitTransport{tracker: tracker}
+}
+
+// maxConcurrentDetailRequests bounds the requests a list fetch makes at once for
+// the details of its rows, so a page of results doesn't burst into secondary limits
+const maxConcurrentDetailRequests = 4
+
+// forEachConcurrently calls fn with the index of each of n rows, running at most
+// maxConcurrentDetailRequests calls at once
+func forEachConcurrently(n int, fn func(i int)) {
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentDetailRequests)
+	for i := 0; i < n; i++ {
+		g.Go(func() error {
+			fn(i)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// actionTimeout bounds actions and signing in. Unlike fetches, whose context the
+// UI cancels, they have no context of their own
+const actionTimeout = 30 * time.Second
+
+// actionContext returns the context of the requests of an action
+func actionContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), actionTimeout)
+}
+
+// newRateLimitedClient returns the HTTP client of the REST API providers. Its
+// requests, including their retries, are bounded by their context rather than a
+// timeout of the client
+func newRateLimitedClient(tracker *RateLimitTracker) *http.Client {
+	return &http.Client{Transport: newRateLimitTransport(tracker)}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if rateLimit, ok := rateLimitFromHeaders(resp.Header); ok {
+			t.tracker.RecordRateLimit(rateLimit)
+		}
+
+		wait, limited := rateLimitWait(resp, attempt)
+		if !limited {
+			return resp, nil
+		}
+		canRetry := req.Body == nil || req.GetBody != nil
+		if attempt >= maxRateLimitRetries || wait > maxRateLimitWait || !canRetry {
+			resp.Body.Close()
+			return nil, &RateLimitError{ResetAt: time.Now().Add(wait)}
+		}
+		resp.Body.Close()
+
+		wait = withJitter(wait)
+		log.Debug("Rate limited, retrying", "url", req.URL.Redacted(), "status", resp.StatusCode, "wait", wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// rateLimitWait reports whether a response was rate limited and how long to
+// wait before retrying. GitHub reports the primary rate limit with a 403, and
+// Azure DevOps delays the requests of users it throttles with a 503
+func rateLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusForbidden, http.StatusServiceUnavailable:
+		if !hasRetryAfter && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if hasRetryAfter {
+		return retryAfter, true
+	}
+	if rateLimit, ok := rateLimitFromHeaders(resp.Header); ok && rateLimit.Remaining == 0 && !rateLimit.ResetAt.IsZero() {
+		return time.Until(rateLimit.ResetAt), true
+	}
+	return rateLimitBackoff << attempt, true
+}
+
+// withJitter spreads a wait over up to half as long again
+func withJitter(wait time.Duration) time.Duration {
+	if wait <= 0 {
+		return 0
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/2+1))
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// rateLimitFromHeaders reads the X-RateLimit-* headers of GitHub, Gitea and
+// Azure DevOps, and the RateLimit-* headers of GitLab. The reset is a Unix time
+func rateLimitFromHeaders(header http.Header) (RateLimit, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, err := strconv.Atoi(header.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+		rateLimit := RateLimit{Limit: limit, Remaining: remaining}
+		if reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+			rateLimit.ResetAt = time.Unix(reset, 0)
+		}
+		return rateLimit, true
+	}
+	return RateLimit{}, false
+}
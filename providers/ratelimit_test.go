@@ -0,0 +1,74 @@
+package providers_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+// newRateLimitedGitLabServer serves empty issue lists, answering the first
+// throttled requests with the given status and headers
+func newRateLimitedGitLabServer(t *testing.T, throttled int, status int, headers map[string]string) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+	server.handle("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]string{"username": "me"})
+	})
+	server.handle("/api/v4/issues", func(w http.ResponseWriter, r *http.Request) {
+		if len(issueRequests(server)) <= throttled {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("RateLimit-Limit", "2000")
+		w.Header().Set("RateLimit-Remaining", "150")
+		w.Header().Set("RateLimit-Reset", "1900000000")
+		server.writeJSON(w, []interface{}{})
+	})
+	return server
+}
+
+func issueRequests(server *fakeServer) []receivedRequest {
+	return server.requests("GET", "/api/v4/issues")
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	server := newRateLimitedGitLabServer(t, 2, http.StatusTooManyRequests, map[string]string{"Retry-After": "0"})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	_, err := provider.FetchIssues(context.Background(), "is:open", 20, nil)
+	require.NoError(t, err)
+	require.Len(t, issueRequests(server), 3)
+
+	rateLimit, ok := provider.(providers.RateLimitReporter).RateLimit()
+	require.True(t, ok)
+	require.Equal(t, 2000, rateLimit.Limit)
+	require.Equal(t, 150, rateLimit.Remaining)
+	require.Equal(t, time.Unix(1900000000, 0), rateLimit.ResetAt)
+	require.True(t, rateLimit.IsLow())
+}
+
+func TestRateLimitExhausted(t *testing.T) {
+	resetAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	server := newRateLimitedGitLabServer(t, 1, http.StatusForbidden, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(resetAt.Unix(), 10),
+	})
+	provider := server.newProvider(providers.ProviderConfig{Type: providers.GitLab})
+
+	// Waiting an hour isn't worth it, so the fetch fails without retrying
+	_, err := provider.FetchIssues(context.Background(), "is:open", 20, nil)
+	var rateLimitErr *providers.RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	require.WithinDuration(t, resetAt, rateLimitErr.ResetAt, 2*time.Second)
+	require.Len(t, issueRequests(server), 1)
+}
@@ -0,0 +1,345 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+)
+
+// Recorded responses let bug reports be reproduced and the UI be tested without
+// the network. RecordResponses saves every response of the GraphQL and REST
+// APIs into a directory, one file per distinct request, and ReplayResponses
+// answers the same requests from those files. Both replace
+// http.DefaultTransport, which every API client is built on, so they must be
+// called before the providers and the GraphQL clients are created
+
+// redactedValue replaces secrets in recorded requests and responses
+const redactedValue = "[REDACTED]"
+
+// secretNames are parts of the names of URL parameters, headers and JSON
+// fields whose values are secrets
+var secretNames = []string{"token", "password", "secret", "authorization", "cookie", "signature", "api_key", "apikey"}
+
+// RecordResponses saves the responses of the API requests into dir, with their
+// secrets redacted
+func RecordResponses(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	log.Debug("Recording API responses", "dir", dir)
+	http.DefaultTransport = &recordingTransport{dir: dir, base: http.DefaultTransport}
+	return nil
+}
+
+// ReplayResponses answers the API requests with the responses recorded in dir.
+// Requests that weren't recorded fail
+func ReplayResponses(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory of recorded responses", dir)
+	}
+	log.Debug("Replaying API responses", "dir", dir)
+	http.DefaultTransport = &replayTransport{dir: dir}
+	return nil
+}
+
+// recordedExchange is a request and its response, as saved in a file
+type recordedExchange struct {
+	Request struct {
+		Method string       `json:"method"`
+		URL    string       `json:"url"`
+		Body   recordedBody `json:"body"`
+	} `json:"request"`
+	Response struct {
+		Status int          `json:"status"`
+		Header http.Header  `json:"header,omitempty"`
+		Body   recordedBody `json:"body"`
+	} `json:"response"`
+}
+
+// recordedBody keeps JSON bodies readable, so recordings can be edited to
+// reproduce a bug. Other bodies are kept as text, or base64 when binary
+type recordedBody struct {
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Text   string          `json:"text,omitempty"`
+	Base64 []byte          `json:"base64,omitempty"`
+}
+
+func newRecordedBody(body []byte) recordedBody {
+	switch {
+	case len(bytes.TrimSpace(body)) == 0:
+		return recordedBody{}
+	case json.Valid(body):
+		return recordedBody{JSON: redactJSON(body)}
+	case utf8.Valid(body):
+		return recordedBody{Text: RedactSecrets(string(body))}
+	}
+	return recordedBody{Base64: body}
+}
+
+func (b recordedBody) bytes() []byte {
+	switch {
+	case len(b.JSON) > 0:
+		return b.JSON
+	case b.Text != "":
+		return []byte(b.Text)
+	}
+	return b.Base64
+}
+
+// volatileQualifier matches the times of the updated qualifiers refreshes add,
+// e.g. updated:>=2024-05-01T10:00:00Z, in search queries and their URL and JSON
+// encodings, where > may be escaped as \u003e
+var volatileQualifier = regexp.MustCompile(`(updated:(?:>|\\u003e|<|\\u003c)?=?)\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`)
+
+// normalizeVolatile replaces the times of the updated qualifiers, which depend on
+// when the previous fetch ran, so a replayed refresh finds the recorded one
+func normalizeVolatile(s string) string {
+	return volatileQualifier.ReplaceAllString(s, "${1}<time>")
+}
+
+// key identifies a request by its method, redacted URL and body, and names the
+// file of its response. The times of updated qualifiers aren't part of it, see
+// normalizeVolatile
+func (e *recordedExchange) key() string {
+	hash := sha256.New()
+	requestURL := e.Request.URL
+	if unescaped, err := url.QueryUnescape(requestURL); err == nil && volatileQualifier.MatchString(unescaped) {
+		requestURL = normalizeVolatile(unescaped)
+	}
+	fmt.Fprintf(hash, "%s %s\n", e.Request.Method, requestURL)
+	body := e.Request.Body.bytes()
+	if len(e.Request.Body.JSON) > 0 {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, body); err == nil {
+			body = compacted.Bytes()
+		}
+	}
+	hash.Write([]byte(normalizeVolatile(string(body))))
+
+	host := "request"
+	if u, err := url.Parse(e.Request.URL); err == nil && u.Host != "" {
+		host = strings.NewReplacer(":", "_", "/", "_").Replace(u.Host)
+	}
+	return fmt.Sprintf("%s-%s-%s.json", host, e.Request.Method, hex.EncodeToString(hash.Sum(nil))[:16])
+}
+
+// newRecordedRequest redacts the URL and the body of a request, returning the
+// body too, since reading it consumes it
+func newRecordedRequest(req *http.Request) (*recordedExchange, []byte, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	exchange := &recordedExchange{}
+	exchange.Request.Method = req.Method
+	exchange.Request.URL = redactURL(req.URL)
+	exchange.Request.Body = newRecordedBody(body)
+	return exchange, body, nil
+}
+
+type recordingTransport struct {
+	dir  string
+	base http.RoundTripper
+
+	mu sync.Mutex
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	registerRequestSecrets(req)
+	exchange, body, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	exchange.Response.Status = resp.StatusCode
+	exchange.Response.Header = redactHeader(resp.Header)
+	// Redacting can change the length of the body
+	exchange.Response.Header.Del("Content-Length")
+	exchange.Response.Body = newRecordedBody(respBody)
+	if err := t.save(exchange); err != nil {
+		log.Debug("Failed recording the response", "url", exchange.Request.URL, "err", err)
+	}
+	return resp, nil
+}
+
+func (t *recordingTransport) save(exchange *recordedExchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(t.dir, exchange.key())
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	log.Debug("Recording response", "url", exchange.Request.URL, "file", path)
+	return os.WriteFile(path, []byte(RedactSecrets(string(data))+"\n"), 0o644)
+}
+
+type replayTransport struct {
+	dir string
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange, _, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(t.dir, exchange.key())
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Debug("No recorded response", "method", req.Method, "url", exchange.Request.URL, "file", path)
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, exchange.Request.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, exchange); err != nil {
+		return nil, fmt.Errorf("invalid recorded response %s: %w", path, err)
+	}
+
+	header := exchange.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	body := exchange.Response.Body.bytes()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Response.Status, http.StatusText(exchange.Response.Status)),
+		StatusCode:    exchange.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// registerRequestSecrets registers the credentials sent with a request, so
+// they are redacted wherever they are echoed
+func registerRequestSecrets(req *http.Request) {
+	for name, values := range req.Header {
+		if !isSecretName(name) {
+			continue
+		}
+		for _, value := range values {
+			scheme, credentials, ok := strings.Cut(value, " ")
+			if !ok {
+				RegisterSecret(value)
+				continue
+			}
+			if strings.EqualFold(scheme, "basic") {
+				if decoded, err := base64.StdEncoding.DecodeString(credentials); err == nil {
+					RegisterSecret(string(decoded))
+				}
+			}
+			RegisterSecret(credentials)
+		}
+	}
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL drops the user info of a URL and redacts its secret parameters
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	query := redacted.Query()
+	for name := range query {
+		if isSecretName(name) {
+			query.Set(name, redactedValue)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return RedactSecrets(redacted.String())
+}
+
+func redactHeader(header http.Header) http.Header {
+	redacted := http.Header{}
+	for name, values := range header {
+		if isSecretName(name) {
+			continue
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// redactJSON redacts the string values of the secret fields of a JSON body
+func redactJSON(body []byte) json.RawMessage {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(redactJSONValue(value))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if _, isString := field.(string); isString && isSecretName(name) {
+				v[name] = redactedValue
+				continue
+			}
+			v[name] = redactJSONValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,134 @@
+package providers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/providers"
+)
+
+const recordedToken = "glpat-recorded-token"
+
+// newRecordedGitLabServer serves an issue that leaks the token it was fetched with
+func newRecordedGitLabServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := newFakeServer(t)
+	server.handle("/api/v4/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, recordedToken, r.Header.Get("PRIVATE-TOKEN"))
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Total", "1")
+		server.writeJSON(w, []map[string]interface{}{{
+			"iid":           3,
+			"project_id":    7,
+			"title":         "Leaked " + recordedToken,
+			"state":         "opened",
+			"author":        map[string]string{"username": "carol"},
+			"web_url":       server.URL + "/group/repo/-/issues/3",
+			"created_at":    "2024-01-01T10:00:00Z",
+			"updated_at":    "2024-01-03T10:00:00Z",
+			"runners_token": "runner-secret",
+		}})
+	})
+	return server
+}
+
+func fetchRecordedIssues(t *testing.T, baseURL string, token string) (providers.IssuesResponse, error) {
+	t.Helper()
+	provider, err := providers.NewGitLabProvider(providers.ProviderConfig{
+		Type:    providers.GitLab,
+		BaseURL: baseURL,
+		Token:   token,
+	})
+	require.NoError(t, err)
+	return provider.FetchIssues(context.Background(), "is:open author:carol", 20, nil)
+}
+
+func TestRecordAndReplayResponses(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+	dir := t.TempDir()
+	server := newRecordedGitLabServer(t)
+
+	require.NoError(t, providers.RecordResponses(dir))
+	recorded, err := fetchRecordedIssues(t, server.URL, recordedToken)
+	require.NoError(t, err)
+	require.Equal(t, "Leaked "+recordedToken, recorded.Issues[0].Title)
+	server.Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.NotContains(t, string(data), recordedToken)
+	require.NotContains(t, string(data), "runner-secret")
+	require.NotContains(t, string(data), "session=abc")
+	require.True(t, strings.HasPrefix(filepath.Base(files[0]), "127.0.0.1_"), files[0])
+
+	// The server is gone, and the token doesn't matter anymore
+	http.DefaultTransport = defaultTransport
+	require.NoError(t, providers.ReplayResponses(dir))
+	replayed, err := fetchRecordedIssues(t, server.URL, "another-token")
+	require.NoError(t, err)
+	require.Equal(t, recorded.TotalCount, replayed.TotalCount)
+	require.Len(t, replayed.Issues, 1)
+	require.Equal(t, "Leaked [REDACTED]", replayed.Issues[0].Title)
+	require.Equal(t, recorded.Issues[0].Url, replayed.Issues[0].Url)
+	require.Equal(t, recorded.Issues[0].UpdatedAt, replayed.Issues[0].UpdatedAt)
+
+	provider, err := providers.NewGitLabProvider(providers.ProviderConfig{
+		Type:    providers.GitLab,
+		BaseURL: server.URL,
+		Token:   "another-token",
+	})
+	require.NoError(t, err)
+	_, err = provider.FetchIssues(context.Background(), "is:closed", 20, nil)
+	require.ErrorContains(t, err, "no recorded response for GET")
+}
+
+func TestReplayIgnoresTheTimesOfUpdatedQualifiers(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+	dir := t.TempDir()
+	server := newFakeServer(t)
+	server.handle("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]interface{}{"data": map[string]int{"issueCount": 3}})
+	})
+	server.handle("/search", func(w http.ResponseWriter, r *http.Request) {
+		server.writeJSON(w, map[string]int{"total_count": 4})
+	})
+
+	search := func(since string) {
+		t.Helper()
+		query := map[string]interface{}{"query": "is:open updated:>=" + since}
+		body, err := json.Marshal(query)
+		require.NoError(t, err)
+		resp, err := http.Post(server.URL+"/graphql", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = http.Get(server.URL + "/search?q=" + url.QueryEscape("is:open updated:>="+since))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	require.NoError(t, providers.RecordResponses(dir))
+	search("2024-05-01T10:00:00Z")
+	server.Close()
+
+	http.DefaultTransport = defaultTransport
+	require.NoError(t, providers.ReplayResponses(dir))
+	search("2024-05-01T10:25:00Z")
+}
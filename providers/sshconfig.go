@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// resolveSSHHost returns the HostName ~/.ssh/config sets for a host alias, e.g.
+// github.com for `Host github.com-personal`, or the host itself when there is none
+func resolveSSHHost(alias string) string {
+	config, ok := readSSHConfig()
+	if !ok {
+		return alias
+	}
+
+	hostName, ok := findSSHHostName(bufio.NewScanner(bytes.NewReader(config)), alias)
+	if !ok {
+		return alias
+	}
+	log.Debug("Resolved SSH host alias", "alias", alias, "hostName", hostName)
+	return hostName
+}
+
+// sshConfig is the content of ~/.ssh/config, read once for every remote. It is
+// read again when the home directory changes
+var sshConfig struct {
+	mu      sync.Mutex
+	path    string
+	content []byte
+	ok      bool
+}
+
+// readSSHConfig returns the content of ~/.ssh/config, reporting whether there
+// is one
+func readSSHConfig() ([]byte, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, false
+	}
+	path := filepath.Join(home, ".ssh", "config")
+
+	sshConfig.mu.Lock()
+	defer sshConfig.mu.Unlock()
+	if sshConfig.path != path {
+		content, err := os.ReadFile(path)
+		sshConfig.path, sshConfig.content, sshConfig.ok = path, content, err == nil
+	}
+	return sshConfig.content, sshConfig.ok
+}
+
+// findSSHHostName returns the first HostName of the Host blocks matching the
+// alias, like ssh does. Match blocks and Include directives aren't supported
+func findSSHHostName(scanner *bufio.Scanner, alias string) (string, bool) {
+	matching := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Keywords and arguments are separated by whitespace or an optional =
+		i := strings.IndexAny(line, " \t=")
+		if i < 0 {
+			continue
+		}
+		keyword := line[:i]
+		args := strings.Trim(strings.TrimLeft(line[i:], " \t="), `"`)
+
+		switch strings.ToLower(keyword) {
+		case "host":
+			matching = matchSSHHostPatterns(strings.Fields(args), alias)
+		case "match":
+			matching = false
+		case "hostname":
+			if matching {
+				return strings.ReplaceAll(args, "%h", alias), true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchSSHHostPatterns reports whether a Host line applies to the alias: one of
+// its patterns matches, and none of its negated patterns do
+func matchSSHHostPatterns(patterns []string, alias string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		ok, _ := filepath.Match(strings.TrimPrefix(pattern, "!"), alias)
+		if ok && negated {
+			return false
+		}
+		matched = matched || ok
+	}
+	return matched
+}
@@ -51,15 +51,17 @@ type Review struct {
 }
 
 type ReviewThreads struct {
-	Nodes []struct {
-		Id           string
-		IsOutdated   bool
-		OriginalLine int
-		StartLine    int
-		Line         int
-		Path         string
-		Comments     ReviewComments `graphql:"comments(first: 10)"`
-	}
+	Nodes []ReviewThread
+}
+
+type ReviewThread struct {
+	Id           string
+	IsOutdated   bool
+	OriginalLine int
+	StartLine    int
+	Line         int
+	Path         string
+	Comments     ReviewComments `graphql:"comments(first: 10)"`
 }
 
 type ReviewComments struct {
@@ -79,9 +81,11 @@ type ReviewComment struct {
 
 type ReviewRequests struct {
 	TotalCount int
-	Nodes      []struct {
-		AsCodeOwner bool `graphql:"asCodeOwner"`
-	}
+	Nodes      []ReviewRequest
+}
+
+type ReviewRequest struct {
+	AsCodeOwner bool `graphql:"asCodeOwner"`
 }
 
 type ChangedFiles struct {
@@ -112,16 +116,18 @@ type CommitNode struct {
 		StatusCheckRollup struct {
 			Contexts struct {
 				TotalCount int
-				Nodes      []struct {
-					Typename      string      `graphql:"__typename"`
-					CheckRun      CheckRun    `graphql:"... on CheckRun"`
-					StatusContext StatusContext `graphql:"... on StatusContext"`
-				}
+				Nodes      []CheckContext
 			}
 		}
 	}
 }
 
+type CheckContext struct {
+	Typename      string        `graphql:"__typename"`
+	CheckRun      CheckRun      `graphql:"... on CheckRun"`
+	StatusContext StatusContext `graphql:"... on StatusContext"`
+}
+
 type CheckRun struct {
 	Name       string
 	Status     string
@@ -184,6 +190,7 @@ type PullRequestData struct {
 	Deletions         int
 	HeadRefName       string
 	BaseRefName       string
+	IsCrossRepository bool
 	HeadRepository    struct {
 		Name string
 	}
@@ -239,16 +246,25 @@ type IssueReactions struct {
 	TotalCount int
 }
 
+// UnknownTotalCount is the TotalCount of a page with more pages after it, when
+// the API doesn't count the rows matching the search
+const UnknownTotalCount = -1
+
 type PullRequestsResponse struct {
-	Prs        []PullRequestData
+	Prs []PullRequestData
+	// TotalCount is the number of pull requests matching the search, or
+	// UnknownTotalCount
 	TotalCount int
 	PageInfo   PageInfo
+	// Warnings are shown to the user, e.g. for filters the provider couldn't apply
+	Warnings []string
 }
 
 type IssuesResponse struct {
 	Issues     []IssueData
 	TotalCount int
 	PageInfo   PageInfo
+	Warnings   []string
 }
 
 // Helper methods to implement ItemData interface
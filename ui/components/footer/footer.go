@@ -13,6 +13,7 @@ import (
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/git"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
 	"github.com/dlvhdr/gh-dash/v4/ui/keys"
@@ -24,6 +25,8 @@ type Model struct {
 	leftSection     *string
 	rightSection    *string
 	help            bbHelp.Model
+	capabilities    providers.Capabilities
+	rateLimit       *providers.RateLimit
 	ShowAll         bool
 	ShowConfirmQuit bool
 }
@@ -88,6 +91,7 @@ func (m Model) View() string {
 		if m.rightSection != nil {
 			rightSection = *m.rightSection
 		}
+		rateLimit := m.renderRateLimit()
 		spacing := lipgloss.NewStyle().
 			Background(m.ctx.Theme.SelectedBackground).
 			Render(
@@ -98,17 +102,18 @@ func (m Model) View() string {
 							viewSwitcher,
 						)-lipgloss.Width(leftSection)-
 							lipgloss.Width(rightSection)-
+							lipgloss.Width(rateLimit)-
 							lipgloss.Width(
 								helpIndicator,
 							)-lipgloss.Width(donationIndicator),
 					)))
 
 		footer = m.ctx.Styles.Common.FooterStyle.
-			Render(lipgloss.JoinHorizontal(lipgloss.Top, viewSwitcher, leftSection, spacing, rightSection, donationIndicator, helpIndicator))
+			Render(lipgloss.JoinHorizontal(lipgloss.Top, viewSwitcher, leftSection, spacing, rightSection, rateLimit, donationIndicator, helpIndicator))
 	}
 
 	if m.ShowAll {
-		keymap := keys.CreateKeyMapForView(m.ctx.View)
+		keymap := keys.CreateKeyMapForView(m.ctx.View, m.capabilities)
 		fullHelp := m.help.View(keymap)
 		return lipgloss.JoinVertical(lipgloss.Top, footer, fullHelp)
 	}
@@ -116,6 +121,37 @@ func (m Model) View() string {
 	return footer
 }
 
+// SetCapabilities sets the capabilities of the provider of the current section
+func (m *Model) SetCapabilities(capabilities providers.Capabilities) {
+	m.capabilities = capabilities
+}
+
+// SetRateLimit sets the API budget left to the provider of the current section
+func (m *Model) SetRateLimit(rateLimit providers.RateLimit, ok bool) {
+	if !ok {
+		m.rateLimit = nil
+		return
+	}
+	m.rateLimit = &rateLimit
+}
+
+// renderRateLimit shows the API budget left until it resets, in the warning
+// color when it runs low
+func (m *Model) renderRateLimit() string {
+	if m.rateLimit == nil || m.rateLimit.Limit == 0 || m.rateLimit.IsReset() {
+		return ""
+	}
+	text := fmt.Sprintf("API %d/%d", m.rateLimit.Remaining, m.rateLimit.Limit)
+	if !m.rateLimit.ResetAt.IsZero() {
+		text += fmt.Sprintf(" resets %s", m.rateLimit.ResetAt.Local().Format("15:04"))
+	}
+	style := m.ctx.Styles.Common.FooterStyle.Foreground(m.ctx.Theme.FaintText)
+	if m.rateLimit.IsLow() {
+		style = style.Foreground(m.ctx.Theme.WarningText)
+	}
+	return style.Render(text + " ")
+}
+
 func (m *Model) SetWidth(width int) {
 	m.help.Width = width
 }
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/issuessection"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
@@ -26,31 +27,45 @@ func (m *Model) comment(body string) tea.Cmd {
 	}
 	startCmd := m.ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command(
-			"gh",
-			"issue",
-			"comment",
-			fmt.Sprint(issueNumber),
-			"-R",
-			issue.GetRepoNameWithOwner(),
-			"-b",
-			body,
-		)
+		provider, err := data.GetProvider(m.provider)
+		commenter, ok := provider.(providers.Commenter)
+		switch {
+		case err != nil:
+		case ok:
+			err = commenter.AddComment(issueNumber, issue.GetRepoNameWithOwner(), body)
+		case provider == nil:
+			c := exec.Command(
+				"gh",
+				"issue",
+				"comment",
+				fmt.Sprint(issueNumber),
+				"-R",
+				data.GitHubRepoArg(m.provider, issue.GetRepoNameWithOwner()),
+				"-b",
+				body,
+			)
+			err = c.Run()
+		default:
+			err = fmt.Errorf("%s doesn't support commenting on issues", provider.GetType())
+		}
 
-		err := c.Run()
-		return constants.TaskFinishedMsg{
-			SectionId:   m.sectionId,
-			SectionType: issuessection.SectionType,
-			TaskId:      taskId,
-			Err:         err,
-			Msg: issuessection.UpdateIssueMsg{
+		var msg tea.Msg
+		if err == nil {
+			msg = issuessection.UpdateIssueMsg{
 				IssueNumber: issueNumber,
 				NewComment: &data.IssueComment{
 					Author:    struct{ Login string }{Login: m.ctx.User},
 					Body:      body,
 					UpdatedAt: time.Now(),
 				},
-			},
+			}
+		}
+		return constants.TaskFinishedMsg{
+			SectionId:   m.sectionId,
+			SectionType: issuessection.SectionType,
+			TaskId:      taskId,
+			Err:         err,
+			Msg:         msg,
 		}
 	})
 }
@@ -27,6 +27,7 @@ type Model struct {
 	ctx       *context.ProgramContext
 	issue     *issue.Issue
 	sectionId int
+	provider  string
 	width     int
 
 	ShowConfirmCancel bool
@@ -243,6 +244,11 @@ func (m *Model) SetSectionId(id int) {
 	m.sectionId = id
 }
 
+// SetProvider sets the provider profile of the section the issue belongs to
+func (m *Model) SetProvider(provider string) {
+	m.provider = provider
+}
+
 func (m *Model) SetRow(data *data.IssueData) {
 	if data == nil {
 		m.issue = nil
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/issuessection"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
@@ -28,7 +29,7 @@ func (m *Model) unassign(usernames []string) tea.Cmd {
 		"edit",
 		fmt.Sprint(issueNumber),
 		"-R",
-		issue.GetRepoNameWithOwner(),
+		data.GitHubRepoArg(m.provider, issue.GetRepoNameWithOwner()),
 	}
 	for _, assignee := range usernames {
 		commandArgs = append(commandArgs, "--remove-assignee")
@@ -37,22 +38,36 @@ func (m *Model) unassign(usernames []string) tea.Cmd {
 
 	startCmd := m.ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command("gh", commandArgs...)
+		provider, err := data.GetProvider(m.provider)
+		assigner, ok := provider.(providers.Assigner)
+		switch {
+		case err != nil:
+		case ok:
+			err = assigner.RemoveAssignees(issueNumber, issue.GetRepoNameWithOwner(), usernames)
+		case provider == nil:
+			c := exec.Command("gh", commandArgs...)
+			err = c.Run()
+		default:
+			err = fmt.Errorf("%s doesn't support unassigning issues", provider.GetType())
+		}
 
-		err := c.Run()
-		returnedAssignees := data.Assignees{Nodes: []data.Assignee{}}
-		for _, assignee := range usernames {
-			returnedAssignees.Nodes = append(returnedAssignees.Nodes, data.Assignee{Login: assignee})
+		var msg tea.Msg
+		if err == nil {
+			returnedAssignees := data.Assignees{Nodes: []data.Assignee{}}
+			for _, assignee := range usernames {
+				returnedAssignees.Nodes = append(returnedAssignees.Nodes, data.Assignee{Login: assignee})
+			}
+			msg = issuessection.UpdateIssueMsg{
+				IssueNumber:      issueNumber,
+				RemovedAssignees: &returnedAssignees,
+			}
 		}
 		return constants.TaskFinishedMsg{
 			SectionId:   m.sectionId,
 			SectionType: issuessection.SectionType,
 			TaskId:      taskId,
 			Err:         err,
-			Msg: issuessection.UpdateIssueMsg{
-				IssueNumber:      issueNumber,
-				RemovedAssignees: &returnedAssignees,
-			},
+			Msg:         msg,
 		}
 	})
 }
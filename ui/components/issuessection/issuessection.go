@@ -2,10 +2,12 @@ package issuessection
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/data"
@@ -151,13 +153,44 @@ func (m Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	case SectionIssuesFetchedMsg:
 		if m.LastFetchTaskId == msg.TaskId {
 			if m.PageInfo != nil {
-				m.Issues = append(m.Issues, msg.Issues...)
+				m.Issues = data.AppendNewRows(m.Issues, msg.Issues)
 			} else {
 				m.Issues = msg.Issues
+				m.LastFetchedAt = msg.FetchedAt
+				m.LastFullFetchAt = msg.FetchedAt
 			}
 			m.TotalCount = msg.TotalCount
 			m.SetIsLoading(false)
 			m.PageInfo = &msg.PageInfo
+			m.CachedAt = time.Time{}
+			m.Table.SetRows(m.BuildRows())
+			m.UpdateLastUpdated(time.Now())
+			m.UpdateTotalItemsCount(m.TotalCount)
+		}
+
+	case cachedIssuesLoadedMsg:
+		if m.LastFetchTaskId == msg.TaskId && m.PageInfo == nil {
+			m.Issues = msg.Res.Issues
+			m.TotalCount = msg.Res.TotalCount
+			m.CachedAt = msg.SavedAt
+			m.Table.SetRows(m.BuildRows())
+			m.UpdateLastUpdated(msg.SavedAt)
+			m.UpdateTotalItemsCount(m.TotalCount)
+		}
+
+	case SectionIssuesUpdatedMsg:
+		if m.LastFetchTaskId == msg.TaskId {
+			issues, ok := data.MergeUpdatedRows(m.Issues, msg.Issues, m.TotalCount, msg.TotalCount)
+			if msg.HasMore || !ok {
+				log.Debug("Reloading the section, the updated issues can't be merged", "id", m.Id, "hasMore", msg.HasMore)
+				m.PageInfo = nil
+				cmd = tea.Batch(m.FetchNextPageSectionRows()...)
+				break
+			}
+			m.Issues = issues
+			m.TotalCount = msg.TotalCount
+			m.LastFetchedAt = msg.FetchedAt
+			m.SetIsLoading(false)
 			m.Table.SetRows(m.BuildRows())
 			m.UpdateLastUpdated(time.Now())
 			m.UpdateTotalItemsCount(m.TotalCount)
@@ -299,7 +332,8 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 		startCursor = m.PageInfo.StartCursor
 	}
 	taskId := fmt.Sprintf("fetching_issues_%d_%s", m.Id, startCursor)
-	m.LastFetchTaskId = taskId
+	isFirstFetch := m.LastFetchTaskId == ""
+	fetchCtx, done := m.StartFetch(taskId)
 	task := context.Task{
 		Id:        taskId,
 		StartText: fmt.Sprintf(`Fetching issues for "%s"`, m.Config.Title),
@@ -313,12 +347,24 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 	startCmd := m.Ctx.StartTask(task)
 	cmds = append(cmds, startCmd)
 
+	limit := m.Config.Limit
+	if limit == nil {
+		limit = &m.Ctx.Config.Defaults.IssuesLimit
+	}
+	filters := m.GetFilters()
+	isFirstPage := m.PageInfo == nil
+	isCached := isFirstPage && m.IsConfiguredSearch()
+	if isFirstFetch && isCached {
+		cmds = append(cmds, m.loadCachedRows(taskId, filters, *limit))
+	}
+
 	fetchCmd := func() tea.Msg {
-		limit := m.Config.Limit
-		if limit == nil {
-			limit = &m.Ctx.Config.Defaults.IssuesLimit
+		defer done()
+		fetchedAt := time.Now()
+		res, err := data.FetchIssues(fetchCtx, m.Config.Provider, filters, *limit, m.PageInfo)
+		if section.IsCancelled(err) {
+			return constants.ClearTaskMsg{TaskId: taskId}
 		}
-		res, err := data.FetchIssues(m.GetFilters(), *limit, m.PageInfo)
 		if err != nil {
 			return constants.TaskFinishedMsg{
 				SectionId:   m.Id,
@@ -327,24 +373,120 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 				Err:         err,
 			}
 		}
+		if isCached {
+			data.SaveCachedIssues(m.Config.Provider, filters, *limit, res)
+		}
 
 		return constants.TaskFinishedMsg{
 			SectionId:   m.Id,
 			SectionType: m.Type,
 			TaskId:      taskId,
+			Warning:     strings.Join(res.Warnings, "; "),
 			Msg: SectionIssuesFetchedMsg{
 				Issues:     res.Issues,
 				TotalCount: res.TotalCount,
 				PageInfo:   res.PageInfo,
 				TaskId:     taskId,
+				FetchedAt:  fetchedAt,
 			},
 		}
 	}
 	cmds = append(cmds, fetchCmd)
+	m.IsLoading = true
 
 	return cmds
 }
 
+// FetchUpdatedSectionRows refreshes the section by fetching only the issues
+// updated since the last fetch, or by reloading its first page when it can't.
+// The rows shown are kept until the fetch finishes. Issues that stopped matching
+// aren't among the updated ones, they are dropped by the reload that follows when
+// the count of the section tells some did, see data.MergeUpdatedRows
+func (m *Model) FetchUpdatedSectionRows() []tea.Cmd {
+	if m == nil {
+		return nil
+	}
+	updatedFilters, ok := m.UpdatedSinceFilters()
+	if !ok {
+		m.PageInfo = nil
+		return m.FetchNextPageSectionRows()
+	}
+
+	taskId := fmt.Sprintf("fetching_updated_issues_%d_%s", m.Id, time.Now().String())
+	fetchCtx, done := m.StartFetch(taskId)
+	task := context.Task{
+		Id:        taskId,
+		StartText: fmt.Sprintf(`Fetching updated issues for "%s"`, m.Config.Title),
+		FinishedText: fmt.Sprintf(
+			`Issues for "%s" have been updated`,
+			m.Config.Title,
+		),
+		State: context.TaskStart,
+		Error: nil,
+	}
+	startCmd := m.Ctx.StartTask(task)
+
+	limit := m.Config.Limit
+	if limit == nil {
+		limit = &m.Ctx.Config.Defaults.IssuesLimit
+	}
+	filters := m.GetFilters()
+	fetchCmd := func() tea.Msg {
+		defer done()
+		fetchedAt := time.Now()
+		updated, totalCount, err := data.FetchUpdatedIssues(fetchCtx, m.Config.Provider, filters, updatedFilters, *limit)
+		if section.IsCancelled(err) {
+			return constants.ClearTaskMsg{TaskId: taskId}
+		}
+		if err != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:   m.Id,
+				SectionType: m.Type,
+				TaskId:      taskId,
+				Err:         err,
+			}
+		}
+
+		return constants.TaskFinishedMsg{
+			SectionId:   m.Id,
+			SectionType: m.Type,
+			TaskId:      taskId,
+			Warning:     strings.Join(updated.Warnings, "; "),
+			Msg: SectionIssuesUpdatedMsg{
+				Issues:     updated.Issues,
+				HasMore:    updated.PageInfo.HasNextPage,
+				TotalCount: totalCount,
+				TaskId:     taskId,
+				FetchedAt:  fetchedAt,
+			},
+		}
+	}
+
+	m.IsLoading = true
+	return []tea.Cmd{startCmd, fetchCmd}
+}
+
+// cachedIssuesLoadedMsg carries the issues of the previous run
+type cachedIssuesLoadedMsg struct {
+	TaskId  string
+	Res     data.IssuesResponse
+	SavedAt time.Time
+}
+
+// loadCachedRows loads the issues of the previous run, which are shown until
+// the fetch of the task finishes. They are loaded next to the fetch, as finding
+// the account they are cached under may take a request
+func (m *Model) loadCachedRows(taskId string, filters string, limit int) tea.Cmd {
+	provider := m.Config.Provider
+	return m.MakeSectionCmd(func() tea.Msg {
+		res, savedAt, ok := data.LoadCachedIssues(provider, filters, limit)
+		if !ok {
+			return nil
+		}
+		return cachedIssuesLoadedMsg{TaskId: taskId, Res: res, SavedAt: savedAt}
+	})
+}
+
 func (m *Model) UpdateLastUpdated(t time.Time) {
 	m.Table.UpdateLastUpdated(t)
 }
@@ -356,6 +498,7 @@ func (m *Model) ResetRows() {
 
 func FetchAllSections(
 	ctx *context.ProgramContext,
+	issues []section.Section,
 ) (sections []section.Section, fetchAllCmd tea.Cmd) {
 	sectionConfigs := ctx.Config.IssuesSections
 	fetchIssuesCmds := make([]tea.Cmd, 0, len(sectionConfigs))
@@ -368,13 +511,26 @@ func FetchAllSections(
 			time.Now(),
 			time.Now(),
 		) // 0 is the search section
+		if len(issues) > 0 && len(issues) >= i+1 && issues[i+1] != nil {
+			oldSection := issues[i+1].(*Model)
+			sectionModel.Issues = oldSection.Issues
+			sectionModel.LastFetchTaskId = oldSection.LastFetchTaskId
+			sectionModel.CachedAt = oldSection.CachedAt
+			if oldSection.Config.Provider == sectionModel.Config.Provider &&
+				oldSection.GetFilters() == sectionModel.GetFilters() {
+				sectionModel.TotalCount = oldSection.TotalCount
+				sectionModel.PageInfo = oldSection.PageInfo
+				sectionModel.LastFetchedAt = oldSection.LastFetchedAt
+				sectionModel.LastFullFetchAt = oldSection.LastFullFetchAt
+			}
+		}
 		if sectionConfig.Layout.CreatorIcon.Hidden != nil {
 			sectionModel.ShowAuthorIcon = !*sectionConfig.Layout.CreatorIcon.Hidden
 		}
 		sections = append(sections, &sectionModel)
 		fetchIssuesCmds = append(
 			fetchIssuesCmds,
-			sectionModel.FetchNextPageSectionRows()...)
+			sectionModel.FetchUpdatedSectionRows()...)
 	}
 	return sections, tea.Batch(fetchIssuesCmds...)
 }
@@ -384,6 +540,17 @@ type SectionIssuesFetchedMsg struct {
 	TotalCount int
 	PageInfo   data.PageInfo
 	TaskId     string
+	FetchedAt  time.Time
+}
+
+// SectionIssuesUpdatedMsg carries the issues updated since the last fetch, and
+// the number of issues matching the section now
+type SectionIssuesUpdatedMsg struct {
+	Issues     []data.IssueData
+	HasMore    bool
+	TotalCount int
+	TaskId     string
+	FetchedAt  time.Time
 }
 
 type UpdateIssueMsg struct {
@@ -450,14 +617,18 @@ func (m *Model) SetIsLoading(val bool) {
 
 func (m Model) GetPagerContent() string {
 	pagerContent := ""
-	if m.TotalCount > 0 {
+	lastUpdated := m.LastUpdated().Format("01/02 15:04:05")
+	if staleText := m.StaleText(); staleText != "" {
+		lastUpdated = staleText
+	}
+	if m.TotalCount != 0 {
 		pagerContent = fmt.Sprintf(
 			"%v %v • %v %v/%v • Fetched %v",
 			constants.WaitingIcon,
-			m.LastUpdated().Format("01/02 15:04:05"),
+			lastUpdated,
 			m.SingularForm,
 			m.Table.GetCurrItem()+1,
-			m.TotalCount,
+			m.TotalCountText(),
 			len(m.Table.Rows),
 		)
 	}
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
 	"github.com/dlvhdr/gh-dash/v4/utils"
@@ -22,26 +24,41 @@ func (m *Model) reopen() tea.Cmd {
 		Error:        nil,
 	}
 	startCmd := m.Ctx.StartTask(task)
+	repoNameWithOwner := issue.GetRepoNameWithOwner()
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command(
-			"gh",
-			"issue",
-			"reopen",
-			fmt.Sprint(m.GetCurrRow().GetNumber()),
-			"-R",
-			m.GetCurrRow().GetRepoNameWithOwner(),
-		)
+		provider, err := data.GetProvider(m.Config.Provider)
+		actions, ok := provider.(providers.IssueActions)
+		switch {
+		case err != nil:
+		case ok:
+			err = actions.ReopenIssue(issueNumber, repoNameWithOwner)
+		case provider == nil:
+			c := exec.Command(
+				"gh",
+				"issue",
+				"reopen",
+				fmt.Sprint(issueNumber),
+				"-R",
+				data.GitHubRepoArg(m.Config.Provider, repoNameWithOwner),
+			)
+			err = c.Run()
+		default:
+			err = fmt.Errorf("%s doesn't support reopening issues", provider.GetType())
+		}
 
-		err := c.Run()
+		var msg tea.Msg
+		if err == nil {
+			msg = UpdateIssueMsg{
+				IssueNumber: issueNumber,
+				IsClosed:    utils.BoolPtr(false),
+			}
+		}
 		return constants.TaskFinishedMsg{
 			SectionId:   m.Id,
 			SectionType: SectionType,
 			TaskId:      taskId,
 			Err:         err,
-			Msg: UpdateIssueMsg{
-				IssueNumber: issueNumber,
-				IsClosed:    utils.BoolPtr(false),
-			},
+			Msg:         msg,
 		}
 	})
 }
@@ -6,6 +6,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/prssection"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/tasks"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
@@ -28,7 +30,7 @@ func (m *Model) approve(comment string) tea.Cmd {
 		"pr",
 		"review",
 		"-R",
-		pr.GetRepoNameWithOwner(),
+		data.GitHubRepoArg(m.provider, pr.GetRepoNameWithOwner()),
 		fmt.Sprint(prNumber),
 		"--approve",
 	}
@@ -38,9 +40,21 @@ func (m *Model) approve(comment string) tea.Cmd {
 
 	startCmd := m.ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command("gh", commandArgs...)
-
-		err := c.Run()
+		provider, err := data.GetProvider(m.provider)
+		if err != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:   m.sectionId,
+				SectionType: prssection.SectionType,
+				TaskId:      taskId,
+				Err:         err,
+			}
+		}
+		if actions, ok := provider.(providers.PullRequestActions); ok {
+			err = actions.ApprovePullRequest(prNumber, pr.GetRepoNameWithOwner(), comment)
+		} else {
+			c := exec.Command("gh", commandArgs...)
+			err = c.Run()
+		}
 		return constants.TaskFinishedMsg{
 			SectionId:   m.sectionId,
 			SectionType: prssection.SectionType,
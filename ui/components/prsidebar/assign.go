@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/prssection"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/tasks"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
@@ -30,7 +31,7 @@ func (m *Model) assign(usernames []string) tea.Cmd {
 		"edit",
 		fmt.Sprint(prNumber),
 		"-R",
-		pr.GetRepoNameWithOwner(),
+		data.GitHubRepoArg(m.provider, pr.GetRepoNameWithOwner()),
 	}
 	for _, assignee := range usernames {
 		commandArgs = append(commandArgs, "--add-assignee")
@@ -39,22 +40,36 @@ func (m *Model) assign(usernames []string) tea.Cmd {
 
 	startCmd := m.ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command("gh", commandArgs...)
+		provider, err := data.GetProvider(m.provider)
+		assigner, ok := provider.(providers.Assigner)
+		switch {
+		case err != nil:
+		case ok:
+			err = assigner.AddAssignees(prNumber, pr.GetRepoNameWithOwner(), usernames)
+		case provider == nil:
+			c := exec.Command("gh", commandArgs...)
+			err = c.Run()
+		default:
+			err = fmt.Errorf("%s doesn't support assigning pull requests", provider.GetType())
+		}
 
-		err := c.Run()
-		returnedAssignees := data.Assignees{Nodes: []data.Assignee{}}
-		for _, assignee := range usernames {
-			returnedAssignees.Nodes = append(returnedAssignees.Nodes, data.Assignee{Login: assignee})
+		var msg tea.Msg
+		if err == nil {
+			returnedAssignees := data.Assignees{Nodes: []data.Assignee{}}
+			for _, assignee := range usernames {
+				returnedAssignees.Nodes = append(returnedAssignees.Nodes, data.Assignee{Login: assignee})
+			}
+			msg = tasks.UpdatePRMsg{
+				PrNumber:       prNumber,
+				AddedAssignees: &returnedAssignees,
+			}
 		}
 		return constants.TaskFinishedMsg{
 			SectionId:   m.sectionId,
 			SectionType: prssection.SectionType,
 			TaskId:      taskId,
 			Err:         err,
-			Msg: tasks.UpdatePRMsg{
-				PrNumber:       prNumber,
-				AddedAssignees: &returnedAssignees,
-			},
+			Msg:         msg,
 		}
 	})
 }
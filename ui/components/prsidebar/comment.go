@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/prssection"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/tasks"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
@@ -27,31 +28,45 @@ func (m *Model) comment(body string) tea.Cmd {
 	}
 	startCmd := m.ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		c := exec.Command(
-			"gh",
-			"pr",
-			"comment",
-			fmt.Sprint(prNumber),
-			"-R",
-			pr.GetRepoNameWithOwner(),
-			"-b",
-			body,
-		)
+		provider, err := data.GetProvider(m.provider)
+		commenter, ok := provider.(providers.Commenter)
+		switch {
+		case err != nil:
+		case ok:
+			err = commenter.AddComment(prNumber, pr.GetRepoNameWithOwner(), body)
+		case provider == nil:
+			c := exec.Command(
+				"gh",
+				"pr",
+				"comment",
+				fmt.Sprint(prNumber),
+				"-R",
+				data.GitHubRepoArg(m.provider, pr.GetRepoNameWithOwner()),
+				"-b",
+				body,
+			)
+			err = c.Run()
+		default:
+			err = fmt.Errorf("%s doesn't support commenting on pull requests", provider.GetType())
+		}
 
-		err := c.Run()
-		return constants.TaskFinishedMsg{
-			SectionId:   m.sectionId,
-			SectionType: prssection.SectionType,
-			TaskId:      taskId,
-			Err:         err,
-			Msg: tasks.UpdatePRMsg{
+		var msg tea.Msg
+		if err == nil {
+			msg = tasks.UpdatePRMsg{
 				PrNumber: prNumber,
 				NewComment: &data.Comment{
 					Author:    struct{ Login string }{Login: m.ctx.User},
 					Body:      body,
 					UpdatedAt: time.Now(),
 				},
-			},
+			}
+		}
+		return constants.TaskFinishedMsg{
+			SectionId:   m.sectionId,
+			SectionType: prssection.SectionType,
+			TaskId:      taskId,
+			Err:         err,
+			Msg:         msg,
 		}
 	})
 }
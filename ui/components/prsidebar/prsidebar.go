@@ -32,6 +32,7 @@ var (
 type Model struct {
 	ctx       *context.ProgramContext
 	sectionId int
+	provider  string
 	pr        *pr.PullRequest
 	width     int
 	carousel  carousel.Model
@@ -389,6 +390,11 @@ func (m *Model) SetSectionId(id int) {
 	m.sectionId = id
 }
 
+// SetProvider sets the provider profile of the section the pull request belongs to
+func (m *Model) SetProvider(provider string) {
+	m.provider = provider
+}
+
 func (m *Model) SetRow(d *data.PullRequestData) {
 	if d == nil {
 		m.pr = nil
@@ -8,7 +8,11 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/git"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/common"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
@@ -38,12 +42,27 @@ func (m *Model) checkout() (tea.Cmd, error) {
 	}
 	startCmd := m.Ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		// Get the current provider and use provider-specific checkout command
-		provider := data.GetCurrentProvider()
+		userHomeDir, _ := os.UserHomeDir()
+		if strings.HasPrefix(repoPath, "~") {
+			repoPath = strings.Replace(repoPath, "~", userHomeDir, 1)
+		}
+
+		// Get the provider of the section and use provider-specific checkout command
+		provider, err := data.GetProvider(m.Config.Provider)
+		if err != nil {
+			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
+		}
 		var c *exec.Cmd
-		var err error
-		
-		if provider != nil {
+
+		if gitCheckout, ok := provider.(providers.GitCheckout); ok {
+			sourceBranch, isFork := "", false
+			if prData, ok := pr.(*data.PullRequestData); ok {
+				sourceBranch, isFork = prData.HeadRefName, prData.IsCrossRepository
+			}
+			branch, err := git.CheckoutPullRequest(repoPath, m.Ctx.Config.Repo.Remotes, prNumber, sourceBranch, isFork, gitCheckout.PullRequestRef(prNumber))
+			log.Debug("Checked out pull request with git", "pr", prNumber, "branch", branch, "err", err)
+			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
+		} else if provider != nil {
 			// Use provider-specific command
 			args, cmdErr := provider.GetCheckoutCommand(prNumber, repoName)
 			if cmdErr != nil {
@@ -69,11 +88,6 @@ func (m *Model) checkout() (tea.Cmd, error) {
 				fmt.Sprint(prNumber),
 			)
 		}
-		
-		userHomeDir, _ := os.UserHomeDir()
-		if strings.HasPrefix(repoPath, "~") {
-			repoPath = strings.Replace(repoPath, "~", userHomeDir, 1)
-		}
 
 		c.Dir = repoPath
 		err = c.Run()
@@ -0,0 +1,41 @@
+package prssection
+
+import (
+	gocontext "context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dlvhdr/gh-dash/v4/data"
+)
+
+// pullRequestDetailsFetchedMsg carries a pull request fetched with the changed
+// files and comments its listed row lacks
+type pullRequestDetailsFetchedMsg struct {
+	Url string
+	Pr  data.PullRequestData
+	Err error
+}
+
+// FetchCurrRowDetails fetches the changed files and comments of the current
+// pull request for the sidebar, when the provider of the section lists pull
+// requests without them. Every pull request is only fetched once
+func (m *Model) FetchCurrRowDetails() tea.Cmd {
+	row := m.GetCurrRow()
+	if row == nil || data.GetCapabilities(m.Config.Provider).ListsDetails {
+		return nil
+	}
+	url := row.GetUrl()
+	if m.detailsFetched[url] {
+		return nil
+	}
+	m.detailsFetched[url] = true
+
+	provider := m.Config.Provider
+	defaults := m.Ctx.Config.Defaults
+	return m.MakeSectionCmd(func() tea.Msg {
+		ctx, cancel := defaults.RequestContext(gocontext.Background())
+		defer cancel()
+		pr, err := data.FetchPullRequest(ctx, provider, url)
+		return pullRequestDetailsFetchedMsg{Url: url, Pr: pr, Err: err}
+	})
+}
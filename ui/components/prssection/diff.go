@@ -15,8 +15,13 @@ func (m Model) diff() tea.Cmd {
 		return nil
 	}
 
-	// Get the current provider and use provider-specific diff command
-	provider := data.GetCurrentProvider()
+	// Get the provider of the section and use provider-specific diff command
+	provider, err := data.GetProvider(m.Config.Provider)
+	if err != nil {
+		return func() tea.Msg {
+			return constants.ErrMsg{Err: err}
+		}
+	}
 	if provider == nil {
 		// Fallback to the original GitHub command
 		return m.executeGitHubDiffCommand(currRowData)
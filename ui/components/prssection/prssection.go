@@ -2,10 +2,12 @@ package prssection
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/data"
@@ -24,6 +26,9 @@ const SectionType = "pr"
 type Model struct {
 	section.BaseModel
 	Prs []data.PullRequestData
+	// detailsFetched holds the URLs of the pull requests whose details were
+	// fetched for the sidebar
+	detailsFetched map[string]bool
 }
 
 func NewModel(
@@ -48,6 +53,7 @@ func NewModel(
 		},
 	)
 	m.Prs = []data.PullRequestData{}
+	m.detailsFetched = map[string]bool{}
 
 	return m
 }
@@ -90,7 +96,7 @@ func (m Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 				input := m.PromptConfirmationBox.Value()
 				action := m.GetPromptConfirmationAction()
 				pr := m.GetCurrRow()
-				sid := tasks.SectionIdentifier{Id: m.Id, Type: SectionType}
+				sid := tasks.SectionIdentifier{Id: m.Id, Type: SectionType, Provider: m.Config.Provider}
 				if input == "Y" || input == "y" {
 					switch action {
 					case "close":
@@ -180,18 +186,68 @@ func (m Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
 	case SectionPullRequestsFetchedMsg:
 		if m.LastFetchTaskId == msg.TaskId {
 			if m.PageInfo != nil {
-				m.Prs = append(m.Prs, msg.Prs...)
+				m.Prs = data.AppendNewRows(m.Prs, msg.Prs)
 			} else {
 				m.Prs = msg.Prs
+				m.LastFetchedAt = msg.FetchedAt
+				m.LastFullFetchAt = msg.FetchedAt
+				clear(m.detailsFetched)
 			}
 			m.TotalCount = msg.TotalCount
 			m.PageInfo = &msg.PageInfo
+			m.CachedAt = time.Time{}
 			m.SetIsLoading(false)
 			m.Table.SetRows(m.BuildRows())
 			m.Table.UpdateLastUpdated(time.Now())
 			m.UpdateTotalItemsCount(m.TotalCount)
 
 		}
+
+	case SectionPullRequestsUpdatedMsg:
+		if m.LastFetchTaskId == msg.TaskId {
+			prs, ok := data.MergeUpdatedRows(m.Prs, msg.Prs, m.TotalCount, msg.TotalCount)
+			if msg.HasMore || !ok {
+				log.Debug("Reloading the section, the updated PRs can't be merged", "id", m.Id, "hasMore", msg.HasMore)
+				m.PageInfo = nil
+				cmd = tea.Batch(m.FetchNextPageSectionRows()...)
+				break
+			}
+			m.Prs = prs
+			for _, pr := range msg.Prs {
+				delete(m.detailsFetched, pr.Url)
+			}
+			m.TotalCount = msg.TotalCount
+			m.LastFetchedAt = msg.FetchedAt
+			m.SetIsLoading(false)
+			m.Table.SetRows(m.BuildRows())
+			m.Table.UpdateLastUpdated(time.Now())
+			m.UpdateTotalItemsCount(m.TotalCount)
+		}
+
+	case cachedPullRequestsLoadedMsg:
+		if m.LastFetchTaskId == msg.TaskId && m.PageInfo == nil {
+			m.Prs = msg.Res.Prs
+			m.TotalCount = msg.Res.TotalCount
+			m.CachedAt = msg.SavedAt
+			m.Table.SetIsLoading(false)
+			m.Table.SetRows(m.BuildRows())
+			m.Table.UpdateLastUpdated(msg.SavedAt)
+			m.UpdateTotalItemsCount(m.TotalCount)
+		}
+
+	case pullRequestDetailsFetchedMsg:
+		if msg.Err != nil {
+			log.Debug("Failed fetching PR details", "url", msg.Url, "err", msg.Err)
+			delete(m.detailsFetched, msg.Url)
+			break
+		}
+		for i, currPr := range m.Prs {
+			if currPr.Url == msg.Url {
+				m.Prs[i] = msg.Pr
+				m.Table.SetRows(m.BuildRows())
+				break
+			}
+		}
 	}
 
 	search, searchCmd := m.SearchBar.Update(msg)
@@ -377,6 +433,17 @@ type SectionPullRequestsFetchedMsg struct {
 	TotalCount int
 	PageInfo   data.PageInfo
 	TaskId     string
+	FetchedAt  time.Time
+}
+
+// SectionPullRequestsUpdatedMsg carries the pull requests updated since the
+// last fetch, and the number of pull requests matching the section now
+type SectionPullRequestsUpdatedMsg struct {
+	Prs        []data.PullRequestData
+	HasMore    bool
+	TotalCount int
+	TaskId     string
+	FetchedAt  time.Time
 }
 
 func (m *Model) GetCurrRow() data.RowData {
@@ -404,7 +471,7 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 	}
 	taskId := fmt.Sprintf("fetching_prs_%d_%s", m.Id, startCursor)
 	isFirstFetch := m.LastFetchTaskId == ""
-	m.LastFetchTaskId = taskId
+	fetchCtx, done := m.StartFetch(taskId)
 	task := context.Task{
 		Id:        taskId,
 		StartText: fmt.Sprintf(`Fetching PRs for "%s"`, m.Config.Title),
@@ -418,13 +485,24 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 	startCmd := m.Ctx.StartTask(task)
 	cmds = append(cmds, startCmd)
 
+	limit := m.Config.Limit
+	if limit == nil {
+		limit = &m.Ctx.Config.Defaults.PrsLimit
+	}
+	filters := m.GetFilters()
+	isFirstPage := m.PageInfo == nil
+	isCached := isFirstPage && m.IsConfiguredSearch()
+	if isFirstFetch && isCached {
+		cmds = append(cmds, m.loadCachedRows(taskId, filters, *limit))
+	}
+
 	fetchCmd := func() tea.Msg {
-		limit := m.Config.Limit
-		if limit == nil {
-			limit = &m.Ctx.Config.Defaults.PrsLimit
+		defer done()
+		fetchedAt := time.Now()
+		res, err := data.FetchPullRequests(fetchCtx, m.Config.Provider, filters, *limit, m.PageInfo)
+		if section.IsCancelled(err) {
+			return constants.ClearTaskMsg{TaskId: taskId}
 		}
-
-		res, err := data.FetchPullRequests(m.GetFilters(), *limit, m.PageInfo)
 		if err != nil {
 			return constants.TaskFinishedMsg{
 				SectionId:   m.Id,
@@ -433,23 +511,28 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 				Err:         err,
 			}
 		}
+		if isCached {
+			data.SaveCachedPullRequests(m.Config.Provider, filters, *limit, res)
+		}
 
 		return constants.TaskFinishedMsg{
 			SectionId:   m.Id,
 			SectionType: m.Type,
 			TaskId:      taskId,
+			Warning:     strings.Join(res.Warnings, "; "),
 			Msg: SectionPullRequestsFetchedMsg{
 				Prs:        res.Prs,
 				TotalCount: res.TotalCount,
 				PageInfo:   res.PageInfo,
 				TaskId:     taskId,
+				FetchedAt:  fetchedAt,
 			},
 		}
 	}
 	cmds = append(cmds, fetchCmd)
 
 	m.IsLoading = true
-	if isFirstFetch {
+	if isFirstFetch && m.CachedAt.IsZero() {
 		m.SetIsLoading(true)
 		cmds = append(cmds, m.Table.StartLoadingSpinner())
 	}
@@ -457,8 +540,100 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 	return cmds
 }
 
+// FetchUpdatedSectionRows refreshes the section by fetching only the pull
+// requests updated since the last fetch, or by reloading its first page when it
+// can't. The rows shown are kept until the fetch finishes. Pull requests that
+// stopped matching aren't among the updated ones, they are dropped by the reload
+// that follows when the count of the section tells some did, see
+// data.MergeUpdatedRows
+func (m *Model) FetchUpdatedSectionRows() []tea.Cmd {
+	if m == nil {
+		return nil
+	}
+	updatedFilters, ok := m.UpdatedSinceFilters()
+	if !ok {
+		m.PageInfo = nil
+		return m.FetchNextPageSectionRows()
+	}
+
+	taskId := fmt.Sprintf("fetching_updated_prs_%d_%s", m.Id, time.Now().String())
+	fetchCtx, done := m.StartFetch(taskId)
+	task := context.Task{
+		Id:        taskId,
+		StartText: fmt.Sprintf(`Fetching updated PRs for "%s"`, m.Config.Title),
+		FinishedText: fmt.Sprintf(
+			`PRs for "%s" have been updated`,
+			m.Config.Title,
+		),
+		State: context.TaskStart,
+		Error: nil,
+	}
+	startCmd := m.Ctx.StartTask(task)
+
+	limit := m.Config.Limit
+	if limit == nil {
+		limit = &m.Ctx.Config.Defaults.PrsLimit
+	}
+	filters := m.GetFilters()
+	fetchCmd := func() tea.Msg {
+		defer done()
+		fetchedAt := time.Now()
+		updated, totalCount, err := data.FetchUpdatedPullRequests(fetchCtx, m.Config.Provider, filters, updatedFilters, *limit)
+		if section.IsCancelled(err) {
+			return constants.ClearTaskMsg{TaskId: taskId}
+		}
+		if err != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:   m.Id,
+				SectionType: m.Type,
+				TaskId:      taskId,
+				Err:         err,
+			}
+		}
+
+		return constants.TaskFinishedMsg{
+			SectionId:   m.Id,
+			SectionType: m.Type,
+			TaskId:      taskId,
+			Warning:     strings.Join(updated.Warnings, "; "),
+			Msg: SectionPullRequestsUpdatedMsg{
+				Prs:        updated.Prs,
+				HasMore:    updated.PageInfo.HasNextPage,
+				TotalCount: totalCount,
+				TaskId:     taskId,
+				FetchedAt:  fetchedAt,
+			},
+		}
+	}
+
+	m.IsLoading = true
+	return []tea.Cmd{startCmd, fetchCmd}
+}
+
+// cachedPullRequestsLoadedMsg carries the pull requests of the previous run
+type cachedPullRequestsLoadedMsg struct {
+	TaskId  string
+	Res     data.PullRequestsResponse
+	SavedAt time.Time
+}
+
+// loadCachedRows loads the pull requests of the previous run, which are shown
+// until the fetch of the task finishes. They are loaded next to the fetch, as
+// finding the account they are cached under may take a request
+func (m *Model) loadCachedRows(taskId string, filters string, limit int) tea.Cmd {
+	provider := m.Config.Provider
+	return m.MakeSectionCmd(func() tea.Msg {
+		res, savedAt, ok := data.LoadCachedPullRequests(provider, filters, limit)
+		if !ok {
+			return nil
+		}
+		return cachedPullRequestsLoadedMsg{TaskId: taskId, Res: res, SavedAt: savedAt}
+	})
+}
+
 func (m *Model) ResetRows() {
 	m.Prs = nil
+	clear(m.detailsFetched)
 	m.BaseModel.ResetRows()
 }
 
@@ -480,6 +655,14 @@ func FetchAllSections(
 			oldSection := prs[i+1].(*Model)
 			sectionModel.Prs = oldSection.Prs
 			sectionModel.LastFetchTaskId = oldSection.LastFetchTaskId
+			sectionModel.CachedAt = oldSection.CachedAt
+			if oldSection.Config.Provider == sectionModel.Config.Provider &&
+				oldSection.GetFilters() == sectionModel.GetFilters() {
+				sectionModel.TotalCount = oldSection.TotalCount
+				sectionModel.PageInfo = oldSection.PageInfo
+				sectionModel.LastFetchedAt = oldSection.LastFetchedAt
+				sectionModel.LastFullFetchAt = oldSection.LastFullFetchAt
+			}
 		}
 		if sectionConfig.Layout.AuthorIcon.Hidden != nil {
 			sectionModel.ShowAuthorIcon = !*sectionConfig.Layout.AuthorIcon.Hidden
@@ -487,7 +670,7 @@ func FetchAllSections(
 		sections = append(sections, &sectionModel)
 		fetchPRsCmds = append(
 			fetchPRsCmds,
-			sectionModel.FetchNextPageSectionRows()...)
+			sectionModel.FetchUpdatedSectionRows()...)
 	}
 	return sections, tea.Batch(fetchPRsCmds...)
 }
@@ -550,14 +733,18 @@ func (m Model) GetPagerContent() string {
 	} else {
 		timeElapsed = fmt.Sprintf("~%v ago", timeElapsed)
 	}
-	if m.TotalCount > 0 {
+	lastUpdated := "Updated " + timeElapsed
+	if staleText := m.StaleText(); staleText != "" {
+		lastUpdated = staleText
+	}
+	if m.TotalCount != 0 {
 		pagerContent = fmt.Sprintf(
-			"%v Updated %v • %v %v/%v (fetched %v)",
+			"%v %v • %v %v/%v (fetched %v)",
 			constants.WaitingIcon,
-			timeElapsed,
+			lastUpdated,
 			m.SingularForm,
 			m.Table.GetCurrItem()+1,
-			m.TotalCount,
+			m.TotalCountText(),
 			len(m.Table.Rows),
 		)
 	}
@@ -2,6 +2,7 @@ package prssection
 
 import (
 	"bytes"
+	gocontext "context"
 	"fmt"
 	"os/exec"
 
@@ -36,8 +37,16 @@ func (m *Model) watchChecks() tea.Cmd {
 	}
 	startCmd := m.Ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
-		// Get the current provider and use provider-specific watch checks command
-		provider := data.GetCurrentProvider()
+		// Get the provider of the section and use provider-specific watch checks command
+		provider, err := data.GetProvider(m.Config.Provider)
+		if err != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:   m.Id,
+				SectionType: SectionType,
+				TaskId:      taskId,
+				Err:         err,
+			}
+		}
 		var c *exec.Cmd
 		
 		if provider != nil {
@@ -87,7 +96,7 @@ func (m *Model) watchChecks() tea.Cmd {
 		c.Stdout = &outb
 		c.Stderr = &errb
 
-		err := c.Start()
+		err = c.Start()
 		go func() {
 			err := c.Wait()
 			if err != nil {
@@ -95,7 +104,9 @@ func (m *Model) watchChecks() tea.Cmd {
 			}
 
 			// TODO: check for installation of terminal-notifier or alternative as logo isn't supported
-			updatedPr, err := data.FetchPullRequest(url)
+			fetchCtx, cancel := m.Ctx.Config.Defaults.RequestContext(gocontext.Background())
+			defer cancel()
+			updatedPr, err := data.FetchPullRequest(fetchCtx, m.Config.Provider, url)
 			if err != nil {
 				log.Debug("Error fetching updated PR details", "url", url, "err", err)
 			}
@@ -1,6 +1,7 @@
 package reposection
 
 import (
+	gocontext "context"
 	"fmt"
 	"sync"
 	"time"
@@ -40,7 +41,7 @@ func (m *Model) fastForward() (tea.Cmd, error) {
 	startCmd := m.Ctx.StartTask(task)
 	return tea.Batch(startCmd, func() tea.Msg {
 		var err error
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
@@ -48,21 +49,21 @@ func (m *Model) fastForward() (tea.Cmd, error) {
 		if b.Data.IsCheckedOut {
 			err = repo.Pull(gitm.PullOptions{
 				All:            false,
-				Remote:         "origin",
+				Remote:         repo.Remote,
 				Branch:         b.Data.Name,
 				CommandOptions: gitm.CommandOptions{Args: []string{"--ff-only", "--no-edit"}},
 			})
 		} else {
 			err = repo.Fetch(gitm.FetchOptions{CommandOptions: gitm.CommandOptions{Args: []string{
 				"--no-write-fetch-head",
-				"origin",
+				repo.Remote,
 				b.Data.Name + ":" + b.Data.Name,
 			}}})
 		}
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
-		repo, err = git.GetRepo(m.Ctx.RepoPath)
+		repo, err = git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
@@ -106,10 +107,14 @@ func (m *Model) push(opts pushOptions) (tea.Cmd, error) {
 			args = append(args, "--force")
 		}
 		if len(b.Data.Remotes) == 0 {
+			repo, repoErr := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
+			if repoErr != nil {
+				return constants.TaskFinishedMsg{TaskId: taskId, Err: repoErr}
+			}
 			args = append(args, "--set-upstream")
 			err = gitm.Push(
 				m.Ctx.RepoPath,
-				"origin",
+				repo.Remote,
 				b.Data.Name,
 				gitm.PushOptions{CommandOptions: gitm.CommandOptions{Args: args}},
 			)
@@ -124,7 +129,7 @@ func (m *Model) push(opts pushOptions) (tea.Cmd, error) {
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
@@ -156,7 +161,7 @@ func (m *Model) checkout() (tea.Cmd, error) {
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
@@ -191,7 +196,7 @@ func (m *Model) readRepoCmd() []tea.Cmd {
 		cmds = append(cmds, bCmd)
 	}
 	cmds = append(cmds, func() tea.Msg {
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: branchesTaskId, Err: err}
 		}
@@ -214,14 +219,14 @@ func (m *Model) fetchRepoCmd() []tea.Cmd {
 	}
 	fetchTask := context.Task{
 		Id:           fetchTaskId,
-		StartText:    "Fetching branches from origin",
-		FinishedText: "Fetched origin branches",
+		StartText:    "Fetching branches from the remotes",
+		FinishedText: "Fetched the branches of the remotes",
 		State:        context.TaskStart,
 		Error:        nil,
 	}
 	cmds = append(cmds, m.Ctx.StartTask(fetchTask))
 	cmds = append(cmds, func() tea.Msg {
-		repo, err := git.FetchRepo(m.Ctx.RepoPath)
+		repo, err := git.FetchRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: fetchTaskId, Err: err}
 		}
@@ -251,7 +256,9 @@ func (m *Model) fetchPRsCmd() tea.Cmd {
 		if limit == nil {
 			limit = &m.Ctx.Config.Defaults.PrsLimit
 		}
-		res, err := data.FetchPullRequests(fmt.Sprintf("author:@me repo:%s", git.GetRepoShortName(m.Ctx.RepoUrl)), *limit, nil)
+		fetchCtx, cancel := m.Ctx.Config.Defaults.RequestContext(gocontext.Background())
+		defer cancel()
+		res, err := data.FetchPullRequests(fetchCtx, "", fmt.Sprintf("author:@me repo:%s", git.GetRepoShortName(m.Ctx.RepoUrl)), *limit, nil)
 		if err != nil {
 			return constants.TaskFinishedMsg{
 				SectionId:   0,
@@ -285,7 +292,9 @@ func (m *Model) fetchPRCmd(branch string) []tea.Cmd {
 	}
 	startCmd := m.Ctx.StartTask(task)
 	return []tea.Cmd{startCmd, func() tea.Msg {
-		res, err := data.FetchPullRequests(fmt.Sprintf("author:@me repo:%s head:%s", git.GetRepoShortName(m.Ctx.RepoUrl), branch), 1, nil)
+		fetchCtx, cancel := m.Ctx.Config.Defaults.RequestContext(gocontext.Background())
+		defer cancel()
+		res, err := data.FetchPullRequests(fetchCtx, "", fmt.Sprintf("author:@me repo:%s head:%s", git.GetRepoShortName(m.Ctx.RepoUrl), branch), 1, nil)
 		log.Debug("Fetching PRs", "res", res)
 		if err != nil {
 			return constants.TaskFinishedMsg{
@@ -389,7 +398,7 @@ func (m *Model) deleteBranch() tea.Cmd {
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
@@ -419,7 +428,7 @@ func (m *Model) newBranch(name string) tea.Cmd {
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
-		repo, err := git.GetRepo(m.Ctx.RepoPath)
+		repo, err := git.GetRepo(m.Ctx.RepoPath, m.Ctx.Config.Repo.Remotes)
 		if err != nil {
 			return constants.TaskFinishedMsg{TaskId: taskId, Err: err}
 		}
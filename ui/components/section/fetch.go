@@ -0,0 +1,57 @@
+package section
+
+import (
+	gocontext "context"
+	"errors"
+	"sync"
+)
+
+// fetch is a running fetch of a section, registered under its task id
+type fetch struct {
+	cancel gocontext.CancelFunc
+}
+
+// Section models are recreated on refresh, so the running fetches are kept
+// here rather than on the models, which carry over the LastFetchTaskId
+var (
+	fetchesMu sync.Mutex
+	fetches   = map[string]*fetch{}
+)
+
+// StartFetch makes taskId the section's LastFetchTaskId, cancelling the fetch
+// it supersedes, and returns the context for the new fetch. done must be
+// called when the fetch returns
+func (m *BaseModel) StartFetch(taskId string) (ctx gocontext.Context, done func()) {
+	fetchesMu.Lock()
+	defer fetchesMu.Unlock()
+
+	if previous, ok := fetches[m.LastFetchTaskId]; ok && m.LastFetchTaskId != taskId {
+		previous.cancel()
+		delete(fetches, m.LastFetchTaskId)
+	}
+	m.LastFetchTaskId = taskId
+
+	var cancel gocontext.CancelFunc
+	if m.Ctx != nil && m.Ctx.Config != nil {
+		ctx, cancel = m.Ctx.Config.Defaults.RequestContext(gocontext.Background())
+	} else {
+		ctx, cancel = gocontext.WithCancel(gocontext.Background())
+	}
+	current := &fetch{cancel: cancel}
+	fetches[taskId] = current
+
+	return ctx, func() {
+		fetchesMu.Lock()
+		defer fetchesMu.Unlock()
+		if fetches[taskId] == current {
+			delete(fetches, taskId)
+		}
+		cancel()
+	}
+}
+
+// IsCancelled reports whether a fetch failed because a newer fetch superseded
+// it, rather than because of an error or a timeout
+func IsCancelled(err error) bool {
+	return errors.Is(err, gocontext.Canceled)
+}
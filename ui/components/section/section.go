@@ -3,6 +3,7 @@ package section
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"text/template"
 	"time"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/git"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/common"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/prompt"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/search"
@@ -46,6 +49,14 @@ type BaseModel struct {
 	ShowAuthorIcon            bool
 	IsFilteredByCurrentRemote bool
 	IsLoading                 bool
+	// CachedAt is when the rows shown were fetched, while they come from the
+	// cache of a previous run rather than from a fetch of this one
+	CachedAt time.Time
+	// LastFetchedAt is when the last fetch of the first page or of the rows
+	// updated since started, and LastFullFetchAt when the last fetch of the
+	// first page did
+	LastFetchedAt   time.Time
+	LastFullFetchAt time.Time
 }
 
 type NewSectionOptions struct {
@@ -62,10 +73,11 @@ type NewSectionOptions struct {
 
 func (options NewSectionOptions) GetConfigFiltersWithCurrentRemoteAdded(ctx *context.ProgramContext) string {
 	searchValue := options.Config.Filters
-	if !ctx.Config.SmartFilteringAtLaunch {
+	// The current remote belongs to the current provider, not to a section's provider profile
+	if !ctx.Config.SmartFilteringAtLaunch || options.Config.Provider != "" {
 		return searchValue
 	}
-	repo, err := repository.Current()
+	repo, err := currentRepo(ctx)
 	if err != nil {
 		return searchValue
 	}
@@ -77,6 +89,27 @@ func (options NewSectionOptions) GetConfigFiltersWithCurrentRemoteAdded(ctx *con
 	return fmt.Sprintf("repo:%s/%s %s", repo.Owner, repo.Name, searchValue)
 }
 
+// currentRepo returns the repository of the preferred remote of the current
+// clone, e.g. upstream in a fork. GH_REPO overrides it, like it does for gh
+func currentRepo(ctx *context.ProgramContext) (repository.Repository, error) {
+	if os.Getenv("GH_REPO") != "" {
+		return repository.Current()
+	}
+	dir := ctx.RepoPath
+	if dir == "" {
+		dir = "."
+	}
+	var remotes []string
+	if ctx.Config != nil {
+		remotes = ctx.Config.Repo.Remotes
+	}
+	url, err := git.GetRemoteUrl(dir, remotes)
+	if err != nil {
+		return repository.Repository{}, err
+	}
+	return repository.Parse(url)
+}
+
 func NewModel(
 	ctx *context.ProgramContext,
 	options NewSectionOptions,
@@ -142,6 +175,7 @@ type Section interface {
 type Identifier interface {
 	GetId() int
 	GetType() string
+	GetProvider() string
 }
 
 type Component interface {
@@ -198,9 +232,24 @@ func (m *BaseModel) HasRepoNameInConfiguredFilter() bool {
 	return false
 }
 
+// IsConfiguredSearch reports whether the section searches with the filters of
+// its config, with or without the current clone filter, rather than a typed
+// search. Only those are cached
+func (m *BaseModel) IsConfiguredSearch() bool {
+	if m.Id == 0 {
+		// the search section
+		return false
+	}
+	searchValue := m.SearchValue
+	if repo, err := currentRepo(m.Ctx); err == nil && !m.HasRepoNameInConfiguredFilter() {
+		searchValue = strings.Replace(searchValue, fmt.Sprintf("repo:%s/%s", repo.Owner, repo.Name), "", 1)
+	}
+	return strings.Join(strings.Fields(searchValue), " ") == strings.Join(strings.Fields(m.Config.Filters), " ")
+}
+
 func (m *BaseModel) GetSearchValue() string {
 	searchValue := m.enrichSearchWithTemplateVars()
-	repo, err := repository.Current()
+	repo, err := currentRepo(m.Ctx)
 	if err != nil {
 		return searchValue
 	}
@@ -270,6 +319,11 @@ func (m *BaseModel) GetType() string {
 	return m.Type
 }
 
+// GetProvider returns the provider profile of the section, empty for the current provider
+func (m *BaseModel) GetProvider() string {
+	return m.Config.Provider
+}
+
 func (m *BaseModel) CurrRow() int {
 	return m.Table.GetCurrItem()
 }
@@ -403,6 +457,8 @@ func (m *BaseModel) View() string {
 
 func (m *BaseModel) ResetRows() {
 	m.Table.Rows = nil
+	m.CachedAt = time.Time{}
+	m.LastFetchedAt = time.Time{}
 	m.ResetPageInfo()
 	m.Table.ResetCurrItem()
 }
@@ -411,6 +467,56 @@ func (m *BaseModel) LastUpdated() time.Time {
 	return m.Table.LastUpdated()
 }
 
+// updatedSinceOverlap is fetched again by every refresh of the updated rows, as
+// the search index lags behind the updates
+const updatedSinceOverlap = 5 * time.Minute
+
+// UpdatedSinceFilters returns the filters of the rows updated since the last
+// fetch, when a refresh can fetch only those rather than reloading the section.
+// Sections are reloaded every FullRefetchInterval, and when their provider or
+// their filters can't tell when rows were updated
+func (m *BaseModel) UpdatedSinceFilters() (string, bool) {
+	if m.LastFetchedAt.IsZero() || m.PageInfo == nil {
+		return "", false
+	}
+	fullRefetchInterval := m.Ctx.Config.Defaults.FullRefetchInterval()
+	if fullRefetchInterval == 0 || time.Since(m.LastFullFetchAt) >= fullRefetchInterval {
+		return "", false
+	}
+	if !data.GetCapabilities(m.Config.Provider).UpdatedSince {
+		return "", false
+	}
+
+	filters := m.GetFilters()
+	for _, filter := range providers.ParseSearchQuery(filters).Filters {
+		if filter.Key == "updated" || filter.Key == "sort" {
+			return "", false
+		}
+	}
+	since := m.LastFetchedAt.Add(-updatedSinceOverlap).UTC().Format(time.RFC3339)
+	return strings.TrimSpace(fmt.Sprintf("%s updated:>=%s", filters, since)), true
+}
+
+// StaleText describes the age of the rows while they come from the cache
+func (m *BaseModel) StaleText() string {
+	if m.CachedAt.IsZero() {
+		return ""
+	}
+	if elapsed := utils.TimeElapsed(m.CachedAt); elapsed != "now" {
+		return fmt.Sprintf("Cached ~%v ago", elapsed)
+	}
+	return "Cached just now"
+}
+
+// TotalCountText is the number of rows matching the section. When the provider
+// can't count them it is the number of rows fetched, followed by a +
+func (m *BaseModel) TotalCountText() string {
+	if m.TotalCount == providers.UnknownTotalCount {
+		return fmt.Sprintf("%d+", len(m.Table.Rows))
+	}
+	return fmt.Sprint(m.TotalCount)
+}
+
 func (m *BaseModel) CreatedAt() time.Time {
 	return m.Table.CreatedAt()
 }
@@ -434,6 +540,9 @@ func (m *BaseModel) GetPromptConfirmation() string {
 
 		case m.PromptConfirmationAction == "merge" && m.Ctx.View == config.PRsView:
 			prompt = "Are you sure you want to merge this PR? (Y/n) "
+			if strategy := data.GetMergeStrategy(m.Config.Provider); strategy != "" {
+				prompt = fmt.Sprintf("Are you sure you want to merge this PR with %s? (Y/n) ", strategy)
+			}
 
 		case m.PromptConfirmationAction == "update" && m.Ctx.View == config.PRsView:
 			prompt = "Are you sure you want to update this PR? (Y/n) "
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/section"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
 	"github.com/dlvhdr/gh-dash/v4/utils"
@@ -16,10 +17,20 @@ import (
 
 type SectionState struct {
 	Count     int
+	NumRows   int
 	IsLoading bool
 	spinner   spinner.Model
 }
 
+// countText is the count of the section, or the number of its rows followed by
+// a + when its provider can't count them
+func (s SectionState) countText() string {
+	if s.Count == providers.UnknownTotalCount {
+		return utils.ShortNumber(s.NumRows) + "+"
+	}
+	return utils.ShortNumber(s.Count)
+}
+
 type Model struct {
 	sectionsConfigs []config.SectionConfig
 	sectionCounts   []SectionState
@@ -57,7 +68,7 @@ func (m Model) View(ctx *context.ProgramContext) string {
 			if m.sectionCounts[i].IsLoading {
 				title = fmt.Sprintf("%s %s", title, m.sectionCounts[i].spinner.View())
 			} else {
-				title = fmt.Sprintf("%s (%s)", title, utils.ShortNumber(m.sectionCounts[i].Count))
+				title = fmt.Sprintf("%s (%s)", title, m.sectionCounts[i].countText())
 			}
 		}
 		sectionTitles = append(sectionTitles, title)
@@ -104,6 +115,7 @@ func (m *Model) UpdateSectionsConfigs(ctx *context.ProgramContext) {
 func (m *Model) UpdateSectionCounts(sections []section.Section) {
 	for i, s := range sections {
 		m.sectionCounts[i].Count = s.GetTotalCount()
+		m.sectionCounts[i].NumRows = s.NumRows()
 		m.sectionCounts[i].IsLoading = s.GetIsLoading()
 	}
 }
@@ -18,6 +18,8 @@ import (
 type SectionIdentifier struct {
 	Id   int
 	Type string
+	// Provider is the provider profile of the section, empty for the current provider
+	Provider string
 }
 
 type UpdatePRMsg struct {
@@ -47,10 +49,56 @@ type GitHubTask struct {
 	StartText    string
 	FinishedText string
 	Msg          func(c *exec.Cmd, err error) tea.Msg
+	// Action performs the task through the provider API when the provider supports it
+	Action func(actions providers.PullRequestActions) error
+}
+
+// providerActions returns the provider of the section when it acts on pull requests through its API
+func providerActions(section SectionIdentifier) (providers.PullRequestActions, bool) {
+	provider, err := data.GetProvider(section.Provider)
+	if err != nil {
+		log.Debug("Failed to get the provider of the section", "provider", section.Provider, "error", err)
+		return nil, false
+	}
+	actions, ok := provider.(providers.PullRequestActions)
+	return actions, ok
+}
+
+// fireProviderActionTask runs a task through the provider API. The row is
+// only updated when the action succeeded
+func fireProviderActionTask(ctx *context.ProgramContext, task GitHubTask, actions providers.PullRequestActions) tea.Cmd {
+	start := context.Task{
+		Id:           task.Id,
+		StartText:    task.StartText,
+		FinishedText: task.FinishedText,
+		State:        context.TaskStart,
+		Error:        nil,
+	}
+
+	startCmd := ctx.StartTask(start)
+	return tea.Batch(startCmd, func() tea.Msg {
+		log.Debug("Running provider action", "task", task.Id)
+		err := task.Action(actions)
+		var msg tea.Msg
+		if err == nil {
+			msg = task.Msg(nil, nil)
+		}
+		return constants.TaskFinishedMsg{
+			TaskId:      task.Id,
+			SectionId:   task.Section.Id,
+			SectionType: task.Section.Type,
+			Err:         err,
+			Msg:         msg,
+		}
+	})
 }
 
 // Helper function to execute provider-aware commands
 func fireProviderTask(ctx *context.ProgramContext, task GitHubTask, cmdFunc func(providers.GitProvider, int, string) ([]string, error), prNumber int, repoNameWithOwner string) tea.Cmd {
+	if actions, ok := providerActions(task.Section); ok && task.Action != nil {
+		return fireProviderActionTask(ctx, task, actions)
+	}
+
 	start := context.Task{
 		Id:           task.Id,
 		StartText:    task.StartText,
@@ -61,9 +109,17 @@ func fireProviderTask(ctx *context.ProgramContext, task GitHubTask, cmdFunc func
 
 	startCmd := ctx.StartTask(start)
 	return tea.Batch(startCmd, func() tea.Msg {
-		provider := data.GetCurrentProvider()
+		provider, err := data.GetProvider(task.Section.Provider)
+		if err != nil {
+			return constants.TaskFinishedMsg{
+				TaskId:      task.Id,
+				SectionId:   task.Section.Id,
+				SectionType: task.Section.Type,
+				Err:         err,
+				Msg:         task.Msg(nil, err),
+			}
+		}
 		var c *exec.Cmd
-		var err error
 		
 		if provider != nil {
 			// Use provider-specific command
@@ -178,6 +234,9 @@ func ReopenPR(ctx *context.ProgramContext, section SectionIdentifier, pr data.Ro
 				IsClosed: utils.BoolPtr(false),
 			}
 		},
+		Action: func(actions providers.PullRequestActions) error {
+			return actions.ReopenPullRequest(prNumber, pr.GetRepoNameWithOwner())
+		},
 	}, func(p providers.GitProvider, num int, repo string) ([]string, error) {
 		return p.GetReopenCommand(num, repo)
 	}, prNumber, pr.GetRepoNameWithOwner())
@@ -203,6 +262,9 @@ func ClosePR(ctx *context.ProgramContext, section SectionIdentifier, pr data.Row
 				IsClosed: utils.BoolPtr(true),
 			}
 		},
+		Action: func(actions providers.PullRequestActions) error {
+			return actions.ClosePullRequest(prNumber, pr.GetRepoNameWithOwner())
+		},
 	}, func(p providers.GitProvider, num int, repo string) ([]string, error) {
 		return p.GetCloseCommand(num, repo)
 	}, prNumber, pr.GetRepoNameWithOwner())
@@ -228,6 +290,9 @@ func PRReady(ctx *context.ProgramContext, section SectionIdentifier, pr data.Row
 				ReadyForReview: utils.BoolPtr(true),
 			}
 		},
+		Action: func(actions providers.PullRequestActions) error {
+			return actions.MarkPullRequestReady(prNumber, pr.GetRepoNameWithOwner())
+		},
 	}, func(p providers.GitProvider, num int, repo string) ([]string, error) {
 		return p.GetReadyCommand(num, repo)
 	}, prNumber, pr.GetRepoNameWithOwner())
@@ -235,13 +300,31 @@ func PRReady(ctx *context.ProgramContext, section SectionIdentifier, pr data.Row
 
 func MergePR(ctx *context.ProgramContext, section SectionIdentifier, pr data.RowData) tea.Cmd {
 	prNumber := pr.GetNumber()
+	if actions, ok := providerActions(section); ok {
+		return fireProviderActionTask(ctx, GitHubTask{
+			Id:           fmt.Sprintf("merge_%d", prNumber),
+			Section:      section,
+			StartText:    fmt.Sprintf("Merging PR #%d", prNumber),
+			FinishedText: fmt.Sprintf("PR #%d has been merged", prNumber),
+			Msg: func(c *exec.Cmd, err error) tea.Msg {
+				return UpdatePRMsg{
+					PrNumber: prNumber,
+					IsMerged: utils.BoolPtr(true),
+				}
+			},
+			Action: func(actions providers.PullRequestActions) error {
+				return actions.MergePullRequest(prNumber, pr.GetRepoNameWithOwner())
+			},
+		}, actions)
+	}
+
 	c := exec.Command(
 		"gh",
 		"pr",
 		"merge",
 		fmt.Sprint(prNumber),
 		"-R",
-		pr.GetRepoNameWithOwner(),
+		data.GitHubRepoArg(section.Provider, pr.GetRepoNameWithOwner()),
 	)
 
 	taskId := fmt.Sprintf("merge_%d", prNumber)
@@ -330,6 +413,13 @@ func UpdatePR(ctx *context.ProgramContext, section SectionIdentifier, pr data.Ro
 				IsClosed: utils.BoolPtr(true),
 			}
 		},
+		Action: func(actions providers.PullRequestActions) error {
+			updater, ok := actions.(providers.BranchUpdater)
+			if !ok {
+				return fmt.Errorf("updating the branch of PR #%d isn't supported by this provider", prNumber)
+			}
+			return updater.UpdatePullRequestBranch(prNumber, pr.GetRepoNameWithOwner())
+		},
 	}, func(p providers.GitProvider, num int, repo string) ([]string, error) {
 		return p.GetUpdateCommand(num, repo)
 	}, prNumber, pr.GetRepoNameWithOwner())
@@ -29,6 +29,7 @@ const (
 	PersonIcon  = ""
 	WaitingIcon = ""
 	FailureIcon = "󰅙"
+	WarningIcon = ""
 	SuccessIcon = ""
 
 	CommentIcon = ""
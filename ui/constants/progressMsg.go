@@ -7,7 +7,9 @@ type TaskFinishedMsg struct {
 	SectionId   int
 	SectionType string
 	Err         error
-	Msg         tea.Msg
+	// Warning replaces the task's finished text when the task succeeded only partially
+	Warning string
+	Msg     tea.Msg
 }
 
 type ClearTaskMsg struct {
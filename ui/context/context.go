@@ -16,6 +16,7 @@ const (
 	TaskStart State = iota
 	TaskFinished
 	TaskError
+	TaskWarning
 )
 
 type Task struct {
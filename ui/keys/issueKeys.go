@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 	log "github.com/charmbracelet/log"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 )
 
 type IssueKeyMap struct {
@@ -62,6 +64,23 @@ func IssueFullHelp() []key.Binding {
 	}
 }
 
+// issueCapabilityKeys pairs the keys of issue actions with whether the provider can perform them
+func issueCapabilityKeys(caps providers.Capabilities) []capabilityKey {
+	return []capabilityKey{
+		{&IssueKeys.Assign, caps.Assignees},
+		{&IssueKeys.Unassign, caps.Assignees},
+		{&IssueKeys.Comment, caps.Comments},
+		{&IssueKeys.Close, caps.IssueClose},
+		{&IssueKeys.Reopen, caps.IssueClose},
+	}
+}
+
+// UnsupportedIssueAction returns the description of the issue action bound to
+// the key when the provider can't perform it
+func UnsupportedIssueAction(msg tea.KeyMsg, caps providers.Capabilities) (string, bool) {
+	return unsupportedAction(msg, issueCapabilityKeys(caps))
+}
+
 func rebindIssueKeys(keys []config.Keybinding) error {
 	CustomIssueBindings = []key.Binding{}
 
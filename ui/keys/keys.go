@@ -8,10 +8,12 @@ import (
 	log "github.com/charmbracelet/log"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 )
 
 type KeyMap struct {
 	viewType      config.ViewType
+	capabilities  providers.Capabilities
 	Up            key.Binding
 	Down          key.Binding
 	FirstLine     key.Binding
@@ -32,8 +34,11 @@ type KeyMap struct {
 	Quit          key.Binding
 }
 
-func CreateKeyMapForView(viewType config.ViewType) help.KeyMap {
+// CreateKeyMapForView returns the keys of the view, with the actions the
+// provider of the current section can't perform marked as unsupported
+func CreateKeyMapForView(viewType config.ViewType, capabilities providers.Capabilities) help.KeyMap {
 	Keys.viewType = viewType
+	Keys.capabilities = capabilities
 	return Keys
 }
 
@@ -50,13 +55,13 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	}
 
 	if k.viewType == config.PRsView {
-		additionalKeys = PRFullHelp()
+		additionalKeys = markUnsupportedKeys(PRFullHelp(), prCapabilityKeys(k.capabilities))
 		customKeys = append(customKeys, CustomPRBindings...)
 	} else if k.viewType == config.RepoView {
 		additionalKeys = BranchFullHelp()
 		customKeys = append(customKeys, CustomBranchBindings...)
 	} else {
-		additionalKeys = IssueFullHelp()
+		additionalKeys = markUnsupportedKeys(IssueFullHelp(), issueCapabilityKeys(k.capabilities))
 		customKeys = append(customKeys, CustomIssueBindings...)
 	}
 
@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 	log "github.com/charmbracelet/log"
 
 	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 )
 
 type PRKeyMap struct {
@@ -121,6 +123,59 @@ func PRFullHelp() []key.Binding {
 	}
 }
 
+type capabilityKey struct {
+	binding   *key.Binding
+	supported bool
+}
+
+// prCapabilityKeys pairs the keys of PR actions with whether the provider can perform them
+func prCapabilityKeys(caps providers.Capabilities) []capabilityKey {
+	return []capabilityKey{
+		{&PRKeys.Approve, caps.Reviews},
+		{&PRKeys.Assign, caps.Assignees},
+		{&PRKeys.Unassign, caps.Assignees},
+		{&PRKeys.Comment, caps.Comments},
+		{&PRKeys.Checkout, caps.Checkout},
+		{&PRKeys.Close, caps.Close},
+		{&PRKeys.Reopen, caps.Close},
+		{&PRKeys.Ready, caps.DraftToggle},
+		{&PRKeys.Merge, caps.CanMerge()},
+		{&PRKeys.Update, caps.UpdateBranch},
+		{&PRKeys.WatchChecks, caps.WatchChecks},
+	}
+}
+
+// UnsupportedPRAction returns the description of the PR action bound to the
+// key when the provider can't perform it
+func UnsupportedPRAction(msg tea.KeyMsg, caps providers.Capabilities) (string, bool) {
+	return unsupportedAction(msg, prCapabilityKeys(caps))
+}
+
+func unsupportedAction(msg tea.KeyMsg, capabilityKeys []capabilityKey) (string, bool) {
+	for _, k := range capabilityKeys {
+		if !k.supported && key.Matches(msg, *k.binding) {
+			return k.binding.Help().Desc, true
+		}
+	}
+	return "", false
+}
+
+// markUnsupportedKeys marks the help of the actions the provider can't perform
+func markUnsupportedKeys(bindings []key.Binding, capabilityKeys []capabilityKey) []key.Binding {
+	unsupported := make(map[string]bool)
+	for _, k := range capabilityKeys {
+		if !k.supported {
+			unsupported[k.binding.Help().Key] = true
+		}
+	}
+	for i, binding := range bindings {
+		if help := binding.Help(); unsupported[help.Key] {
+			bindings[i].SetHelp(help.Key, help.Desc+" (unsupported)")
+		}
+	}
+	return bindings
+}
+
 func rebindPRKeys(keys []config.Keybinding) error {
 	CustomPRBindings = []key.Binding{}
 
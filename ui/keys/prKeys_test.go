@@ -0,0 +1,65 @@
+package keys_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dlvhdr/gh-dash/v4/config"
+	"github.com/dlvhdr/gh-dash/v4/providers"
+	"github.com/dlvhdr/gh-dash/v4/ui/keys"
+)
+
+func TestUnsupportedPRAction(t *testing.T) {
+	merge := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")}
+	diff := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")}
+
+	action, unsupported := keys.UnsupportedPRAction(merge, providers.Capabilities{})
+	require.True(t, unsupported)
+	require.Equal(t, "merge", action)
+
+	_, unsupported = keys.UnsupportedPRAction(merge, providers.GitHubCapabilities())
+	require.False(t, unsupported)
+
+	_, unsupported = keys.UnsupportedPRAction(diff, providers.Capabilities{})
+	require.False(t, unsupported)
+}
+
+func TestPRHelpMarksUnsupportedActions(t *testing.T) {
+	keymap := keys.CreateKeyMapForView(config.PRsView, providers.Capabilities{MergeStrategies: []string{"merge"}})
+
+	descs := make(map[string]string)
+	for _, column := range keymap.FullHelp() {
+		for _, binding := range column {
+			descs[binding.Help().Key] = binding.Help().Desc
+		}
+	}
+	require.Equal(t, "merge", descs["m"])
+	require.Equal(t, "watch checks (unsupported)", descs["w"])
+	require.Equal(t, "diff", descs["d"])
+	require.Equal(t, "watch checks", keys.PRKeys.WatchChecks.Help().Desc)
+}
+
+func TestUnsupportedIssueAction(t *testing.T) {
+	closeIssue := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}
+	comment := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}
+	caps := providers.Capabilities{IssueClose: true}
+
+	_, unsupported := keys.UnsupportedIssueAction(closeIssue, caps)
+	require.False(t, unsupported)
+
+	action, unsupported := keys.UnsupportedIssueAction(comment, caps)
+	require.True(t, unsupported)
+	require.Equal(t, "comment", action)
+
+	keymap := keys.CreateKeyMapForView(config.IssuesView, caps)
+	descs := make(map[string]string)
+	for _, column := range keymap.FullHelp() {
+		for _, binding := range column {
+			descs[binding.Help().Key] = binding.Help().Desc
+		}
+	}
+	require.Equal(t, "close", descs["x"])
+	require.Equal(t, "comment (unsupported)", descs["c"])
+}
@@ -17,10 +17,12 @@ import (
 
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/data"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/common"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/section"
 	"github.com/dlvhdr/gh-dash/v4/ui/constants"
 	"github.com/dlvhdr/gh-dash/v4/ui/context"
+	"github.com/dlvhdr/gh-dash/v4/ui/keys"
 	"github.com/dlvhdr/gh-dash/v4/ui/markdown"
 )
 
@@ -32,6 +34,31 @@ func (m *Model) getCurrSection() section.Section {
 	return sections[m.currSectionId]
 }
 
+// isUnsupportedAction refuses PR and issue actions the provider of the section
+// can't perform, explaining why in the footer
+func (m *Model) isUnsupportedAction(msg tea.KeyMsg, currSection section.Section) bool {
+	caps := data.GetCapabilities(currSection.GetProvider())
+	var action, kind string
+	var unsupported bool
+	switch m.ctx.View {
+	case config.PRsView:
+		action, unsupported = keys.UnsupportedPRAction(msg, caps)
+		kind = "pull requests"
+	case config.IssuesView:
+		action, unsupported = keys.UnsupportedIssueAction(msg, caps)
+		kind = "issues"
+	}
+	if !unsupported {
+		return false
+	}
+	providerType := providers.GitHub
+	if provider, err := data.GetProvider(currSection.GetProvider()); err == nil && provider != nil {
+		providerType = provider.GetType()
+	}
+	m.ctx.Error = fmt.Errorf("%s isn't supported for %s %s, open it in the browser instead", action, providerType, kind)
+	return true
+}
+
 func (m *Model) getCurrRowData() data.RowData {
 	section := m.getCurrSection()
 	if section == nil {
@@ -21,6 +21,7 @@ import (
 	"github.com/dlvhdr/gh-dash/v4/config"
 	"github.com/dlvhdr/gh-dash/v4/data"
 	"github.com/dlvhdr/gh-dash/v4/git"
+	"github.com/dlvhdr/gh-dash/v4/providers"
 	"github.com/dlvhdr/gh-dash/v4/ui/common"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/branch"
 	"github.com/dlvhdr/gh-dash/v4/ui/components/branchsidebar"
@@ -128,18 +129,19 @@ func (m *Model) initScreen() tea.Msg {
 		return initMsg{Config: cfg}
 	}
 
-	// Initialize providers after config is loaded
-	if err := data.InitProviders(&cfg, m.ctx.RepoPath); err != nil {
-		log.Debug("Failed to initialize providers", "error", err)
-		// Don't fail here, just log the error and continue with GitHub default
+	// Initialize providers after config is loaded. Sections whose provider
+	// failed report it themselves, so keep going and surface the error.
+	providersErr := data.InitProviders(&cfg, m.ctx.RepoPath)
+	if providersErr != nil {
+		log.Error("Failed to initialize providers", "error", providersErr)
 	}
 
 	var url string
 	if config.IsFeatureEnabled(config.FF_REPO_VIEW) && m.ctx.RepoPath != "" {
-		res, err := git.GetOriginUrl(m.ctx.RepoPath)
+		res, err := git.GetRemoteUrl(m.ctx.RepoPath, cfg.Repo.Remotes)
 		if err != nil {
 			showError(err)
-			return initMsg{Config: cfg}
+			return initMsg{Config: cfg, Err: providersErr}
 		}
 		url = res
 	}
@@ -154,7 +156,7 @@ func (m *Model) initScreen() tea.Msg {
 		showError(err)
 	}
 
-	return initMsg{Config: cfg, RepoUrl: url}
+	return initMsg{Config: cfg, RepoUrl: url, Err: providersErr}
 }
 
 func (m Model) Init() tea.Cmd {
@@ -201,11 +203,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.executeKeybinding(msg.String())
 			return m, cmd
 
+		case currSection != nil && m.isUnsupportedAction(msg, currSection):
+			return m, nil
+
 		case key.Matches(msg, m.keys.PrevSection):
 			prevSection := m.getSectionAt(m.getPrevSectionId())
 			if prevSection != nil {
 				m.setCurrSectionId(prevSection.GetId())
-				m.onViewedRowChanged()
+				cmd = m.onViewedRowChanged()
 			}
 
 		case key.Matches(msg, m.keys.NextSection):
@@ -213,7 +218,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			nextSection := m.getSectionAt(nextSectionId)
 			if nextSection != nil {
 				m.setCurrSectionId(nextSection.GetId())
-				m.onViewedRowChanged()
+				cmd = m.onViewedRowChanged()
 			}
 
 		case key.Matches(msg, m.keys.Down):
@@ -226,7 +231,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Up):
 			currSection.PrevRow()
-			m.onViewedRowChanged()
+			cmd = m.onViewedRowChanged()
 
 		case key.Matches(msg, m.keys.FirstLine):
 			currSection.FirstItem()
@@ -242,6 +247,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.TogglePreview):
 			m.sidebar.IsOpen = !m.sidebar.IsOpen
 			m.syncMainContentWidth()
+			cmd = m.syncSidebar()
 
 		case key.Matches(msg, m.keys.Refresh):
 			currSection.ResetFilters()
@@ -517,6 +523,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case initMsg:
 		m.ctx.Config = &msg.Config
 		m.ctx.RepoUrl = msg.RepoUrl
+		m.ctx.Error = msg.Err
 		m.ctx.Theme = theme.ParseTheme(m.ctx.Config)
 		m.ctx.Styles = context.InitStyles(m.ctx.Theme)
 		m.ctx.View = m.ctx.Config.Defaults.View
@@ -529,9 +536,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, fetchSectionsCmds, fetchUser, m.doRefreshAtInterval(), m.doUpdateFooterAtInterval())
 
 	case intervalRefresh:
-		newSections, fetchSectionsCmds := m.fetchAllViewSections()
-		m.setCurrentViewSections(newSections)
-		cmds = append(cmds, fetchSectionsCmds, m.doRefreshAtInterval())
+		if rateLimit, ok := m.lowRateLimit(); ok {
+			log.Info("Skipping refresh, the API rate limit is running low", "remaining", rateLimit.Remaining, "resetAt", rateLimit.ResetAt)
+			cmds = append(cmds, m.doRefreshAtInterval())
+		} else {
+			newSections, fetchSectionsCmds := m.fetchAllViewSections()
+			m.setCurrentViewSections(newSections)
+			cmds = append(cmds, fetchSectionsCmds, m.doRefreshAtInterval())
+		}
 
 	case userFetchedMsg:
 		m.ctx.User = msg.user
@@ -544,13 +556,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				log.Error("Task finished with error", "id", task.Id, "err", msg.Err)
 				task.State = context.TaskError
 				task.Error = msg.Err
+			} else if msg.Warning != "" {
+				log.Warn("Task finished with warning", "id", task.Id, "warning", msg.Warning)
+				task.State = context.TaskWarning
+				task.FinishedText = msg.Warning
 			} else {
 				task.State = context.TaskFinished
 			}
 			now := time.Now()
 			task.FinishedTime = &now
 			m.tasks[msg.TaskId] = task
-			clear := tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			// Leave warnings up long enough to be read
+			clearAfter := 2 * time.Second
+			if task.State == context.TaskWarning {
+				clearAfter = 6 * time.Second
+			}
+			clear := tea.Tick(clearAfter, func(t time.Time) tea.Msg {
 				return constants.ClearTaskMsg{TaskId: msg.TaskId}
 			})
 			cmds = append(cmds, clear)
@@ -633,6 +654,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	m.footer, footerCmd = m.footer.Update(msg)
+	if section := m.getCurrSection(); section != nil {
+		m.footer.SetCapabilities(data.GetCapabilities(section.GetProvider()))
+		m.footer.SetRateLimit(data.GetRateLimit(section.GetProvider()))
+	}
 	if currSection != nil {
 		if currSection.IsPromptConfirmationFocused() {
 			m.footer.SetLeftSection(currSection.GetPromptConfirmation())
@@ -703,6 +728,7 @@ func (m Model) View() string {
 type initMsg struct {
 	Config  config.Config
 	RepoUrl string
+	Err     error
 }
 
 func (m *Model) setCurrSectionId(newSectionId int) {
@@ -793,12 +819,17 @@ func (m *Model) syncSidebar() tea.Cmd {
 		cmd = m.branchSidebar.SetRow(&row)
 		m.sidebar.SetContent(m.branchSidebar.View())
 	case *data.PullRequestData:
+		if prs, ok := m.getCurrSection().(*prssection.Model); ok && m.sidebar.IsOpen {
+			cmd = prs.FetchCurrRowDetails()
+		}
 		m.prSidebar.SetSectionId(m.currSectionId)
+		m.prSidebar.SetProvider(m.getCurrSection().GetProvider())
 		m.prSidebar.SetRow(row)
 		m.prSidebar.SetWidth(width)
 		m.sidebar.SetContent(m.prSidebar.View())
 	case *data.IssueData:
 		m.issueSidebar.SetSectionId(m.currSectionId)
+		m.issueSidebar.SetProvider(m.getCurrSection().GetProvider())
 		m.issueSidebar.SetRow(row)
 		m.issueSidebar.SetWidth(width)
 		m.sidebar.SetContent(m.issueSidebar.View())
@@ -822,7 +853,7 @@ func (m *Model) fetchAllViewSections() ([]section.Section, tea.Cmd) {
 		cmds = append(cmds, prcmds)
 		return s, tea.Batch(cmds...)
 	} else {
-		s, issuecmds := issuessection.FetchAllSections(m.ctx)
+		s, issuecmds := issuessection.FetchAllSections(m.ctx, m.issues)
 		cmds = append(cmds, issuecmds)
 		return s, tea.Batch(cmds...)
 	}
@@ -976,6 +1007,11 @@ func (m *Model) renderRunningTask() string {
 			Foreground(m.ctx.Theme.SuccessText).
 			Background(m.ctx.Theme.SelectedBackground).
 			Render(fmt.Sprintf("%s %s", constants.SuccessIcon, task.FinishedText))
+	case context.TaskWarning:
+		currTaskStatus = lipgloss.NewStyle().
+			Foreground(m.ctx.Theme.WarningText).
+			Background(m.ctx.Theme.SelectedBackground).
+			Render(fmt.Sprintf("%s %s", constants.WarningIcon, task.FinishedText))
 	}
 
 	var numProcessing int
@@ -1032,6 +1068,21 @@ func (m *Model) doRefreshAtInterval() tea.Cmd {
 	)
 }
 
+// lowRateLimit returns the rate limit of a provider of the current view that is
+// running low. Interval refreshes pause until it resets, to leave the budget
+// for the sections the user refreshes
+func (m *Model) lowRateLimit() (providers.RateLimit, bool) {
+	for _, section := range m.getCurrentViewSections() {
+		if section == nil {
+			continue
+		}
+		if rateLimit, ok := data.GetRateLimit(section.GetProvider()); ok && rateLimit.IsLow() {
+			return rateLimit, true
+		}
+	}
+	return providers.RateLimit{}, false
+}
+
 type updateFooterMsg struct{}
 
 func (m *Model) doUpdateFooterAtInterval() tea.Cmd {